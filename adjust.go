@@ -0,0 +1,60 @@
+package main
+
+import "time"
+
+// adjustRepeatWindow is the longest gap between presses of the same adjust
+// key that still counts as holding it down. Terminals repeat a held key
+// every 30-100ms.
+const adjustRepeatWindow = 400 * time.Millisecond
+
+// adjustSteps are the step sizes used while an adjust key is held, each
+// taking over after the given number of repeats, so long cold steeps don't
+// take a hundred key presses to set.
+var adjustSteps = []struct {
+	after int
+	step  time.Duration
+}{
+	{0, 15 * time.Second},
+	{8, 30 * time.Second},
+	{16, time.Minute},
+}
+
+// adjustStep returns the step size after repeats repeated presses.
+func adjustStep(repeats int) time.Duration {
+	step := adjustSteps[0].step
+	for _, s := range adjustSteps {
+		if repeats >= s.after {
+			step = s.step
+		}
+	}
+	return step
+}
+
+// adjustDuration lengthens (dir 1) or shortens (dir -1) the next brew by one
+// step, snapping to a multiple of the step and staying within the supported
+// brew time range. The step grows while the key is held down.
+func (m model) adjustDuration(key string, dir int) model {
+	t := now()
+	if key == m.adjustKey && t.Sub(m.adjustAt) <= adjustRepeatWindow {
+		m.adjustRepeats++
+	} else {
+		// A new run of presses can be undone as a whole
+		m = m.saveUndo("duration change")
+		m.adjustRepeats = 0
+	}
+	m.adjustKey, m.adjustAt = key, t
+
+	step := adjustStep(m.adjustRepeats)
+	d := m.brewDuration()
+	if dir > 0 {
+		d = (d/step + 1) * step
+	} else {
+		d = ((d+step-1)/step - 1) * step
+	}
+	d = min(max(d, MinBrewTime), MaxBrewTime)
+
+	m = m.clearCustom()
+	m.customDuration = d
+	m.timer = d
+	return m
+}
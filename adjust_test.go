@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAdjustDuration(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := now()
+	now = func() time.Time { return clock }
+
+	press := func(m model, key string) model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(model)
+	}
+
+	// Separate presses step by 15 seconds from the preset's 4 minutes
+	m := initialModel(NewConfig())
+	m = press(m, KeyLonger)
+	clock = clock.Add(time.Second)
+	m = press(m, KeyLonger)
+	if m.timer != 4*time.Minute+30*time.Second || m.customDuration != m.timer {
+		t.Fatalf("Expected 4m30s after two presses, got %v", m.timer)
+	}
+	clock = clock.Add(time.Second)
+	m = press(m, KeyShorter)
+	if m.timer != 4*time.Minute+15*time.Second {
+		t.Errorf("Expected 4m15s after shortening, got %v", m.timer)
+	}
+
+	// Holding the key speeds up to 30 seconds and then a minute per repeat
+	clock = clock.Add(time.Second)
+	for i := 0; i < 20; i++ {
+		m = press(m, KeyLonger)
+		clock = clock.Add(50 * time.Millisecond)
+	}
+	// 8 × 15s from 4m15s to 6m15s, 8 × 30s snapping to 6m30s and on to
+	// 10m, then 4 × 1m
+	if want := 14 * time.Minute; m.timer != want {
+		t.Errorf("Expected %v after holding the key, got %v", want, m.timer)
+	}
+
+	// The duration stays within the supported range
+	for i := 0; i < 40; i++ {
+		m = press(m, KeyLonger)
+	}
+	if m.timer != MaxBrewTime {
+		t.Errorf("Expected the duration to stop at %v, got %v", MaxBrewTime, m.timer)
+	}
+
+	// A whole held run is undone at once
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyUndo)})
+	if m = newModel.(model); m.timer != 4*time.Minute+15*time.Second {
+		t.Errorf("Expected undo to restore 4m15s, got %v", m.timer)
+	}
+}
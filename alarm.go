@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alarmLayouts are the accepted ways of writing a wall-clock alarm time.
+var alarmLayouts = []string{"15:04", "15:04:05", "3:04pm", "3:04:05pm", "3pm"}
+
+// startMsg starts the timer without a key press, for alarms given with -at.
+type startMsg struct{}
+
+// parseAlarmTime parses a wall-clock time such as "14:45" or "2:45pm" and
+// returns its next occurrence after now: today if it is still ahead,
+// otherwise tomorrow. An IANA time zone may follow the time, as in
+// "14:45 Europe/London", to count down to that zone's clock rather than
+// the local one.
+//
+// The day is stepped on the calendar rather than by 24 hours, so across a
+// daylight saving change the alarm still goes off at the wall-clock time
+// given. A time that the change skips, such as 02:30 on the night clocks go
+// forward, falls an hour later, as time.Date normalises it.
+func parseAlarmTime(s string, now time.Time) (time.Time, error) {
+	clock, zone, hasZone := strings.Cut(strings.TrimSpace(s), " ")
+	loc := now.Location()
+	if hasZone {
+		var err error
+		if loc, err = time.LoadLocation(strings.TrimSpace(zone)); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone %q (use names like Europe/London or UTC)", strings.TrimSpace(zone))
+		}
+		now = now.In(loc)
+	}
+	for _, layout := range alarmLayouts {
+		t, err := time.ParseInLocation(layout, strings.ToLower(clock), loc)
+		if err != nil {
+			continue
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		if !at.After(now) {
+			at = time.Date(now.Year(), now.Month(), now.Day()+1, t.Hour(), t.Minute(), t.Second(), 0, loc)
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use values like 14:45, 2:45pm or 14:45 Europe/London)", s)
+}
+
+// alarmClock formats the alarm time for display. An alarm set in another
+// time zone carries that zone's abbreviation, as its clock time would
+// otherwise read as local.
+func (m model) alarmClock() string {
+	label := m.config.Formats.clock(m.alarmAt)
+	if m.alarmAt.Location() != m.clock.Location() {
+		label += " " + m.alarmAt.Format("MST")
+	}
+	return label
+}
+
+// untilAlarm returns the time left until at, rounded up to whole seconds so
+// the countdown reaches zero at the alarm time rather than just before it.
+func untilAlarm(at, now time.Time) time.Duration {
+	d := at.Sub(now)
+	if r := d % time.Second; r > 0 {
+		d += time.Second - r
+	}
+	return d
+}
+
+// setAlarm makes the next brew count down to the wall-clock time at.
+func (m model) setAlarm(at, now time.Time) model {
+	m.alarmAt = at
+	m.customDuration = untilAlarm(at, now)
+	if m.state == StateIdle && !m.stopwatch {
+		m.timer = m.customDuration
+	}
+	return m
+}
+
+// clearCustom drops a typed duration or alarm so the selected preset's
+// duration applies again.
+func (m model) clearCustom() model {
+	m.customDuration = 0
+	m.alarmAt = time.Time{}
+	return m
+}
+
+// startAlarm refreshes the countdown from the alarm time, which may have
+// been set a while ago, and starts it. An alarm time that has already passed
+// is reported instead.
+func (m model) startAlarm() (model, tea.Cmd) {
+	d := untilAlarm(m.alarmAt, now())
+	if d <= 0 {
+		return m.showStatus(fmt.Sprintf("Alarm time %s has passed", m.alarmClock()))
+	}
+	m.customDuration = d
+	return m.start()
+}
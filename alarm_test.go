@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseAlarmTime(t *testing.T) {
+	now := time.Date(2024, 5, 10, 13, 30, 0, 0, time.Local)
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"14:45", time.Date(2024, 5, 10, 14, 45, 0, 0, time.Local)},
+		{"14:45:30", time.Date(2024, 5, 10, 14, 45, 30, 0, time.Local)},
+		{"2:45pm", time.Date(2024, 5, 10, 14, 45, 0, 0, time.Local)},
+		{" 3PM ", time.Date(2024, 5, 10, 15, 0, 0, 0, time.Local)},
+		// Times that have passed today mean tomorrow
+		{"09:00", time.Date(2024, 5, 11, 9, 0, 0, 0, time.Local)},
+		{"13:30", time.Date(2024, 5, 11, 13, 30, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseAlarmTime(tt.input, now)
+		if err != nil {
+			t.Errorf("parseAlarmTime(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseAlarmTime(%q): expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "25:00", "14.45", "3m"} {
+		if _, err := parseAlarmTime(input, now); err == nil {
+			t.Errorf("parseAlarmTime(%q): expected an error", input)
+		}
+	}
+}
+
+func TestUntilAlarmRoundsUp(t *testing.T) {
+	now := time.Date(2024, 5, 10, 13, 30, 0, 0, time.UTC)
+	at := now.Add(90*time.Second + 200*time.Millisecond)
+	if got := untilAlarm(at, now); got != 91*time.Second {
+		t.Errorf("Expected 1m31s, got %v", got)
+	}
+}
+
+func TestAlarmFromDurationInput(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	at := now().Add(2 * time.Hour)
+
+	m, _ := mdl.openInput(inputDuration, "Duration: ", "")
+	m.input.SetValue(at.Format("15:04"))
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	if m.alarmAt.IsZero() || m.alarmAt.Format("15:04") != at.Format("15:04") {
+		t.Fatalf("Expected alarm at %s, got %v", at.Format("15:04"), m.alarmAt)
+	}
+	if m.timer < 119*time.Minute || m.timer > 2*time.Hour {
+		t.Errorf("Expected about two hours on the timer, got %v", m.timer)
+	}
+
+	// Alarms start without asking for a tea name
+	newModel, _ = m.Update(startMsg{})
+	m = newModel.(model)
+	if !m.isBrewing() || m.brewTea != "Alarm "+at.Format("15:04") {
+		t.Errorf("Expected alarm brew to start, got %q in state %v", m.brewTea, m.state)
+	}
+
+	// Choosing a preset cancels the alarm
+	m = m.stopTicking()
+	m.state = StateIdle
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m = newModel.(model); !m.alarmAt.IsZero() || m.timer != config.Presets[1].Duration {
+		t.Errorf("Expected preset selection to clear the alarm, got %v", m.alarmAt)
+	}
+}
+
+func TestParseAlarmTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("No time zone database: %v", err)
+	}
+	now := time.Date(2024, 11, 2, 13, 0, 0, 0, time.UTC) // 09:00 in New York, daylight time
+
+	at, err := parseAlarmTime("10:30 America/New_York", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 11, 2, 10, 30, 0, 0, newYork); !at.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, at)
+	}
+
+	// Tomorrow is a day on the calendar: clocks go back overnight, so the
+	// alarm is 25 hours away rather than 24
+	at, err = parseAlarmTime("9am America/New_York", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 11, 3, 9, 0, 0, 0, newYork); !at.Equal(want) || at.Sub(now) != 25*time.Hour {
+		t.Errorf("Expected %v, 25h away, got %v (%v)", want, at, at.Sub(now))
+	}
+
+	if _, err := parseAlarmTime("14:45 Mars/Olympus_Mons", now); err == nil || !strings.Contains(err.Error(), "time zone") {
+		t.Errorf("Expected an unknown time zone to be rejected, got %v", err)
+	}
+}
+
+func TestAlarmClockShowsZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("No time zone database: %v", err)
+	}
+	m := initialModel(NewConfig())
+	m.alarmAt = now().Add(time.Hour)
+	if got := m.alarmClock(); got != "15:00" {
+		t.Errorf("Expected a local alarm without a zone, got %q", got)
+	}
+	m.alarmAt = m.alarmAt.In(tokyo)
+	if got := m.alarmClock(); got != "00:00 JST" {
+		t.Errorf("Expected the alarm's zone to be named, got %q", got)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertRetries is how many times a failed notification or light alert is
+// tried again before the failure is reported. Right after login D-Bus may
+// not be up yet, and a light on Wi-Fi can miss a request.
+const alertRetries = 3
+
+// alertBackoff is the wait before the first retry. It doubles for each
+// further retry, so the last one comes 7 seconds after the first try.
+var alertBackoff = time.Second
+
+// Urgency levels of desktop notifications on Linux. Critical notifications
+// stay up until dismissed on most desktops.
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// notifyOptions are the platform-specific details of a desktop
+// notification. Each platform uses the ones it supports.
+type notifyOptions struct {
+	Sound   bool          // Play the system's own notification sound
+	Urgency string        // UrgencyLow, UrgencyNormal or UrgencyCritical
+	Expire  time.Duration // How long the notification stays up, 0 for the default
+	Collect bool          // Offer a button that collects the finished brew (Linux)
+}
+
+// notifyOptions returns the notification details set in the configuration.
+// Notifications sent during quiet hours make no sound.
+func (c *Config) notifyOptions() notifyOptions {
+	return notifyOptions{Sound: c.NotificationSound && !c.quiet(), Urgency: c.NotifyUrgency, Expire: c.NotifyExpire}
+}
+
+// sendNotification shows a desktop notification; tests replace it.
+var sendNotification = notify
+
+//go:embed icon.png
+var iconPNG []byte
+
+// notifyIcon extracts the embedded teapot icon that every notification
+// backend shows into the cache directory and returns its path, since the
+// notification services take a file rather than image data. It is written
+// once per run; if that fails the icon is left out and the notification
+// sent all the same.
+var notifyIcon = sync.OnceValue(func() string {
+	icon := filepath.Join(dirs().Cache, "icon.png")
+	if err := os.MkdirAll(filepath.Dir(icon), 0o755); err != nil {
+		log.Printf("Saving the notification icon failed: %v", err)
+		return ""
+	}
+	if err := os.WriteFile(icon, iconPNG, 0o644); err != nil {
+		log.Printf("Saving the notification icon failed: %v", err)
+		return ""
+	}
+	return icon
+})
+
+// notifyResultMsg reports the outcome of the desktop notification sent when
+// a brew finishes. A nil err means the notification was delivered.
+type notifyResultMsg struct {
+	err error
+}
+
+// soundResultMsg reports the outcome of the alert sound played when a brew
+// finishes. A nil err means the sound played or was deliberately stopped.
+type soundResultMsg struct {
+	err error
+}
+
+// alertCmd returns the commands announcing a finished brew: a desktop
+// notification with the given body, an alert sound (on a network speaker if
+// one is configured), GPIO pulses and a blinking smart light, each when
+// enabled and routed for the finish in the configuration. During quiet hours
+// the sound is left out.
+// They run concurrently and report back through result messages so failures
+// can be shown in the UI.
+func alertCmd(ctx context.Context, config *Config, body string) tea.Cmd {
+	var cmds []tea.Cmd
+	if config.routed(EventFinish, ChannelDesktop) {
+		opts := config.notifyOptions()
+		opts.Collect = config.acknowledging()
+		cmds = append(cmds, notifyCmd(ctx, body, opts))
+	}
+	sound := config.routed(EventFinish, ChannelSound)
+	if sound && config.Speaker != "" {
+		cmds = append(cmds, castCmd(ctx, config.Speaker))
+	} else if sound {
+		cmds = append(cmds, soundCmd(ctx))
+	}
+	if config.routed(EventFinish, ChannelGPIO) {
+		cmds = append(cmds, gpioCmd(ctx, config.GPIOPin))
+	}
+	if config.routed(EventFinish, ChannelLight) {
+		cmds = append(cmds, lightCmd(ctx, config.Light))
+	}
+	return tea.Batch(cmds...)
+}
+
+// notifyCmd sends the "tea is ready" desktop notification, retrying until
+// it is delivered, the retries run out or ctx is cancelled.
+func notifyCmd(ctx context.Context, body string, opts notifyOptions) tea.Cmd {
+	return func() tea.Msg {
+		return notifyResultMsg{err: retryAlert(ctx, "notification", func() error {
+			return sendNotification("Go Brew Timer", body, opts)
+		})}
+	}
+}
+
+// retryAlert calls send and, while it fails, calls it again up to
+// alertRetries times with exponential backoff. Retries are only logged; the
+// returned error is the last failure, for the caller to show in the UI. A
+// cancelled ctx stops retrying, as the alert is no longer wanted.
+func retryAlert(ctx context.Context, what string, send func() error) error {
+	err := send()
+	delay := alertBackoff
+	for try := 1; err != nil && try <= alertRetries; try++ {
+		log.Printf("Sending %s failed, retrying in %v: %v", what, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if err = send(); err == nil {
+			return nil
+		}
+		if try == alertRetries {
+			return fmt.Errorf("%w (gave up after %d tries)", err, try+1)
+		}
+	}
+	return err
+}
+
+// soundCmd plays the alert sound until it finishes or ctx is cancelled.
+// Cancellation is not a failure, so it is reported as success.
+func soundCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		err := playSound(ctx)
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+		return soundResultMsg{err: err}
+	}
+}
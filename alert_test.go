@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRetryAlert(t *testing.T) {
+	tries := 0
+	err := retryAlert(context.Background(), "test", func() error {
+		if tries++; tries < 3 {
+			return errors.New("D-Bus not up")
+		}
+		return nil
+	})
+	if err != nil || tries != 3 {
+		t.Errorf("Expected success on the third try, got %v after %d tries", err, tries)
+	}
+
+	tries = 0
+	err = retryAlert(context.Background(), "test", func() error {
+		tries++
+		return errors.New("no route to host")
+	})
+	if tries != alertRetries+1 {
+		t.Errorf("Expected %d tries, got %d", alertRetries+1, tries)
+	}
+	if err == nil || err.Error() != "no route to host (gave up after 4 tries)" {
+		t.Errorf("Expected the last failure to be returned, got %v", err)
+	}
+
+	// Silencing the alert stops the retries
+	ctx, cancel := context.WithCancel(context.Background())
+	tries = 0
+	err = retryAlert(ctx, "test", func() error {
+		tries++
+		cancel()
+		return errors.New("timeout")
+	})
+	if err != nil || tries != 1 {
+		t.Errorf("Expected no retries once cancelled, got %v after %d tries", err, tries)
+	}
+}
+
+func TestNotifyRetryFailureShown(t *testing.T) {
+	defer func(f func(title, body string, opts notifyOptions) error) { sendNotification = f }(sendNotification)
+	sendNotification = func(string, string, notifyOptions) error { return errors.New("D-Bus not up") }
+
+	msg := notifyCmd(context.Background(), "Rooibos is ready", notifyOptions{})()
+	m := initialModel(NewConfig())
+	newModel, _ := m.Update(msg)
+	m = newModel.(model)
+	if !m.notifyFailed || !strings.Contains(m.status, "Notification failed: D-Bus not up (gave up after 4 tries)") {
+		t.Errorf("Expected the final failure in the status line, got %q", m.status)
+	}
+}
+
+func TestNotifyIconEmbedded(t *testing.T) {
+	img, err := png.Decode(bytes.NewReader(iconPNG))
+	if err != nil {
+		t.Fatalf("Expected the embedded icon to be a PNG, got %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != b.Dy() || b.Dx() < 64 {
+		t.Errorf("Expected a square icon of at least 64px, got %v", b)
+	}
+}
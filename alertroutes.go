@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Events that raise alerts, the keys of the [alerts.routes] table.
+const (
+	EventFinish  = "finish"  // The brew is ready
+	EventHalfway = "halfway" // The halfway cue is reached
+	EventFinal   = "final"   // The final stretch cue is reached
+	EventStart   = "start"   // A brew starts
+	EventPause   = "pause"   // A brew has been paused for pauseReminderAfter
+	EventResume  = "resume"  // A paused brew resumes
+)
+
+// Alert channels an event can be routed to.
+const (
+	ChannelSound   = "sound"   // The alert sound, soft for every event but the finish
+	ChannelDesktop = "desktop" // A desktop notification
+	ChannelLight   = "light"   // Blink the smart light
+	ChannelGPIO    = "gpio"    // Pulse the GPIO pin
+)
+
+// alertEvents and alertChannels list the events and channels in the order
+// they are documented.
+var (
+	alertEvents   = []string{EventFinish, EventHalfway, EventFinal, EventStart, EventPause, EventResume}
+	alertChannels = []string{ChannelSound, ChannelDesktop, ChannelLight, ChannelGPIO}
+)
+
+// AlertRoutes maps an event to the channels it fires, such as
+// finish = ["sound", "desktop"]. Events it leaves out keep their default
+// channels, and an empty list silences an event.
+type AlertRoutes map[string][]string
+
+// validate checks that every event and channel in the routes exists.
+func (r AlertRoutes) validate() []error {
+	var errs []error
+	for _, event := range sortedKeys(r) {
+		if !slices.Contains(alertEvents, event) {
+			errs = append(errs, fmt.Errorf("alerts.routes: unknown event %q (use %s)", event, strings.Join(alertEvents, ", ")))
+			continue
+		}
+		for _, channel := range r[event] {
+			if !slices.Contains(alertChannels, channel) {
+				errs = append(errs, fmt.Errorf("alerts.routes.%s: unknown channel %q (use %s)", event, channel, strings.Join(alertChannels, ", ")))
+			}
+		}
+	}
+	return errs
+}
+
+// String lists the routes in the order events are documented, such as
+// "finish=sound+desktop halfway=none", for "go-brew config sources".
+func (r AlertRoutes) String() string {
+	var routes []string
+	for _, event := range alertEvents {
+		channels, ok := r[event]
+		switch {
+		case !ok:
+			continue
+		case len(channels) == 0:
+			routes = append(routes, event+"=none")
+		default:
+			routes = append(routes, event+"="+strings.Join(channels, "+"))
+		}
+	}
+	if len(routes) == 0 {
+		return "defaults"
+	}
+	return strings.Join(routes, " ")
+}
+
+// sortedKeys returns the events in r in alphabetical order, so errors come
+// out the same way every time.
+func sortedKeys(r AlertRoutes) []string {
+	keys := make([]string, 0, len(r))
+	for k := range r {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// defaultRoute returns the channels an event fires when [alerts.routes]
+// doesn't name it. They follow the older switches: the finish uses every
+// channel, cues play the soft sound with cues.sound, and the brew state
+// events notify with alerts.notify_start, notify_pause and notify_resume.
+func (c *Config) defaultRoute(event string) []string {
+	on := func(enabled bool, channel string) []string {
+		if enabled {
+			return []string{channel}
+		}
+		return nil
+	}
+	switch event {
+	case EventFinish:
+		return alertChannels
+	case EventHalfway, EventFinal:
+		return on(c.CueSound, ChannelSound)
+	case EventStart:
+		return on(c.NotifyStart, ChannelDesktop)
+	case EventPause:
+		return on(c.NotifyPause, ChannelDesktop)
+	case EventResume:
+		return on(c.NotifyResume, ChannelDesktop)
+	}
+	return nil
+}
+
+// routed reports whether event fires channel. Besides being routed there,
+// the channel has to be on: alerts.sound and alerts.desktop switch sound and
+// notifications off for every event, quiet hours mute the sound, and the
+// light and GPIO pin need setting up.
+func (c *Config) routed(event, channel string) bool {
+	channels, ok := c.Routes[event]
+	if !ok {
+		channels = c.defaultRoute(event)
+	}
+	if !slices.Contains(channels, channel) {
+		return false
+	}
+	switch channel {
+	case ChannelSound:
+		return c.SoundEnabled && !c.quiet()
+	case ChannelDesktop:
+		return c.NotifyEnabled
+	case ChannelLight:
+		return c.Light.Kind != ""
+	case ChannelGPIO:
+		return c.GPIOPin >= 0
+	}
+	return false
+}
+
+// routedAny reports whether event fires any channel at all.
+func (c *Config) routedAny(event string) bool {
+	return slices.ContainsFunc(alertChannels, func(channel string) bool { return c.routed(event, channel) })
+}
+
+// eventAlertCmd raises the alerts routed for an event other than the finish,
+// with body as the text of a notification. Its sound is the soft cue sound,
+// and failures of it are only logged, as the finish alert reports sound
+// problems. It returns nil when the event is routed nowhere.
+func (m model) eventAlertCmd(ctx context.Context, event, body string) tea.Cmd {
+	var cmds []tea.Cmd
+	if m.config.routed(event, ChannelSound) {
+		cmds = append(cmds, cueCmd(ctx))
+	}
+	if m.config.routed(event, ChannelDesktop) {
+		cmds = append(cmds, notifyCmd(ctx, body, m.config.notifyOptions()))
+	}
+	if m.config.routed(event, ChannelLight) {
+		cmds = append(cmds, lightCmd(ctx, m.config.Light))
+	}
+	if m.config.routed(event, ChannelGPIO) {
+		cmds = append(cmds, gpioCmd(ctx, m.config.GPIOPin))
+	}
+	return tea.Batch(cmds...)
+}
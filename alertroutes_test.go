@@ -0,0 +1,111 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultRoutes(t *testing.T) {
+	config := NewConfig()
+	config.GPIOPin = -1
+	for _, tc := range []struct {
+		event, channel string
+		want           bool
+	}{
+		{EventFinish, ChannelSound, true},
+		{EventFinish, ChannelDesktop, true},
+		{EventFinish, ChannelLight, false}, // No light set up
+		{EventHalfway, ChannelSound, false},
+		{EventStart, ChannelDesktop, false},
+	} {
+		if got := config.routed(tc.event, tc.channel); got != tc.want {
+			t.Errorf("Expected %s→%s to be %v by default, got %v", tc.event, tc.channel, tc.want, got)
+		}
+	}
+
+	// The older switches still decide the defaults
+	config.CueSound, config.NotifyStart = true, true
+	if !config.routed(EventHalfway, ChannelSound) || !config.routed(EventStart, ChannelDesktop) {
+		t.Error("Expected cues.sound and notify_start to route their events")
+	}
+}
+
+func TestConfiguredRoutes(t *testing.T) {
+	fc, _, err := loadConfigFile(writeConfig(t, `
+[alerts.routes]
+finish = ["sound"]
+halfway = ["desktop"]
+final = []
+`))
+	if err != nil {
+		t.Fatalf("Failed to load routes: %v", err)
+	}
+	config := NewConfig()
+	config.CueSound = true
+	config.applyFile(fc, "user")
+
+	if config.routed(EventFinish, ChannelDesktop) || !config.routed(EventFinish, ChannelSound) {
+		t.Error("Expected the finish to play the sound only")
+	}
+	if !config.routed(EventHalfway, ChannelDesktop) || config.routed(EventHalfway, ChannelSound) {
+		t.Error("Expected halfway to notify instead of playing the cue sound")
+	}
+	if config.routedAny(EventFinal) {
+		t.Error("Expected an empty route to silence the final cue")
+	}
+	if got := config.Routes.String(); got != "finish=sound halfway=desktop final=none" {
+		t.Errorf("Expected the routes listed in event order, got %q", got)
+	}
+
+	// A later layer reroutes single events and keeps the rest
+	project, _, err := loadConfigFile(writeConfig(t, "[alerts.routes]\nfinal = [\"sound\"]\n"))
+	if err != nil {
+		t.Fatalf("Failed to load routes: %v", err)
+	}
+	config.applyFile(project, "project")
+	if !config.routed(EventFinal, ChannelSound) || !config.routed(EventHalfway, ChannelDesktop) {
+		t.Errorf("Expected routes to merge by event, got %s", config.Routes)
+	}
+
+	// A master switch still turns a channel off everywhere
+	config.NotifyEnabled = false
+	if config.routed(EventHalfway, ChannelDesktop) {
+		t.Error("Expected alerts.desktop = false to win over a route")
+	}
+}
+
+func TestInvalidRoutes(t *testing.T) {
+	fc, _, err := loadConfigFile(writeConfig(t, "[alerts.routes]\nsteeped = [\"sound\"]\nfinish = [\"ntfy\"]\n"))
+	if err != nil {
+		t.Fatalf("Failed to load routes: %v", err)
+	}
+	errs := fc.validate()
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), `unknown channel "ntfy"`) || !strings.Contains(errs[1].Error(), `unknown event "steeped"`) {
+		t.Errorf("Expected an unknown event and channel to be reported, got %v", errs)
+	}
+}
+
+func TestCueRoutedToDesktop(t *testing.T) {
+	var sent []string
+	defer func(f func(title, body string, opts notifyOptions) error) { sendNotification = f }(sendNotification)
+	sendNotification = func(_, body string, _ notifyOptions) error {
+		sent = append(sent, body)
+		return nil
+	}
+
+	config := NewConfig()
+	config.Routes = AlertRoutes{EventHalfway: {ChannelDesktop}}
+	m := initialModel(config)
+	m.customDuration = time.Minute
+	m.brewTea = "Sencha"
+	m.state = StateBrewing
+	m.timer = 31 * time.Second
+
+	_, cmd := m.Update(tickLeft(m, 30*time.Second))
+	cmdMsgs(cmd)
+	if !slices.Contains(sent, "Your Sencha is halfway done") {
+		t.Errorf("Expected a halfway notification, got %q", sent)
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
+	"fmt"
 	"log"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/ebitengine/oto/v3"
@@ -21,62 +24,118 @@ var alertMP3Data []byte
 // 2. Secondary: System-specific sound files
 // 3. Tertiary: Terminal bell character
 // This ensures users receive notification even on systems with limited audio capabilities.
-func playSound() {
-	go func() {
-		if err := tryMP3Playback(); err != nil {
-			log.Printf("MP3 playback failed: %v", err)
-			if err := trySystemBeep(); err != nil {
-				log.Printf("System beep failed: %v", err)
-				log.Println("All audio methods failed")
-			}
+// It blocks until the alert has played and returns an error only when every
+// method failed. Cancelling ctx stops an alert that is still playing and skips
+// any remaining fallbacks, releasing the audio device immediately.
+func playSound(ctx context.Context) error {
+	mp3Err := tryMP3Playback(ctx, 1)
+	if mp3Err == nil || ctx.Err() != nil {
+		return ctx.Err()
+	}
+	log.Printf("MP3 playback failed: %v", mp3Err)
+
+	beepErr := trySystemBeep(ctx)
+	if beepErr == nil || ctx.Err() != nil {
+		return ctx.Err()
+	}
+	log.Printf("System beep failed: %v", beepErr)
+
+	return fmt.Errorf("all audio methods failed: mp3: %v; system beep: %v", mp3Err, beepErr)
+}
+
+// playCue plays the alert sound quietly for the halfway and final stretch
+// cues. There are no fallbacks: system beeps are too loud for a gentle cue.
+func playCue(ctx context.Context) error {
+	return tryMP3Playback(ctx, cueVolume)
+}
+
+// playbackPollInterval is how often a playing sound is checked for completion.
+const playbackPollInterval = 50 * time.Millisecond
+
+// The audio device context is created once per process: oto allows only a
+// single context, so every alert after the first reuses it.
+var (
+	audioOnce sync.Once
+	audioCtx  *oto.Context
+	audioErr  error
+)
+
+// audioContext returns the shared oto context, creating it on first use with
+// the given sample rate and waiting until the device is ready.
+func audioContext(sampleRate int) (*oto.Context, error) {
+	audioOnce.Do(func() {
+		ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+			SampleRate:   sampleRate,
+			ChannelCount: 2,
+			Format:       oto.FormatSignedInt16LE,
+			BufferSize:   0, // Use driver's default buffer size
+		})
+		if err != nil {
+			audioErr = err
+			return
 		}
-	}()
+		<-ready
+		audioCtx = ctx
+	})
+	return audioCtx, audioErr
 }
 
 // tryMP3Playback attempts to play the embedded MP3 alert file using pure Go libraries.
 // It uses go-mp3 for decoding and oto for cross-platform audio playback.
-// This method provides the best audio quality and requires no external files.
-func tryMP3Playback() error {
+// The decoder is streamed into the player, so frames are decoded on demand as
+// the device buffer drains rather than up front, and the call returns as soon
+// as playback has actually finished or ctx is cancelled. The volume ranges
+// from 0 to 1.
+func tryMP3Playback(ctx context.Context, volume float64) error {
 	reader := bytes.NewReader(alertMP3Data)
 	decoder, err := mp3.NewDecoder(reader)
 	if err != nil {
 		return err
 	}
 
-	otoCtx, ready, err := oto.NewContext(&oto.NewContextOptions{
-		SampleRate:   decoder.SampleRate(),
-		ChannelCount: 2,
-		Format:       oto.FormatSignedInt16LE,
-		BufferSize:   0, // Use driver's default buffer size
-	})
+	otoCtx, err := audioContext(decoder.SampleRate())
 	if err != nil {
 		return err
 	}
-	<-ready
 
 	player := otoCtx.NewPlayer(decoder)
 	defer player.Close()
 
+	player.SetVolume(volume)
 	player.Play()
+	select {
+	case <-playbackDone(player):
+		return player.Err()
+	case <-ctx.Done():
+		player.Pause()
+		return ctx.Err()
+	}
+}
 
-	// Wait for the sound to finish
-	duration := time.Duration(float64(decoder.Length()) / float64(4*decoder.SampleRate()) * float64(time.Second))
-	time.Sleep(duration)
-
-	return nil
+// playbackDone returns a channel that is closed once the player has consumed
+// all of its input and stopped playing.
+func playbackDone(player *oto.Player) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for player.IsPlaying() {
+			time.Sleep(playbackPollInterval)
+		}
+	}()
+	return done
 }
 
 // trySystemBeep attempts to play a system-specific beep sound as a fallback mechanism.
 // It uses different methods depending on the operating system to provide the best
 // chance of successful audio playback when the MP3 file is unavailable.
-func trySystemBeep() error {
+func trySystemBeep(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "windows":
-		return playWindowsBeep()
+		return playWindowsBeep(ctx)
 	case "darwin":
-		return playMacBeep()
+		return playMacBeep(ctx)
 	case "linux":
-		return playLinuxBeep()
+		return playLinuxBeep(ctx)
 	default:
 		log.Printf("No system beep implementation for %s", runtime.GOOS)
 		return nil
@@ -86,16 +145,16 @@ func trySystemBeep() error {
 // playWindowsBeep plays a system beep sound on Windows using PowerShell.
 // It leverages the .NET Media.SoundPlayer class to play the system beep sound.
 // This method works on modern Windows systems with PowerShell installed.
-func playWindowsBeep() error {
-	cmd := exec.Command("powershell", "-c", "(New-Object Media.SoundPlayer 'System.Windows.Media.SystemSounds.Beep.wav').PlaySync();")
+func playWindowsBeep(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "powershell", "-c", "(New-Object Media.SoundPlayer 'System.Windows.Media.SystemSounds.Beep.wav').PlaySync();")
 	return cmd.Run()
 }
 
 // playMacBeep plays a system beep sound on macOS using the afplay command.
 // It uses the built-in Ping sound file that's available on all macOS systems.
 // This provides a native macOS audio experience without additional dependencies.
-func playMacBeep() error {
-	cmd := exec.Command("afplay", "/System/Library/Sounds/Ping.aiff")
+func playMacBeep(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "afplay", "/System/Library/Sounds/Ping.aiff")
 	return cmd.Run()
 }
 
@@ -105,7 +164,7 @@ func playMacBeep() error {
 // - aplay (ALSA)
 // - beep command-line utility
 // - Terminal bell character as last resort
-func playLinuxBeep() error {
+func playLinuxBeep(ctx context.Context) error {
 	// Try multiple Linux beep methods
 	commands := [][]string{
 		{"paplay", "/usr/share/sounds/alsa/Front_Left.wav"},
@@ -115,11 +174,14 @@ func playLinuxBeep() error {
 	}
 
 	for _, args := range commands {
-		cmd := exec.Command(args[0], args[1:]...)
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 		if err := cmd.Run(); err == nil {
 			return nil
 		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 	}
 
-	return exec.Command("echo", "-e", "\a").Run()
+	return exec.CommandContext(ctx, "echo", "-e", "\a").Run()
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func BenchmarkRender(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, scene := range benchScenes(size.width, size.height) {
+			b.Run(fmt.Sprintf("%dx%d/%s", size.width, size.height, scene.name), func(b *testing.B) {
+				m := scene.model
+				b.ReportAllocs()
+				for range b.N {
+					m.render()
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkViewCached measures the frames Bubbletea asks for between
+// changes, which the view cache answers.
+func BenchmarkViewCached(b *testing.B) {
+	m := benchScenes(80, 24)[1].model
+	m.View()
+	b.ReportAllocs()
+	for range b.N {
+		m.View()
+	}
+}
+
+// BenchmarkTick measures one second of a running brew: the tick update and
+// the frame it leads to.
+func BenchmarkTick(b *testing.B) {
+	m := benchScenes(80, 24)[1].model
+	m.timer = time.Duration(b.N+1) * time.Second
+	b.ReportAllocs()
+	for range b.N {
+		next, _ := m.Update(tickLeft(m, m.timer-time.Second))
+		m = next.(model)
+		m.View()
+	}
+}
+
+// TestRenderAllocBudget guards the brewing frame, rendered every second,
+// against allocation regressions. Raise the budget only for a deliberate
+// addition to the view.
+func TestRenderAllocBudget(t *testing.T) {
+	const budget = 2500 // About 1700 today
+	m := benchScenes(80, 24)[1].model
+	if allocs := testing.AllocsPerRun(20, func() { m.render() }); allocs > budget {
+		t.Errorf("Expected at most %d allocations per brewing frame, got %.0f", budget, allocs)
+	}
+}
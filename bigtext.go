@@ -0,0 +1,42 @@
+package main
+
+import "strings"
+
+// bigGlyphs draws the characters of a clock-style time five rows high, for
+// the oversized kiosk timer. Each cell is doubled across when drawn, so the
+// digits come out roughly as wide as they are tall.
+var bigGlyphs = map[rune][5]string{
+	'0': {"###", "# #", "# #", "# #", "###"},
+	'1': {"  #", "  #", "  #", "  #", "  #"},
+	'2': {"###", "  #", "###", "#  ", "###"},
+	'3': {"###", "  #", "###", "  #", "###"},
+	'4': {"# #", "# #", "###", "  #", "  #"},
+	'5': {"###", "#  ", "###", "  #", "###"},
+	'6': {"###", "#  ", "###", "# #", "###"},
+	'7': {"###", "  #", "  #", "  #", "  #"},
+	'8': {"###", "# #", "###", "# #", "###"},
+	'9': {"###", "# #", "###", "  #", "###"},
+	':': {" ", "#", " ", "#", " "},
+}
+
+// bigText draws s in block characters five rows high. It reports false when
+// s holds a character there is no glyph for, such as the letters of the
+// "words" time format, so the caller can fall back to plain text.
+func bigText(s string) (string, bool) {
+	var rows [5][]string
+	for _, r := range s {
+		glyph, ok := bigGlyphs[r]
+		if !ok {
+			return "", false
+		}
+		for i, row := range glyph {
+			row = strings.ReplaceAll(row, "#", "██")
+			rows[i] = append(rows[i], strings.ReplaceAll(row, " ", "  "))
+		}
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, " ")
+	}
+	return strings.Join(lines, "\n"), true
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// blindTeaName stands in for the tea of a blind taste test until the brew
+// has been rated.
+const blindTeaName = "Mystery tea"
+
+// blindPick chooses which of n presets a blind taste test brews. Tests
+// replace it to make the choice predictable.
+var blindPick = rand.IntN
+
+// parseBlindTest resolves the two presets named in a -blind list such as
+// "Black Tea,Oolong" to their indexes. Names match regardless of case.
+func parseBlindTest(spec string, presets []TeaPreset) ([]int, error) {
+	names := strings.Split(spec, ",")
+	if len(names) != 2 {
+		return nil, fmt.Errorf("name two presets separated by a comma, e.g. \"Black Tea,Oolong\"")
+	}
+	var indexes []int
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		idx := -1
+		for i, p := range presets {
+			if strings.EqualFold(p.Name, name) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("there is no preset called %q", name)
+		}
+		indexes = append(indexes, idx)
+	}
+	if indexes[0] == indexes[1] {
+		return nil, fmt.Errorf("name two different presets")
+	}
+	return indexes, nil
+}
+
+// blindTest reports whether the timer runs a blind taste test.
+func (m model) blindTest() bool {
+	return len(m.config.Blind) > 0
+}
+
+// pickBlind secretly selects one of the blind taste test's presets for the
+// brew about to start. The tea is recorded under its real name, but shown as
+// blindTeaName until the brew is rated.
+func (m model) pickBlind() model {
+	m.presetIdx = m.config.Blind[blindPick(len(m.config.Blind))]
+	m.infusion = 0
+	m = m.clearCustom()
+	m.blindTea = m.currentPreset().Name
+	return m
+}
+
+// blindLabel names the teas being compared in a blind taste test.
+func (m model) blindLabel() string {
+	a, b := m.config.Presets[m.config.Blind[0]], m.config.Presets[m.config.Blind[1]]
+	return m.icon("🙈", "") + "Blind taste test: " + a.Name + " or " + b.Name
+}
+
+// revealBlind shows the tea of a rated blind brew under its real name.
+func (m model) revealBlind() model {
+	m.brewTea = m.blindTea
+	m.blindTea = ""
+	return m
+}
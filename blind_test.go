@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseBlindTest(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr bool
+	}{
+		{"Green Tea,Oolong", []int{1, 5}, false},
+		{" oolong , green tea ", []int{5, 1}, false},
+		{"Green Tea", nil, true},
+		{"Green Tea,Oolong,Herbal", nil, true},
+		{"Green Tea,Sencha", nil, true},
+		{"Oolong,oolong", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseBlindTest(tt.spec, DefaultTeaPresets)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBlindTest(%q): expected error %v, got %v", tt.spec, tt.wantErr, err)
+			continue
+		}
+		if !tt.wantErr && (got[0] != tt.want[0] || got[1] != tt.want[1]) {
+			t.Errorf("parseBlindTest(%q): expected %v, got %v", tt.spec, tt.want, got)
+		}
+	}
+}
+
+func TestBlindTestFlagConflicts(t *testing.T) {
+	config := NewConfig()
+	config.Presets = DefaultTeaPresets
+	config.BlindTest = "Green Tea,Oolong"
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Expected -blind alone to be valid, got %v", err)
+	}
+	config.Stopwatch = true
+	if err := config.Validate(); err == nil {
+		t.Error("Expected -blind with -stopwatch to be rejected")
+	}
+}
+
+func TestBlindTasteTest(t *testing.T) {
+	pick := blindPick
+	t.Cleanup(func() { blindPick = pick })
+	blindPick = func(n int) int { return 1 }
+
+	config := NewConfig()
+	config.Presets = DefaultTeaPresets
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	config.Blind = []int{1, 5}
+	m := initialModel(config)
+	m.width, m.height = 100, 40
+	if !contains(m.View(), "Blind taste test: Green Tea or Oolong") || contains(m.View(), "›") {
+		t.Error("Expected the idle view to name both teas without a selection")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	m = newModel.(model)
+	if m.brewTea != blindTeaName || m.timer != 3*time.Minute {
+		t.Fatalf("Expected Oolong brewing as %q, got %q for %v", blindTeaName, m.brewTea, m.timer)
+	}
+
+	m.timer = time.Second
+	newModel, cmd := m.Update(tickLeft(m, 0))
+	cmdMsgs(cmd)
+	m = newModel.(model)
+	if !contains(m.View(), "find out which tea it was") {
+		t.Error("Expected the finished view to ask for a rating to reveal the tea")
+	}
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || records[0].Tea != "Oolong" || !records[0].Blind {
+		t.Fatalf("Expected a blind Oolong brew in the history, got %+v, %v", records, err)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyRate)})
+	m = newModel.(model)
+	m.input.SetValue("4")
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	cmdMsgs(cmd)
+	m = newModel.(model)
+	if m.brewTea != "Oolong" || m.status != "It was Oolong! Rated 4/5" {
+		t.Errorf("Expected the rating to reveal Oolong, got %q with status %q", m.brewTea, m.status)
+	}
+	if !contains(m.View(), "Rated 4/5 · it was Oolong") {
+		t.Error("Expected the finished view to show the revealed tea")
+	}
+}
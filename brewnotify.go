@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pauseReminderAfter is how long a brew stays paused before the optional
+// reminder that it is still waiting.
+const pauseReminderAfter = 5 * time.Minute
+
+// pauseReminderMsg fires pauseReminderAfter after a brew was paused. It
+// carries the tick chain ID of the pause, so it is ignored once the brew has
+// been resumed, reset or started over.
+type pauseReminderMsg struct {
+	id int
+}
+
+// pauseReminderCmd returns the command that reminds about a paused brew, or
+// nil unless the pause event is routed to an alert.
+func (m model) pauseReminderCmd() tea.Cmd {
+	if !m.config.routedAny(EventPause) {
+		return nil
+	}
+	id := m.tickID
+	return tea.Tick(pauseReminderAfter, func(time.Time) tea.Msg {
+		return pauseReminderMsg{id: id}
+	})
+}
+
+// stateAlertCmd raises the alerts routed for a brew starting, waiting or
+// resuming, by default only a desktop notification with body, or returns
+// nil when the event is routed nowhere.
+func (m model) stateAlertCmd(event, body string) tea.Cmd {
+	if !m.config.routedAny(event) {
+		return nil
+	}
+	return m.eventAlertCmd(context.Background(), event, body)
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestStateNotifications(t *testing.T) {
+	var sent []string
+	defer func(f func(title, body string, opts notifyOptions) error) { sendNotification = f }(sendNotification)
+	sendNotification = func(_, body string, _ notifyOptions) error {
+		sent = append(sent, body)
+		return nil
+	}
+
+	config := NewConfig()
+	config.SoundEnabled = false
+	m := initialModel(config)
+
+	// Off by default
+	m, cmd := m.start()
+	cmdMsgs(cmd)
+	if len(sent) != 0 {
+		t.Fatalf("Expected no notifications by default, got %q", sent)
+	}
+
+	config.NotifyStart, config.NotifyPause, config.NotifyResume = true, true, true
+	m = initialModel(config)
+	m, cmd = m.start()
+	cmdMsgs(cmd)
+	if len(sent) != 1 || sent[0] != "Brewing Rooibos, ready at 14:04" {
+		t.Errorf("Expected a start notification, got %q", sent)
+	}
+
+	m = m.pause()
+	reminder := m.pauseReminderCmd()
+	if reminder == nil {
+		t.Fatal("Expected a pause reminder to be scheduled")
+	}
+	newModel, cmd := m.Update(pauseReminderMsg{id: m.tickID})
+	m = newModel.(model)
+	cmdMsgs(cmd)
+	if len(sent) != 2 || sent[1] != "Your Rooibos has been paused for 5m0s" {
+		t.Errorf("Expected a pause reminder, got %q", sent)
+	}
+
+	id := m.tickID
+	m, cmd = m.resume()
+	cmdMsgs(cmd)
+	if len(sent) != 3 || sent[2] != "Resumed Rooibos, ready at 14:04" {
+		t.Errorf("Expected a resume notification, got %q", sent)
+	}
+
+	// A reminder for a pause that has ended is dropped
+	_, cmd = m.Update(pauseReminderMsg{id: id})
+	if cmdMsgs(cmd); len(sent) != 3 {
+		t.Errorf("Expected a stale pause reminder to be ignored, got %q", sent)
+	}
+
+	// Nothing is sent with desktop notifications off
+	config.NotifyEnabled = false
+	if m.pause().pauseReminderCmd() != nil || m.stateAlertCmd(EventStart, "x") != nil {
+		t.Error("Expected no notifications with desktop notifications off")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxCalcDigits is how many digits a cup size typed into the calculator may
+// have, enough for any kettle.
+const maxCalcDigits = 4
+
+// leafFor returns the grams of leaf for ml of water at a preset's ratio, or
+// false if the ratio names no amounts in grams and millilitres.
+func leafFor(ratio string, ml int) (float64, bool) {
+	perGram, ok := parseRatio(ratio)
+	if !ok {
+		return 0, false
+	}
+	return float64(ml) / perGram, true
+}
+
+// toggleCalc opens the leaf calculator for the selected preset, or closes
+// it. A running timer keeps counting down in the background.
+func (m model) toggleCalc() (model, tea.Cmd) {
+	if m.screen == screenCalc {
+		m.screen = screenTimer
+		return m, nil
+	}
+	m.screen = screenCalc
+	m.calcPreset = m.presetIdx
+	m.calcMl = ""
+	return m, nil
+}
+
+// updateCalc handles a key on the calculator screen: up and down pick the
+// tea, digits type a cup size of your own and the calculator key or esc
+// returns to the timer. Other keys but quitting are ignored, so the timer
+// can't be changed behind the calculator. It reports false for keys it
+// leaves to the timer.
+func (m model) updateCalc(keyStr string) (model, tea.Cmd, bool) {
+	keys := m.config.Keys
+	presets := len(m.config.Presets)
+	switch {
+	case keyStr == keys.Calc || keyStr == "esc":
+		m, cmd := m.toggleCalc()
+		return m, cmd, true
+	case keyStr == keys.Quit || keyStr == KeyQuitAlt:
+		return m, nil, false
+	case keyStr == keys.Up:
+		m.calcPreset = (m.calcPreset - 1 + presets) % presets
+	case keyStr == keys.Down:
+		m.calcPreset = (m.calcPreset + 1) % presets
+	case keyStr == "backspace":
+		if m.calcMl != "" {
+			m.calcMl = m.calcMl[:len(m.calcMl)-1]
+		}
+	case len(keyStr) == 1 && keyStr >= "0" && keyStr <= "9":
+		// A leading zero would only be confusing
+		if len(m.calcMl) < maxCalcDigits && (m.calcMl != "" || keyStr != "0") {
+			m.calcMl += keyStr
+		}
+	}
+	return m, nil, true
+}
+
+// calcVessels returns the vessels the calculator lists: those configured,
+// then the cup size typed, if any.
+func (m model) calcVessels() []Vessel {
+	vessels := m.config.Vessels
+	if m.calcMl != "" {
+		ml, _ := strconv.Atoi(m.calcMl)
+		vessels = append(vessels[:len(vessels):len(vessels)], Vessel{"Your cup", ml, 1})
+	}
+	return vessels
+}
+
+// renderCalc builds the calculator screen: the tea and its ratio, and the
+// leaf each vessel needs.
+func (m model) renderCalc() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Padding(1, 2).Foreground(lipgloss.Color(m.config.Colors.Ready))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Faint(true)
+	teaStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.config.Colors.Brewing))
+
+	preset := m.config.Presets[m.calcPreset]
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.icon("⚖️", "") + "Leaf Calculator"))
+	b.WriteString("\n" + teaStyle.Render(preset.Name))
+	if preset.Info.Ratio == "" {
+		b.WriteString("\n\n" + preset.Name + " has no leaf-to-water ratio.\nSet ratio in its preset, e.g. \"3g per 250ml\".")
+	} else if _, ok := parseRatio(preset.Info.Ratio); !ok {
+		b.WriteString("\n\n" + fmt.Sprintf("Can't work out amounts from the ratio %q.\nWrite it in grams and ml, e.g. \"3g per 250ml\".", preset.Info.Ratio))
+	} else {
+		b.WriteString(labelStyle.Render(" · " + preset.Info.Ratio))
+		b.WriteString("\n")
+		for _, v := range m.calcVessels() {
+			grams, _ := leafFor(preset.Info.Ratio, v.Ml)
+			// Mark the vessel picked for the brew
+			mark := "  "
+			if strings.EqualFold(v.Name, m.vessel) {
+				mark = "▸ "
+			}
+			b.WriteString(fmt.Sprintf("\n%s%s %6s  %5.1fg of leaf", mark, padRight(v.Name, 12), fmt.Sprintf("%dml", v.Ml), grams))
+		}
+	}
+	b.WriteString("\n\n" + labelStyle.Render(fmt.Sprintf("↑/↓ pick the tea · type a cup size in ml · '%s' or esc returns", m.config.Keys.Calc)))
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLeafFor(t *testing.T) {
+	if grams, ok := leafFor("3g per 250ml", 350); !ok || grams != 4.2 {
+		t.Errorf("Expected 4.2g for a 350ml mug, got %v %v", grams, ok)
+	}
+	if _, ok := leafFor("a heaped spoon", 250); ok {
+		t.Error("Expected no amount for a ratio without grams and ml")
+	}
+}
+
+func TestCalculatorScreen(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.presetIdx = 1 // Green Tea, 2g per 250ml
+	m.width, m.height = 80, 24
+	press := func(keys ...string) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "esc":
+				msg = tea.KeyMsg{Type: tea.KeyEsc}
+			case "down":
+				msg = tea.KeyMsg{Type: tea.KeyDown}
+			case "backspace":
+				msg = tea.KeyMsg{Type: tea.KeyBackspace}
+			}
+			newModel, _ := m.Update(msg)
+			m = newModel.(model)
+		}
+	}
+
+	press(KeyCalc)
+	if m.screen != screenCalc {
+		t.Fatal("Expected the calculator key to open the calculator")
+	}
+	view := m.View()
+	for _, want := range []string{"Green Tea", "2g per 250ml", "120ml    1.0g of leaf", "750ml    6.0g of leaf"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the calculator, got:\n%s", want, view)
+		}
+	}
+
+	// Digits type a cup size instead of picking a preset
+	press("0", "4", "0", "0", "5", "backspace")
+	if m.calcMl != "400" || m.presetIdx != 1 {
+		t.Errorf("Expected a typed size of 400ml and the preset kept, got %q and preset %d", m.calcMl, m.presetIdx)
+	}
+	if view := m.View(); !strings.Contains(view, "Your cup") || !strings.Contains(view, "400ml    3.2g of leaf") {
+		t.Errorf("Expected the typed cup in the calculator, got:\n%s", view)
+	}
+
+	// Other teas can be looked up without changing the timer's
+	press("down", KeyStart)
+	if m.calcPreset != 2 || m.presetIdx != 1 || m.state != StateIdle {
+		t.Errorf("Expected only the calculator's tea to change, got %d, preset %d, %v", m.calcPreset, m.presetIdx, m.state)
+	}
+	if view := m.View(); !strings.Contains(view, "Black Tea") || !strings.Contains(view, "400ml    4.0g of leaf") {
+		t.Errorf("Expected Black Tea's amounts, got:\n%s", view)
+	}
+
+	press("esc")
+	if m.screen != screenTimer {
+		t.Error("Expected esc to return to the timer")
+	}
+}
+
+func TestCalculatorWithoutRatio(t *testing.T) {
+	config := NewConfig()
+	config.Presets = []TeaPreset{{Name: "Mint", Duration: 5 * time.Minute}}
+	m := initialModel(config)
+	m.width, m.height = 80, 24
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCalc)})
+	if view := newModel.(model).View(); !strings.Contains(view, "Mint has no leaf-to-water ratio") {
+		t.Errorf("Expected the calculator to ask for a ratio, got:\n%s", view)
+	}
+}
+
+func TestVesselsConfig(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, "[[vessels]]\nname = \"Kyusu\"\nml = 180\n")
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Vessels) != 1 || config.Vessels[0] != (Vessel{"Kyusu", 180, 1}) {
+		t.Errorf("Expected the configured vessel to replace the defaults, got %v", config.Vessels)
+	}
+
+	fc, _, err := loadConfigFile(writeConfig(t, "[[vessels]]\nname = \"\"\nml = 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := fc.validate(); len(errs) != 2 {
+		t.Errorf("Expected the empty name and size to be rejected, got %v", errs)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sonosPort       = "1400"                                       // Port of the Sonos UPnP control API
+	sonosAVTService = "urn:schemas-upnp-org:service:AVTransport:1" // UPnP service that plays media
+	ssdpAddr        = "239.255.255.250:1900"                       // UPnP discovery multicast address
+	ssdpTarget      = "urn:schemas-upnp-org:device:ZonePlayer:1"   // Device type Sonos speakers announce
+)
+
+var (
+	// speakerDiscovery is how long to wait for Sonos speakers to answer when
+	// looking one up by room name.
+	speakerDiscovery = 2 * time.Second
+	// castServe is how long the alert sound stays available to the speaker,
+	// long enough for it to fetch and play the file.
+	castServe = 30 * time.Second
+	// castClient makes the requests to speakers on the local network.
+	castClient = &http.Client{Timeout: 5 * time.Second}
+)
+
+// castCmd plays the alert sound on the configured Sonos speaker instead of
+// the computer's own speakers. If the speaker can't be reached the sound is
+// played locally after all, and the casting problem is reported.
+func castCmd(ctx context.Context, speaker string) tea.Cmd {
+	return func() tea.Msg {
+		err := castSound(ctx, speaker)
+		if err == nil || ctx.Err() != nil {
+			return soundResultMsg{}
+		}
+		log.Printf("Casting to %s failed: %v", speaker, err)
+		if localErr := playSound(ctx); localErr != nil && !errors.Is(localErr, context.Canceled) {
+			return soundResultMsg{err: fmt.Errorf("casting to %s: %v; local playback: %w", speaker, err, localErr)}
+		}
+		return soundResultMsg{err: fmt.Errorf("casting to %s: %w (played locally instead)", speaker, err)}
+	}
+}
+
+// castSound serves the embedded alert sound over HTTP and tells the speaker
+// to play it. It returns once the speaker has had castServe to play it, or
+// stops playback early when ctx is cancelled.
+func castSound(ctx context.Context, speaker string) error {
+	host, err := resolveSpeaker(ctx, speaker)
+	if err != nil {
+		return err
+	}
+
+	// Serve the sound on the address the speaker reaches us on
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return err
+	}
+	localIP := conn.LocalAddr().(*net.UDPAddr).IP
+	conn.Close()
+	ln, err := net.Listen("tcp", net.JoinHostPort(localIP.String(), "0"))
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeContent(w, r, "alert.mp3", time.Time{}, bytes.NewReader(alertMP3Data))
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	soundURL := "http://" + ln.Addr().String() + "/alert.mp3"
+	if err := sonosAction(ctx, host, "SetAVTransportURI", "<CurrentURI>"+html.EscapeString(soundURL)+"</CurrentURI><CurrentURIMetaData></CurrentURIMetaData>"); err != nil {
+		return err
+	}
+	if err := sonosAction(ctx, host, "Play", "<Speed>1</Speed>"); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		// The brew was reset or a new one started: silence the speaker too
+		return sonosAction(context.Background(), host, "Stop", "")
+	case <-time.After(castServe):
+		return nil
+	}
+}
+
+// sonosAction calls an AVTransport action on the speaker at host with the
+// given argument elements.
+func sonosAction(ctx context.Context, host, action, args string) error {
+	body := `<?xml version="1.0" encoding="utf-8"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>` +
+		`<u:` + action + ` xmlns:u="` + sonosAVTService + `"><InstanceID>0</InstanceID>` + args + `</u:` + action + `>` +
+		`</s:Body></s:Envelope>`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+host+"/MediaRenderer/AVTransport/Control", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPACTION", `"`+sonosAVTService+"#"+action+`"`)
+	resp, err := castClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", action, resp.Status)
+	}
+	return nil
+}
+
+// resolveSpeaker returns the host:port of the speaker's control API. The
+// speaker is either an address such as "192.168.1.30" or a Sonos room name
+// such as "Kitchen", which is looked up on the local network.
+func resolveSpeaker(ctx context.Context, speaker string) (string, error) {
+	if _, _, err := net.SplitHostPort(speaker); err == nil {
+		return speaker, nil
+	}
+	if net.ParseIP(speaker) != nil || strings.Contains(speaker, ".") {
+		return net.JoinHostPort(speaker, sonosPort), nil
+	}
+	return discoverSonos(ctx, speaker)
+}
+
+// discoverSonos searches the local network for the Sonos speaker whose room
+// is called name, ignoring case.
+func discoverSonos(ctx context.Context, name string) (string, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return "", err
+	}
+	search := "M-SEARCH * HTTP/1.1\r\nHOST: " + ssdpAddr + "\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: " + ssdpTarget + "\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(search), dst); err != nil {
+		return "", err
+	}
+
+	seen := make(map[string]bool)
+	conn.SetReadDeadline(time.Now().Add(speakerDiscovery))
+	buf := make([]byte, 2048)
+	for ctx.Err() == nil {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			break // Discovery time is up
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		location := resp.Header.Get("Location")
+		if location == "" || seen[location] {
+			continue
+		}
+		seen[location] = true
+		if room, err := sonosRoomName(ctx, location); err == nil && strings.EqualFold(room, name) {
+			u, err := url.Parse(location)
+			if err != nil {
+				return "", err
+			}
+			return u.Host, nil
+		}
+	}
+	return "", fmt.Errorf("no Sonos speaker called %q found on the network", name)
+}
+
+// sonosRoomName reads the room name from a speaker's device description.
+func sonosRoomName(ctx context.Context, location string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := castClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var desc struct {
+		RoomName string `xml:"device>roomName"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&desc); err != nil {
+		return "", err
+	}
+	return desc.RoomName, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCastSound(t *testing.T) {
+	defer func(d time.Duration) { castServe = d }(castServe)
+	castServe = 50 * time.Millisecond
+
+	// The fake speaker fetches the sound it is told to play, like a real one
+	var mu sync.Mutex
+	var actions []string
+	var fetched []byte
+	uriPattern := regexp.MustCompile(`<CurrentURI>(.*)</CurrentURI>`)
+	speaker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		actions = append(actions, r.Header.Get("SOAPACTION"))
+		if m := uriPattern.FindSubmatch(body); m != nil {
+			resp, err := http.Get(string(m[1]))
+			if err != nil {
+				t.Errorf("Failed to fetch the alert sound: %v", err)
+				return
+			}
+			fetched, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+	}))
+	defer speaker.Close()
+
+	if err := castSound(context.Background(), strings.TrimPrefix(speaker.URL, "http://")); err != nil {
+		t.Fatalf("Expected the sound to be cast, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(actions) != 2 || !strings.HasSuffix(actions[0], `#SetAVTransportURI"`) || !strings.HasSuffix(actions[1], `#Play"`) {
+		t.Errorf("Expected SetAVTransportURI then Play, got %v", actions)
+	}
+	if !bytes.Equal(fetched, alertMP3Data) {
+		t.Errorf("Expected the speaker to fetch the alert sound, got %d bytes", len(fetched))
+	}
+}
+
+func TestResolveSpeakerAddress(t *testing.T) {
+	for speaker, want := range map[string]string{
+		"192.168.1.30":      "192.168.1.30:1400",
+		"192.168.1.30:1401": "192.168.1.30:1401",
+		"kitchen.local":     "kitchen.local:1400",
+	} {
+		if got, err := resolveSpeaker(context.Background(), speaker); err != nil || got != want {
+			t.Errorf("resolveSpeaker(%q) = %q, %v; want %q", speaker, got, err, want)
+		}
+	}
+}
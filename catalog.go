@@ -0,0 +1,179 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// catalogRows is the number of catalog matches listed at once.
+const catalogRows = 10
+
+//go:embed catalog.toml
+var catalogData []byte
+
+// catalogTea is a tea in the built-in catalog.
+type catalogTea struct {
+	Category string // Kind of tea such as "Green" or "Herbal"
+	TeaPreset
+}
+
+// loadCatalog decodes the built-in catalog the first time it is needed, so
+// starting the timer doesn't pay for teas nobody browses.
+var loadCatalog = sync.OnceValues(func() ([]catalogTea, error) {
+	var file struct {
+		Teas []struct {
+			Name     string   `toml:"name"`
+			Category string   `toml:"category"`
+			Duration Duration `toml:"duration"`
+			Temp     string   `toml:"temp"`
+			Notes    string   `toml:"notes"`
+		} `toml:"teas"`
+	}
+	if _, err := toml.Decode(string(catalogData), &file); err != nil {
+		return nil, fmt.Errorf("tea catalog: %w", err)
+	}
+	teas := make([]catalogTea, len(file.Teas))
+	for i, t := range file.Teas {
+		teas[i] = catalogTea{t.Category, TeaPreset{t.Name, t.Duration.Duration, t.Temp, t.Notes, nil, PresetInfo{}}}
+	}
+	return teas, nil
+})
+
+// searchCatalog returns the catalog teas whose name, category or notes
+// contain query, ignoring case, in catalog order.
+func searchCatalog(teas []catalogTea, query string) []catalogTea {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return teas
+	}
+	var matches []catalogTea
+	for _, t := range teas {
+		if strings.Contains(strings.ToLower(t.Name+" "+t.Category+" "+t.Notes), query) {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// openCatalog shows the catalog screen with an empty search.
+func (m model) openCatalog() (model, tea.Cmd) {
+	if _, err := loadCatalog(); err != nil {
+		return m, func() tea.Msg { return errMsg{err} }
+	}
+	m.screen = screenCatalog
+	m.catalogIdx = 0
+	m.search = newTextInput()
+	m.search.Prompt = "Search: "
+	m.search.Placeholder = "pu-erh, herbal, darjeeling"
+	return m, m.search.Focus()
+}
+
+// catalogMatches returns the catalog teas matching the current search.
+func (m model) catalogMatches() []catalogTea {
+	teas, _ := loadCatalog()
+	return searchCatalog(teas, m.search.Value())
+}
+
+// updateCatalog handles a key press on the catalog screen: typing searches,
+// up and down move through the matches, enter adds the highlighted tea to
+// the presets and esc returns to the timer.
+func (m model) updateCatalog(msg tea.KeyMsg) (model, tea.Cmd) {
+	matches := m.catalogMatches()
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.screen = screenTimer
+		m.search.Blur()
+		return m, nil
+	case tea.KeyCtrlC:
+		m.screen = screenTimer
+		newModel, cmd := m.Update(msg)
+		return newModel.(model), cmd
+	case tea.KeyUp:
+		if m.catalogIdx > 0 {
+			m.catalogIdx--
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.catalogIdx < len(matches)-1 {
+			m.catalogIdx++
+		}
+		return m, nil
+	case tea.KeyEnter:
+		if m.catalogIdx >= len(matches) {
+			return m, nil
+		}
+		m.screen = screenTimer
+		m.search.Blur()
+		return m.addCatalogTea(matches[m.catalogIdx].TeaPreset)
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	m.catalogIdx = 0 // The matches changed, so start from the best one again
+	return m, cmd
+}
+
+// addCatalogTea selects p, appending it to the presets for this session
+// unless a preset of that name is already there.
+func (m model) addCatalogTea(p TeaPreset) (model, tea.Cmd) {
+	if !m.presetsSelectable() || m.state != StateIdle {
+		return m.showStatus("Finish the current brew before picking another tea")
+	}
+	m = m.saveUndo("preset change")
+	idx := slices.IndexFunc(m.config.Presets, func(q TeaPreset) bool { return strings.EqualFold(q.Name, p.Name) })
+	status := "Selected " + p.Name
+	if idx < 0 {
+		// Replace rather than append in place: the list may be the shared
+		// DefaultTeaPresets
+		m.config.Presets = append(slices.Clone(m.config.Presets), p)
+		idx = len(m.config.Presets) - 1
+		status = "Added " + p.Name + " for this session"
+	}
+	m.presetIdx = idx
+	m.infusion = 0
+	m = m.clearCustom()
+	m.timer = m.brewDuration()
+	return m.showStatus(status)
+}
+
+// renderCatalog builds the catalog screen: the search line and a window of
+// matches around the highlighted one.
+func (m model) renderCatalog() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Padding(1, 2).Foreground(lipgloss.Color(m.config.Colors.Ready))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Faint(true)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.config.Colors.Brewing))
+
+	teas, _ := loadCatalog()
+	matches := m.catalogMatches()
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%sTea Catalog (%d teas)", m.icon("🍵", ""), len(teas))))
+	b.WriteString("\n" + m.search.View() + "\n\n")
+	if len(matches) == 0 {
+		b.WriteString("No teas match")
+	}
+
+	// Scroll so the highlighted tea stays in view
+	first := max(0, min(m.catalogIdx-catalogRows/2, len(matches)-catalogRows))
+	last := min(len(matches), first+catalogRows)
+	for i := first; i < last; i++ {
+		t := matches[i]
+		line := fmt.Sprintf("%s %s %6s  %s", padRight(t.Name, 26), padRight(t.Category, 8), t.Duration, t.Temp)
+		if i == m.catalogIdx {
+			b.WriteString(selectedStyle.Render("▸ "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	if m.catalogIdx < len(matches) {
+		b.WriteString("\n" + labelStyle.Render(matches[m.catalogIdx].Notes))
+	}
+	b.WriteString("\n\n" + labelStyle.Render("type to search · ↑/↓ select · enter adds to presets · esc returns"))
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCatalog(t *testing.T) {
+	teas, err := loadCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teas) < 50 {
+		t.Errorf("Expected at least 50 teas in the catalog, got %d", len(teas))
+	}
+	seen := make(map[string]bool)
+	for _, tea := range teas {
+		key := strings.ToLower(tea.Name)
+		if seen[key] {
+			t.Errorf("Expected unique catalog names, %q appears twice", tea.Name)
+		}
+		seen[key] = true
+		if tea.Category == "" || tea.Temp == "" {
+			t.Errorf("Expected %q to have a category and temperature", tea.Name)
+		}
+		if err := checkBrewTime(tea.Duration); err != nil {
+			t.Errorf("Expected %q to have a valid duration: %v", tea.Name, err)
+		}
+	}
+}
+
+func TestSearchCatalog(t *testing.T) {
+	teas, _ := loadCatalog()
+	if got := searchCatalog(teas, ""); len(got) != len(teas) {
+		t.Errorf("Expected an empty search to list every tea, got %d", len(got))
+	}
+	for _, tea := range searchCatalog(teas, "DARJEELING") {
+		if !strings.Contains(tea.Name, "Darjeeling") {
+			t.Errorf("Expected only Darjeeling teas, got %q", tea.Name)
+		}
+	}
+	if got := searchCatalog(teas, "herbal"); len(got) < 5 {
+		t.Errorf("Expected search to match categories, got %d herbal teas", len(got))
+	}
+	if got := searchCatalog(teas, "no such tea"); len(got) != 0 {
+		t.Errorf("Expected no matches, got %d", len(got))
+	}
+}
+
+func TestCatalogScreen(t *testing.T) {
+	m := initialModel(NewConfig())
+	send := func(msg tea.KeyMsg) {
+		newModel, _ := m.Update(msg)
+		m = newModel.(model)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCatalog)})
+	if m.screen != screenCatalog {
+		t.Fatal("Expected the catalog key to open the catalog")
+	}
+	// Keys bound on the timer screen type into the search instead
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("shou")})
+	if m.search.Value() != "shou" || m.state != StateIdle {
+		t.Fatalf("Expected typing to search, got %q in state %v", m.search.Value(), m.state)
+	}
+	send(tea.KeyMsg{Type: tea.KeyDown})
+	if matches := m.catalogMatches(); len(matches) != 2 || m.catalogIdx != 1 {
+		t.Fatalf("Expected two matches with the second highlighted, got %d at %d", len(matches), m.catalogIdx)
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.screen != screenTimer || m.currentPreset().Name != "Shou Pu-erh" {
+		t.Fatalf("Expected Shou Pu-erh to be selected on the timer, got %q", m.currentPreset().Name)
+	}
+	if len(m.config.Presets) != len(DefaultTeaPresets)+1 || len(DefaultTeaPresets) != 6 {
+		t.Error("Expected the tea to be added for the session only")
+	}
+	if m.timer != m.currentPreset().Duration {
+		t.Errorf("Expected the timer to show the tea's duration, got %v", m.timer)
+	}
+
+	// Picking it again selects the existing preset rather than adding another
+	send(tea.KeyMsg{Type: tea.KeyUp})
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCatalog)})
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("shou pu")})
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.config.Presets) != len(DefaultTeaPresets)+1 || m.currentPreset().Name != "Shou Pu-erh" {
+		t.Errorf("Expected the existing Shou Pu-erh preset to be selected, got %d presets", len(m.config.Presets))
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCatalog)})
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.screen != screenTimer {
+		t.Error("Expected esc to return to the timer")
+	}
+}
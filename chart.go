@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparkBlocks are the bar heights used by sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// heatBlocks are the shades used by heatmap, lightest first.
+var heatBlocks = []rune("░▒▓█")
+
+// heatmap renders values as a row of cells shaded by how they compare with
+// the largest value, one character per value. Zero values are drawn as
+// spaces.
+func heatmap(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(heatBlocks[(v*len(heatBlocks)-1)/peak])
+	}
+	return b.String()
+}
+
+// sparkline renders values as a row of block characters scaled to the
+// largest value, one character per value. Zero values are drawn as spaces so
+// days without a brew stand out from days with few.
+func sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		// The peak gets the full block and every brew at least the lowest
+		b.WriteRune(sparkBlocks[(v*len(sparkBlocks)-1)/peak])
+	}
+	return b.String()
+}
+
+// barChart renders one horizontal bar per label, scaled so the largest value
+// fills width characters, followed by the value. Labels are padded to a
+// common width so the bars line up.
+func barChart(labels []string, values []int, width int) string {
+	labelWidth, peak := maxWidth(labels...), 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	lines := make([]string, len(labels))
+	for i, label := range labels {
+		filled := 0
+		if peak > 0 {
+			filled = values[i] * width / peak
+		}
+		// Never hide a non-zero value behind an empty bar
+		if filled == 0 && values[i] > 0 {
+			filled = 1
+		}
+		lines[i] = fmt.Sprintf("%s %s%s %d", padRight(label, labelWidth), strings.Repeat("█", filled), strings.Repeat(" ", width-filled), values[i])
+	}
+	return strings.Join(lines, "\n")
+}
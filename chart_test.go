@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{nil, ""},
+		{[]int{0, 0}, "  "},
+		{[]int{1, 1}, "██"},
+		{[]int{0, 1, 2, 4, 8}, " ▁▂▄█"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("Expected sparkline(%v) = %q, got %q", tt.values, tt.want, got)
+		}
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{0, 0}, "  "},
+		{[]int{0, 1, 2, 3, 4}, " ░▒▓█"},
+		{[]int{1, 100}, "░█"},
+	}
+	for _, tt := range tests {
+		if got := heatmap(tt.values); got != tt.want {
+			t.Errorf("Expected heatmap(%v) = %q, got %q", tt.values, tt.want, got)
+		}
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	got := barChart([]string{"Oolong", "Sencha", "Mate"}, []int{4, 2, 0}, 8)
+	want := "Oolong ████████ 4\n" +
+		"Sencha ████     2\n" +
+		"Mate            0"
+	if got != want {
+		t.Errorf("Expected bar chart\n%s\ngot\n%s", want, got)
+	}
+
+	// Small values still get a visible bar
+	if got := barChart([]string{"A", "B"}, []int{100, 1}, 10); got != "A ██████████ 100\nB █          1" {
+		t.Errorf("Expected a one-character bar for a small value, got\n%s", got)
+	}
+}
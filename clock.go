@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// now returns the current wall-clock time. Tests replace it to render
+// clock times deterministically.
+var now = time.Now
+
+// clockMsg carries the wall-clock time at the start of a new minute. It keeps
+// the "ready at" preview current while no timer is running, without the cost
+// of per-second ticks.
+type clockMsg time.Time
+
+// clockTick returns a command that delivers a clockMsg at the start of the
+// next minute.
+func clockTick() tea.Cmd {
+	t := now()
+	return tea.Tick(t.Truncate(time.Minute).Add(time.Minute).Sub(t), func(time.Time) tea.Msg {
+		return clockMsg(now())
+	})
+}
+
+// readyAt returns the wall-clock time the current or next brew will be ready,
+// and false when there is nothing to finish: for a finished brew and for the
+// stopwatch. A running brew finishes at its deadline; an idle or paused one
+// would finish its remaining time from now.
+func (m model) readyAt() (time.Time, bool) {
+	switch {
+	case m.stopwatch || m.isFinished():
+		return time.Time{}, false
+	case m.isBrewing():
+		return m.deadline, true
+	default:
+		return m.clock.Add(m.timer), true
+	}
+}
+
+// readyAtLabel formats readyAt for display, or returns "" when there is none.
+func (m model) readyAtLabel() string {
+	at, ok := m.readyAt()
+	if !ok {
+		return ""
+	}
+	return "ready at " + m.config.Formats.clock(at)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadyAt(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	start := now()
+
+	// Idle previews the finish time of the selected preset
+	if got := mdl.readyAtLabel(); got != "ready at "+start.Add(config.Presets[0].Duration).Format("15:04") {
+		t.Errorf("Unexpected idle preview %q", got)
+	}
+
+	// A running brew finishes at its deadline
+	newModel, _ := mdl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m := newModel.(model)
+	if at, ok := m.readyAt(); !ok || !at.Equal(start.Add(m.timer)) {
+		t.Errorf("Expected deadline %v, got %v", start.Add(m.timer), at)
+	}
+
+	// A paused brew moves its finish time along with the clock
+	m = m.pause()
+	newModel, _ = m.Update(clockMsg(start.Add(10 * time.Minute)))
+	m = newModel.(model)
+	if at, _ := m.readyAt(); !at.Equal(start.Add(10*time.Minute + m.timer)) {
+		t.Errorf("Expected paused finish time to follow the clock, got %v", at)
+	}
+
+	m.state = StateFinished
+	if got := m.readyAtLabel(); got != "" {
+		t.Errorf("Expected no finish time once finished, got %q", got)
+	}
+	m.stopwatch, m.state = true, StateBrewing
+	if got := m.readyAtLabel(); got != "" {
+		t.Errorf("Expected no finish time for the stopwatch, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// collectAction is the key of the notification action that collects the
+// finished brew.
+const collectAction = "collect"
+
+// collectMsg acknowledges the finished brew from outside the keyboard, such
+// as the Collect button of its notification.
+type collectMsg struct{}
+
+// remindMsg alerts again about a brew that finished but wasn't collected.
+// It carries the tick chain ID at the time the brew finished, so it is
+// ignored once another brew has started.
+type remindMsg struct {
+	id int
+}
+
+// coldMsg fires when a finished brew has waited alerts.cold_after without
+// being collected. Like remindMsg it belongs to the brew that finished.
+type coldMsg struct {
+	id int
+}
+
+// acknowledging reports whether finished brews wait to be collected, which
+// they do when reminders or the going-cold timer are set.
+func (c *Config) acknowledging() bool {
+	return c.Remind > 0 || c.ColdAfter > 0
+}
+
+// waitForCollection starts the reminders and the going-cold timer of the
+// brew that just finished at t.
+func (m model) waitForCollection(t time.Time) (model, tea.Cmd) {
+	m.readySince, m.collected, m.cold = t, false, false
+	id := m.tickID
+	var cmds []tea.Cmd
+	if m.config.Remind > 0 {
+		cmds = append(cmds, tea.Tick(m.config.Remind, func(time.Time) tea.Msg { return remindMsg{id: id} }))
+	}
+	if m.config.ColdAfter > 0 {
+		cmds = append(cmds, tea.Tick(m.config.ColdAfter, func(time.Time) tea.Msg { return coldMsg{id: id} }))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// waiting reports whether the brew that finished with tick chain id is on
+// screen and still waiting to be collected.
+func (m model) waiting(id int) bool {
+	return m.isFinished() && id == m.tickID && !m.collected && !m.cold
+}
+
+// collect acknowledges that the finished brew was collected, which stops
+// its alert, reminders and going-cold timer.
+func (m model) collect() (model, tea.Cmd) {
+	if !m.waiting(m.tickID) {
+		return m, nil
+	}
+	m.collected = true
+	m = m.silence()
+	m, status := m.showStatus(fmt.Sprintf("Enjoy your %s", m.brewTea))
+	return m, tea.Batch(status, m.brewEventCmd(now(), BrewCollected))
+}
+
+// remind alerts again about a brew still waiting to be collected, as a
+// critical notification, and schedules the next reminder.
+func (m model) remind(id int) (model, tea.Cmd) {
+	if !m.waiting(id) {
+		return m, nil
+	}
+	config := *m.config
+	config.NotifyUrgency = UrgencyCritical
+	body := fmt.Sprintf("Your %s is waiting, ready since %s", m.brewTea, m.config.Formats.clock(m.readySince))
+	m = m.silence()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopAlert = cancel
+	next := tea.Tick(m.config.Remind, func(time.Time) tea.Msg { return remindMsg{id: id} })
+	return m, tea.Batch(alertCmd(ctx, &config, body), next)
+}
+
+// goneCold marks a brew that waited too long as gone cold: it is flagged
+// abandoned in the history and its abandoned event is sent.
+func (m model) goneCold(id int) (model, tea.Cmd) {
+	if !m.waiting(id) {
+		return m, nil
+	}
+	m.cold = true
+	m = m.silence()
+	cmds := []tea.Cmd{m.brewEventCmd(now(), BrewAbandoned)}
+	if !m.finishedAt.IsZero() {
+		m.history = slices.Clone(m.history)
+		for i := range m.history {
+			if m.history[i].Time.Equal(m.finishedAt) {
+				m.history[i].Abandoned = true
+			}
+		}
+		cmds = append(cmds, abandonBrewCmd(m.config.HistoryFile, m.config.HistoryKey, m.finishedAt))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// collectLabel describes a finished brew waiting to be collected: how long
+// it has been ready, when it goes cold and how to collect it.
+func (m model) collectLabel() string {
+	if !m.isFinished() || !m.config.acknowledging() || m.readySince.IsZero() {
+		return ""
+	}
+	switch {
+	case m.collected:
+		return m.icon("✅", "") + "Collected"
+	case m.cold:
+		return m.icon("🥶", "") + "Gone cold before it was collected"
+	}
+	waited := max(0, m.clock.Sub(m.readySince))
+	label := fmt.Sprintf("Ready %d min ago", int(waited/time.Minute))
+	if m.config.ColdAfter > 0 {
+		left := max(0, m.config.ColdAfter-waited)
+		label += fmt.Sprintf(" · cold in %d min", int((left+time.Minute-1)/time.Minute))
+	}
+	return m.icon("☕", "") + label + fmt.Sprintf(" · press '%s' to collect", m.config.Keys.Collect)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// finishedForCollection returns a model whose brew has just finished with
+// reminders and the going-cold timer set, the brew log at a temporary path.
+func finishedForCollection(t *testing.T) model {
+	t.Helper()
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled = false, false
+	config.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	config.Remind = time.Minute
+	config.ColdAfter = 10 * time.Minute
+	m := initialModel(config)
+	m.width, m.height = 80, 30
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	m = newModel.(model)
+	m.timer = time.Second
+	// The finish's commands would wait out the reminder and the cold timer,
+	// so only the brew is logged
+	newModel, _ = m.Update(tickLeft(m, 0))
+	m = newModel.(model)
+	if err := appendHistory(config.HistoryFile, nil, m.history[len(m.history)-1]); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCollectFinishedBrew(t *testing.T) {
+	m := finishedForCollection(t)
+	if view := m.View(); !strings.Contains(view, "Ready 0 min ago · cold in 10 min · press 'x' to collect") {
+		t.Errorf("Expected the going-cold timer on the finished screen, got:\n%s", view)
+	}
+
+	// Reminders escalate to critical notifications with a Collect button
+	m.config.NotifyEnabled = true
+	defer func(f func(title, body string, opts notifyOptions) error) { sendNotification = f }(sendNotification)
+	var sent notifyOptions
+	sendNotification = func(_, _ string, opts notifyOptions) error {
+		sent = opts
+		return nil
+	}
+	m.config.Remind = time.Millisecond // Don't wait a minute for the next one
+	newModel, cmd := m.Update(remindMsg{id: m.tickID})
+	m = newModel.(model)
+	if cmd == nil {
+		t.Fatal("Expected a reminder")
+	}
+	cmdMsgs(cmd)
+	if sent.Urgency != UrgencyCritical || !sent.Collect {
+		t.Errorf("Expected a critical notification with a Collect button, got %+v", sent)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCollect)})
+	m = newModel.(model)
+	if !m.collected || !strings.Contains(m.View(), "Collected") {
+		t.Errorf("Expected the brew collected, got:\n%s", m.View())
+	}
+	if _, cmd := m.Update(remindMsg{id: m.tickID}); cmd != nil {
+		t.Error("Expected no more reminders once collected")
+	}
+	if newModel, _ := m.Update(coldMsg{id: m.tickID}); newModel.(model).cold {
+		t.Error("Expected a collected brew not to go cold")
+	}
+}
+
+func TestBrewGoesCold(t *testing.T) {
+	m := finishedForCollection(t)
+	newModel, cmd := m.Update(coldMsg{id: m.tickID})
+	m = newModel.(model)
+	for _, msg := range cmdMsgs(cmd) {
+		if err, ok := msg.(errMsg); ok {
+			t.Fatal(err.err)
+		}
+	}
+	if !m.cold || !strings.Contains(m.View(), "Gone cold before it was collected") {
+		t.Errorf("Expected the brew gone cold, got:\n%s", m.View())
+	}
+	records, err := loadHistory(m.config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || !records[0].Abandoned {
+		t.Errorf("Expected the brew flagged abandoned, got %+v, %v", records, err)
+	}
+	events := readEvents(t, eventsPath(m.config.HistoryFile))
+	if last := events[len(events)-1]; last.Type != BrewAbandoned || last.Brew != records[0].ID {
+		t.Errorf("Expected an abandoned event for the brew, got %+v", last)
+	}
+
+	// Collecting it now changes nothing
+	if _, cmd := m.Update(collectMsg{}); cmd != nil {
+		t.Error("Expected a cold brew not to be collected")
+	}
+}
+
+func TestRemindConfig(t *testing.T) {
+	fc, _, err := loadConfigFile(writeConfig(t, "[alerts]\nremind = \"10s\"\ncold_after = \"-1m\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := fc.validate(); len(errs) != 2 {
+		t.Errorf("Expected the short reminder and negative cold_after to be rejected, got %v", errs)
+	}
+}
@@ -1,32 +1,63 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"slices"
 	"time"
 )
 
 // Constants contain application-wide configuration values and defaults.
 const (
-	DefaultBrewTime      = 4 * time.Minute
-	MinBrewTime         = 30 * time.Second
-	MaxBrewTime         = 30 * time.Minute
-	DefaultProgressBarWidth = 20
+	DefaultBrewTime         = 4 * time.Minute
+	MinBrewTime             = 30 * time.Second
+	MaxBrewTime             = 30 * time.Minute
+	DefaultProgressBarWidth = 20 // Until the terminal size is known
+	MinProgressBarWidth     = 10
+	MaxProgressBarWidth     = 60
+	DefaultHalfwayCue       = 50               // Percent of the brew
+	DefaultFinalCue         = 10 * time.Second // Remaining time
+	DefaultTickInterval     = time.Second
+	MinTickInterval         = 100 * time.Millisecond // Smooth progress bars
+	MaxTickInterval         = 5 * time.Second        // Slow SSH links
+	MinRemind               = 30 * time.Second       // Reminders no more often than this
 
 	// Colors
 	ColorReady   = "#00FF7F"
 	ColorBrewing = "#FFD93D"
 	ColorPaused  = "#FFA500"
 	ColorIdle    = "#AAAAAA"
+	ColorHalfway = "#F4A261"
+	ColorFinal   = "#FF6B6B"
 
 	// Keys
-	KeyStart   = "s"
-	KeyReset   = "r"
-	KeyQuit    = "q"
-	KeyQuitAlt = "ctrl+c"
-	KeyPause   = "space"
-	KeyUp      = "up"
-	KeyDown    = "down"
+	KeyStart     = "s"
+	KeyReset     = "r"
+	KeyQuit      = "q"
+	KeyQuitAlt   = "ctrl+c"
+	KeySuspend   = "ctrl+z"
+	KeyPause     = "space"
+	KeyUp        = "up"
+	KeyDown      = "down"
+	KeyDuration  = "d"
+	KeyUndo      = "u"
+	KeyStopwatch = "w"
+	KeyLap       = "l"
+	KeyStats     = "h"
+	KeyNote      = "n"
+	KeyLonger    = "+"
+	KeyCopy      = "c"
+	KeyEdit      = "e"
+	KeyShorter   = "-"
+	KeyCatalog   = "b"
+	KeyInfo      = "i"
+	KeyCool      = "k"
+	KeyRate      = "v"
+	KeySuggest   = "a"
+	KeyCalc      = "g"
+	KeyVessel    = "m"
+	KeyCollect   = "x"
 )
 
 // TimerState represents the current state of the timer in the brewing lifecycle.
@@ -44,6 +75,120 @@ const (
 	StateFinished
 )
 
+// Palette holds the colors used for each timer state. Values are lipgloss
+// colors: hex strings like "#FFA500" or ANSI color numbers.
+type Palette struct {
+	Ready   string // Tea is ready
+	Brewing string // Timer is counting down
+	Paused  string // Timer is paused
+	Idle    string // Waiting to start
+	Halfway string // Countdown is past the halfway cue
+	Final   string // Countdown is in its final stretch
+}
+
+// KeyMap holds the keys bound to each action, using Bubbletea key names.
+// ctrl+c always quits in addition to the Quit key.
+type KeyMap struct {
+	Start     string // Start a brew
+	Pause     string // Pause or resume the running brew
+	Reset     string // Reset the timer
+	Quit      string // Quit the application
+	Up        string // Select the previous preset
+	Down      string // Select the next preset
+	Duration  string // Type in a custom duration for the next brew
+	Undo      string // Undo the last reset or preset change
+	Stopwatch string // Switch between the countdown timer and the stopwatch
+	Lap       string // Record a lap on the running stopwatch
+	Stats     string // Show or hide the brew history stats screen
+	Note      string // Attach a note to the current brew
+	Copy      string // Duplicate the selected preset under a new name
+	Edit      string // Change the selected preset's duration
+	Longer    string // Lengthen the next brew, faster while held
+	Shorter   string // Shorten the next brew, faster while held
+	Catalog   string // Browse the built-in tea catalog
+	Info      string // Show or hide the selected preset's details
+	Cool      string // Start or stop timing the water cooling off the boil
+	Rate      string // Rate the finished brew
+	Suggest   string // Brew for the duration suggested by your ratings
+	Calc      string // Open or close the leaf calculator
+	Vessel    string // Select the next vessel to brew in
+	Collect   string // Acknowledge that the finished brew has been collected
+}
+
+// DefaultKeys are the key bindings used when the config file sets none.
+var DefaultKeys = KeyMap{
+	Start:     KeyStart,
+	Pause:     KeyPause,
+	Reset:     KeyReset,
+	Quit:      KeyQuit,
+	Up:        KeyUp,
+	Down:      KeyDown,
+	Duration:  KeyDuration,
+	Undo:      KeyUndo,
+	Stopwatch: KeyStopwatch,
+	Lap:       KeyLap,
+	Stats:     KeyStats,
+	Note:      KeyNote,
+	Copy:      KeyCopy,
+	Edit:      KeyEdit,
+	Longer:    KeyLonger,
+	Shorter:   KeyShorter,
+	Catalog:   KeyCatalog,
+	Info:      KeyInfo,
+	Cool:      KeyCool,
+	Rate:      KeyRate,
+	Suggest:   KeySuggest,
+	Calc:      KeyCalc,
+	Vessel:    KeyVessel,
+	Collect:   KeyCollect,
+}
+
+// bindings returns the help entries describing the key map.
+func (k KeyMap) bindings() []KeyBinding {
+	return []KeyBinding{
+		{k.Start, "Start timer"},
+		{k.Pause, "Pause/Resume"},
+		{k.Reset, "Reset timer"},
+		{k.Up + "/" + k.Down, "Select preset"},
+		{"1-9", "Select preset by number"},
+		{k.Copy + "/" + k.Edit, "Duplicate/edit preset"},
+		{k.Duration, "Type a custom duration"},
+		{k.Longer + "/" + k.Shorter, "Adjust duration (hold to speed up)"},
+		{k.Undo, "Undo reset or preset change"},
+		{k.Stopwatch, "Toggle stopwatch"},
+		{k.Lap, "Record a lap"},
+		{k.Note, "Add a note to this brew"},
+		{k.Cool, "Cool water from the boil"},
+		{k.Info, "Show tea details"},
+		{k.Catalog, "Browse tea catalog"},
+		{k.Stats, "Brew history stats"},
+		{k.Quit + "/" + KeyQuitAlt, "Quit"},
+	}
+}
+
+// bindings returns the help entries for the configured keys and behavior.
+func (c *Config) bindings() []KeyBinding {
+	bindings := c.Keys.bindings()
+	for i := range bindings {
+		if bindings[i].Key != "1-9" {
+			continue
+		}
+		switch {
+		case c.DigitEntry && !c.Kiosk:
+			bindings[i] = KeyBinding{"0-9", "Type a duration (230 = 2:30)"}
+		case c.QuickStart:
+			bindings[i].Desc = "Start preset by number"
+		}
+	}
+	if c.EnterStarts != "" {
+		bindings = slices.Insert(bindings, 1, KeyBinding{"enter", "Start " + c.EnterStarts})
+	}
+	if c.Kiosk {
+		bindings = c.kioskBindings(bindings)
+	}
+	return bindings
+}
+
 // KeyBinding represents a keyboard shortcut and its user-facing description.
 // This provides a flexible way to map keyboard input to actions.
 type KeyBinding struct {
@@ -55,35 +200,133 @@ type KeyBinding struct {
 // information for proper tea preparation. Each preset includes brew time,
 // recommended temperature, and helpful notes for the best results.
 type TeaPreset struct {
-	Name     string        // Human-readable name of the tea type
-	Duration time.Duration // Recommended brewing time
-	Temp     string        // Recommended water temperature
-	Notes    string        // Additional brewing notes or tips
+	Name     string          // Human-readable name of the tea type
+	Duration time.Duration   // Recommended brewing time
+	Temp     string          // Recommended water temperature
+	Notes    string          // Additional brewing notes or tips
+	Steeps   []time.Duration // Durations of successive infusions, nil for a single Duration
+	Info     PresetInfo      // Optional details shown in the info panel
 }
 
+// PresetInfo holds optional background on a tea, shown in the idle view's
+// info panel. Empty fields are left out.
+type PresetInfo struct {
+	Origin   string // Where the tea is grown, e.g. "Fujian, China"
+	Caffeine string // Caffeine level: none, low, medium or high
+	Flavor   string // Tasting notes
+	Ratio    string // Leaf-to-water ratio, e.g. "3g per 250ml"
+}
+
+// Caffeine levels accepted for PresetInfo.Caffeine.
+var caffeineLevels = []string{"none", "low", "medium", "high"}
+
 // DefaultTeaPresets contains carefully selected tea presets for common tea types.
 // These presets are based on standard brewing recommendations and provide
 // excellent starting points for different tea varieties.
 var DefaultTeaPresets = []TeaPreset{
-	{"Rooibos", 4 * time.Minute, "95°C", "No bitterness, naturally sweet"},
-	{"Green Tea", 2 * time.Minute, "80°C", "Don't overbrew to avoid bitterness"},
-	{"Black Tea", 3 * time.Minute, "95°C", "Full flavor development"},
-	{"Herbal", 5 * time.Minute, "95°C", "Medicinal properties develop over time"},
-	{"White Tea", 2 * time.Minute, "75°C", "Delicate flavor, careful timing"},
-	{"Oolong", 3 * time.Minute, "85°C", "Complex flavors, multiple infusions possible"},
+	{"Rooibos", 4 * time.Minute, "95°C", "No bitterness, naturally sweet", nil,
+		PresetInfo{"South Africa", "none", "Honeyed, nutty, hint of vanilla", "3g per 250ml"}},
+	{"Green Tea", 2 * time.Minute, "80°C", "Don't overbrew to avoid bitterness", nil,
+		PresetInfo{"China, Japan", "medium", "Grassy, vegetal, slightly sweet", "2g per 250ml"}},
+	{"Black Tea", 3 * time.Minute, "95°C", "Full flavor development", nil,
+		PresetInfo{"India, Sri Lanka, China", "high", "Malty, brisk, takes milk well", "2.5g per 250ml"}},
+	{"Herbal", 5 * time.Minute, "95°C", "Medicinal properties develop over time", nil,
+		PresetInfo{"Worldwide", "none", "Floral, minty or fruity depending on the blend", "3g per 250ml"}},
+	{"White Tea", 2 * time.Minute, "75°C", "Delicate flavor, careful timing", nil,
+		PresetInfo{"Fujian, China", "low", "Sweet, hay, light melon", "3g per 250ml"}},
+	{"Oolong", 3 * time.Minute, "85°C", "Complex flavors, multiple infusions possible", nil,
+		PresetInfo{"Taiwan, Fujian", "medium", "Floral to toasty depending on the roast", "3g per 250ml"}},
 }
 
 // Config holds all application configuration including user settings,
 // tea presets, key bindings, and preferences. It provides a centralized
 // location for all configurable aspects of the application.
 type Config struct {
-	BrewTime       time.Duration // Default brew time when no preset is selected
-	SoundEnabled   bool          // Whether to play audio alerts when tea is ready
-	NotifyEnabled  bool          // Whether to show desktop notifications
-	ShowVersion    bool          // Whether to show version information and exit
-	CustomDuration bool          // Whether custom duration was set via -duration flag
-	KeyBindings    []KeyBinding  // List of keyboard shortcuts and their descriptions
-	Presets        []TeaPreset   // Available tea presets with their brewing parameters
+	BrewTime            time.Duration  // Default brew time when no preset is selected
+	SoundEnabled        bool           // Whether to play audio alerts when tea is ready
+	NotifyEnabled       bool           // Whether to show desktop notifications
+	NotificationSound   bool           // Whether desktop notifications play the desktop's own chime
+	QuietHours          QuietHours     // Daily span such as "22:00-07:00" in which alerts make no sound, "" for none
+	NotifyUrgency       string         // Urgency of Linux notifications: UrgencyLow, UrgencyNormal or UrgencyCritical
+	NotifyExpire        time.Duration  // How long Linux notifications stay up, 0 for the desktop's default
+	Remind              time.Duration  // How often to alert again until a finished brew is collected, 0 for never
+	ColdAfter           time.Duration  // How long a finished brew can wait to be collected before it goes cold, 0 for ever
+	NotifyStart         bool           // Whether to also notify when a brew starts
+	NotifyPause         bool           // Whether to notify when a brew has been paused for pauseReminderAfter
+	NotifyResume        bool           // Whether to notify when a paused brew resumes
+	Speaker             string         // Sonos speaker to play the alert on, by room name or address
+	GPIOPin             int            // GPIO pin pulsed when tea is ready, -1 to disable
+	Light               LightConfig    // Smart light blinked when tea is ready
+	Routes              AlertRoutes    // Channels each event fires, for the events that don't use the defaults
+	Sync                SyncConfig     // Remote "go-brew sync" keeps the config and history in
+	ShowVersion         bool           // Whether to show version information and exit
+	CustomDuration      bool           // Whether a custom duration was set via -duration or the config file
+	QuickStart          bool           // Whether number keys start the chosen preset right away
+	ConfirmQuit         bool           // Whether quitting during a brew needs a second press
+	ConfirmDuration     bool           // Whether a custom brew far from the preset's time needs a second press
+	PauseOnSuspend      bool           // Whether a brew pauses while go-brew is suspended with ctrl+z
+	DigitEntry          bool           // Whether digit keys type a duration instead of picking presets
+	EnterStarts         string         // Preset enter starts right away, "favorite" for the most brewed, "" to disable
+	HalfwayCue          int            // Percent of the brew after which the halfway accent shows, 0 to disable
+	FinalCue            time.Duration  // Remaining time from which the final stretch accent shows, 0 to disable
+	CueSound            bool           // Whether to play a soft sound at each cue
+	BoilTemp            int            // Temperature of boiling water in °C, for the cool-down estimate
+	RoomTemp            int            // Room temperature in °C the water cools towards
+	CoolHalfLife        time.Duration  // Time for the water to lose half its heat above room temperature
+	ConfigPath          string         // Path of the config file to load
+	CPUProfile          string         // File to write a CPU profile to, if set
+	MemProfile          string         // File to write a heap profile to on exit, if set
+	TraceFile           string         // File to write an execution trace to, if set
+	RecordFile          string         // File to record the session to for "go-brew replay", if set
+	OverlayFile         string         // Text or HTML file kept showing the countdown for stream overlays, if set
+	Plain               bool           // Print a line of text per second instead of the interactive UI
+	Kiosk               bool           // Run as a guest-facing kiosk with a large timer and no quitting
+	KioskPresets        []string       // Names of the presets a kiosk offers, all when empty
+	SimulateFinishAfter time.Duration  // Finish each brew this long after it starts, for automation; 0 to brew normally
+	PprofAddr           string         // Address to serve net/http/pprof on, if set
+	HistoryFile         string         // Brew log to append finished brews to, empty to disable
+	HistoryLimits       HistoryLimits  // How much of the brew log to keep
+	HistoryEncrypt      bool           // Whether the brew log is encrypted
+	HistoryKeyfile      string         // File holding the key of the encrypted brew log, instead of a passphrase
+	HistoryKey          *historyKey    // Key of the encrypted brew log, nil for plain text, set by main
+	ScaleDevice         string         // Bluetooth address or name of the scale to weigh leaf on, empty to disable
+	HookURL             string         // URL brew events are posted to, empty to disable
+	HookQueuePath       string         // Brew events waiting to be posted, empty to post without a queue, set by main
+	ExitOnFinish        time.Duration  // Quit this long after a brew finishes, 0 to stay open
+	Stopwatch           bool           // Whether to start in stopwatch mode
+	AlarmTime           string         // Wall-clock time given with -at, e.g. "14:45"
+	AlarmAt             time.Time      // Next occurrence of AlarmTime, set by main
+	SequenceFile        string         // File of timer steps to run one after another, "-" for stdin
+	Sequence            []sequenceStep // Steps loaded from SequenceFile or of the Recipe
+	Recipe              string         // Guided recipe given with -recipe, e.g. "matcha"
+	BlindTest           string         // Two presets given with -blind to compare, e.g. "Black Tea,Oolong"
+	Blind               []int          // Indexes of the BlindTest presets, set by main
+	TimeFormat          TimeFormat     // How remaining time is written
+	Locale              string         // Locale for clock times, dates and temperatures, "" to follow the environment
+	Formats             localeFormats  // Formats of Locale, resolved by main
+	Images              bool           // Whether to draw a picture of the selected tea where the terminal can
+	ImageProtocol       string         // Image protocol the terminal supports, set by main
+	ShowStrength        bool           // Whether to show the estimated strength of the brewing cup
+	Emoji               bool           // Whether to decorate the view with emoji rather than text labels
+	Screensaver         time.Duration  // Idle time before the screen dims to a clock, 0 to disable
+	TickInterval        time.Duration  // Brew time counted, and screen refreshed, per timer tick
+	TaskbarProgress     bool           // Whether to show the brew's progress on the terminal's taskbar icon
+	TaskbarTerminal     bool           // Whether the terminal supports taskbar progress, set by main
+	Telemetry           bool           // Whether the user opted in to usage counts, set by main
+	AskTelemetry        bool           // Whether to ask about usage counts on this first run, set by main
+	TelemetryPath       string         // Telemetry consent and counts, empty to disable
+	TelemetryEndpoint   string         // URL usage reports are posted to, empty to keep counts local
+	Theme               Theme          // Built-in palette the colors start from
+	Colors              Palette        // Colors used for each timer state
+	Keys                KeyMap         // Keys bound to each action
+	KeyBindings         []KeyBinding   // List of keyboard shortcuts and their descriptions
+	Presets             []TeaPreset    // Available tea presets with their brewing parameters
+	Vessels             []Vessel       // Cups and pots the leaf calculator lists
+	Vessel              string         // Name of the vessel selected at startup, empty for none
+	VesselTiming        bool           // Whether the selected vessel nudges preset brew times
+
+	Sources  map[string]string // Where each non-default setting came from, by setting key
+	setFlags map[string]bool   // Names of flags given explicitly on the command line
 }
 
 // NewConfig creates a new Config instance with sensible default values.
@@ -91,17 +334,33 @@ type Config struct {
 // and enabled audio/notification features for the best user experience.
 func NewConfig() *Config {
 	return &Config{
-		BrewTime:      DefaultBrewTime,
-		SoundEnabled:  true,
-		NotifyEnabled: true,
-		Presets:       DefaultTeaPresets,
-		KeyBindings: []KeyBinding{
-			{"s", "Start timer"},
-			{KeyPause, "Pause/Resume"},
-			{"r", "Reset timer"},
-			{KeyUp + "/" + KeyDown, "Select preset"},
-			{"q/ctrl+c", "Quit"},
-		},
+		BrewTime:        DefaultBrewTime,
+		SoundEnabled:    true,
+		NotifyEnabled:   true,
+		NotifyUrgency:   UrgencyNormal,
+		GPIOPin:         -1,
+		Light:           LightConfig{Color: DefaultLightColor},
+		ConfirmQuit:     true,
+		HalfwayCue:      DefaultHalfwayCue,
+		FinalCue:        DefaultFinalCue,
+		BoilTemp:        DefaultBoilTemp,
+		RoomTemp:        DefaultRoomTemp,
+		CoolHalfLife:    DefaultCoolHalfLife,
+		ConfigPath:      defaultConfigPath(),
+		HistoryFile:     defaultHistoryPath(),
+		Presets:         DefaultTeaPresets,
+		Vessels:         DefaultVessels,
+		TimeFormat:      FormatClock,
+		Formats:         defaultLocale,
+		ShowStrength:    true,
+		Emoji:           true,
+		TaskbarProgress: true,
+		TickInterval:    DefaultTickInterval,
+		Theme:           ThemeDefault,
+		Colors:          themePalettes[ThemeDefault],
+		Keys:            DefaultKeys,
+		KeyBindings:     DefaultKeys.bindings(),
+		Sources:         make(map[string]string),
 	}
 }
 
@@ -109,27 +368,103 @@ func NewConfig() *Config {
 // This prevents invalid configurations that could cause runtime errors or
 // poor user experience. Returns an error if validation fails.
 func (c *Config) Validate() error {
-	if c.BrewTime < MinBrewTime {
+	if err := checkBrewTime(c.BrewTime); err != nil {
+		return err
+	}
+	if len(c.Presets) == 0 {
+		return fmt.Errorf("at least one tea preset is required")
+	}
+	if c.AlarmTime != "" && (c.flagSet("duration") || c.SequenceFile != "" || c.Recipe != "" || c.Stopwatch) {
+		return fmt.Errorf("-at cannot be combined with -duration, -sequence, -recipe or -stopwatch")
+	}
+	if c.Recipe != "" && (c.SequenceFile != "" || c.Stopwatch) {
+		return fmt.Errorf("-recipe cannot be combined with -sequence or -stopwatch")
+	}
+	if c.BlindTest != "" && (c.flagSet("duration") || c.AlarmTime != "" || c.SequenceFile != "" || c.Recipe != "" || c.Stopwatch || c.Plain) {
+		return fmt.Errorf("-blind cannot be combined with -duration, -at, -sequence, -recipe, -stopwatch or -plain")
+	}
+	if err := c.Light.validate(); err != nil {
+		return err
+	}
+	if err := c.Sync.validate(); err != nil {
+		return err
+	}
+	if errs := c.keyConflicts(); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := c.checkEnterStarts(); err != nil {
+		return err
+	}
+	if err := c.checkVessel(); err != nil {
+		return err
+	}
+	if c.Kiosk && c.Plain {
+		return fmt.Errorf("-kiosk needs the interactive UI and cannot be combined with -plain")
+	}
+	if c.Plain && c.RecordFile != "" {
+		return fmt.Errorf("-record needs the interactive UI and cannot be combined with -plain")
+	}
+	if c.ExitOnFinish < 0 {
+		return fmt.Errorf("exit-on-finish delay cannot be negative")
+	}
+	if c.RoomTemp >= c.BoilTemp {
+		return fmt.Errorf("cooling.room must be below cooling.boil")
+	}
+	return nil
+}
+
+// checkBrewTime reports whether d is within the supported brew time range.
+func checkBrewTime(d time.Duration) error {
+	if d < MinBrewTime {
 		return fmt.Errorf("brew time must be at least %v", MinBrewTime)
 	}
-	if c.BrewTime > MaxBrewTime {
+	if d > MaxBrewTime {
 		return fmt.Errorf("brew time cannot exceed %v", MaxBrewTime)
 	}
 	return nil
 }
 
 // ParseFlags parses command line flags and updates the configuration accordingly.
-// Currently supports the -duration flag for custom brew times and -version flag.
+// It supports the -duration flag for custom brew times, the -version flag, and
+// the profiling flags used to diagnose rendering and audio performance.
 // This should be called after NewConfig() but before Validate().
 func (c *Config) ParseFlags() {
 	flag.DurationVar(&c.BrewTime, "duration", c.BrewTime, "brew time for the tea timer")
 	flag.BoolVar(&c.ShowVersion, "version", false, "show version information and exit")
+	flag.StringVar(&c.ConfigPath, "config", c.ConfigPath, "load settings from config `file`")
+	flag.StringVar(&c.SequenceFile, "sequence", "", "run the timers listed in `file` one after another (- reads stdin)")
+	flag.StringVar(&c.AlarmTime, "at", "", "count down to wall-clock `time` such as 14:45, 2:45pm or \"14:45 Europe/London\" and start right away")
+	flag.StringVar(&c.Recipe, "recipe", "", "follow a guided `recipe` step by step, e.g. matcha")
+	flag.BoolVar(&c.Stopwatch, "stopwatch", false, "start as a stopwatch that counts up with laps")
+	flag.StringVar(&c.BlindTest, "blind", "", "blind taste test: brew one of two `presets` such as \"Black Tea,Oolong\" at random, revealed once rated")
+	flag.DurationVar(&c.ExitOnFinish, "exit-on-finish", 0, "quit this long after the brew finishes, e.g. 10s (0 stays open)")
+	flag.StringVar(&c.CPUProfile, "cpuprofile", "", "write a CPU profile to `file`")
+	flag.StringVar(&c.MemProfile, "memprofile", "", "write a heap profile to `file` on exit")
+	flag.StringVar(&c.TraceFile, "trace", "", "write an execution trace to `file`")
+	flag.StringVar(&c.PprofAddr, "pprof", "", "serve net/http/pprof on `addr` (e.g. localhost:6060)")
+	flag.StringVar(&c.RecordFile, "record", "", "record every message the timer receives to `file`, for go-brew replay")
+	flag.StringVar(&c.OverlayFile, "overlay", c.OverlayFile, "keep the countdown in `file` for OBS and other streaming software (.html for a browser source)")
+	flag.BoolVar(&c.Kiosk, "kiosk", false, "run as a guest-facing kiosk: large timer, kiosk.presets only, no quitting or editing presets")
+	flag.BoolVar(&c.Plain, "plain", false, "start right away and print one line per second without control codes, for logs and CI")
+	flag.DurationVar(&c.SimulateFinishAfter, "simulate-finish-after", 0, "finish each brew this long after it starts, for testing the alerts")
+	flag.Usage = printUsage
 	flag.Parse()
 
-	// Check if duration flag was actually used by checking if it was provided in command line
+	// Remember which flags were given so config file values don't override them
+	c.setFlags = make(map[string]bool)
 	flag.Visit(func(f *flag.Flag) {
-		if f.Name == "duration" {
-			c.CustomDuration = true
-		}
+		c.setFlags[f.Name] = true
 	})
+	if c.flagSet("duration") {
+		c.CustomDuration = true
+		c.Sources["duration"] = "flag -duration"
+	}
+	if c.flagSet("overlay") {
+		c.Sources["overlay.file"] = "flag -overlay"
+	}
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func (c *Config) flagSet(name string) bool {
+	return c.setFlags[name]
 }
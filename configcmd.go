@@ -0,0 +1,222 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BurntSushi/toml"
+)
+
+// runConfigCommand implements the "go-brew config" subcommands and returns
+// the process exit code:
+//
+//	go-brew config validate [-config file]  check config files and environment for errors
+//	go-brew config show [-config file]      print the effective configuration
+//	go-brew config sources [-config file]   show where each setting came from
+//
+// -config replaces the user's config file, as it does for the timer itself.
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: go-brew config validate|show|sources [-config file]")
+		return 2
+	}
+
+	sub := args[0]
+	fs := flag.NewFlagSet("config "+sub, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to read")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+
+	switch sub {
+	case "validate":
+		return validateConfig(config, stdout, stderr)
+	case "show":
+		return showConfig(config, stdout, stderr)
+	case "sources":
+		return showSources(config, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown config command %q (want validate, show or sources)\n", sub)
+		return 2
+	}
+}
+
+// validateConfig reports every problem found in the config files and the
+// GOBREW_* environment: syntax errors and malformed values with their line
+// number, out-of-range values with the setting they belong to, and unknown
+// settings as warnings. Files in an older schema are checked as migrated but
+// left unmodified. With -config only that file is checked.
+func validateConfig(config *Config, stdout, stderr io.Writer) int {
+	layers := config.layers()
+	if config.flagSet("config") {
+		layers = []configLayer{{name: "user", path: config.ConfigPath, required: true}}
+	}
+
+	failed, checked := false, 0
+	for _, layer := range layers {
+		ok, found := validateConfigFile(layer, stdout, stderr)
+		failed = failed || !ok
+		if found {
+			checked++
+		}
+	}
+
+	if !config.flagSet("config") {
+		if err := config.loadEnv(os.Getenv); err != nil {
+			fmt.Fprintf(stderr, "error: environment: %v\n", err)
+			failed = true
+		}
+	}
+
+	// Each file can be fine on its own and still clash with another's keys
+	if !failed {
+		for _, err := range mergeLayers(layers, !config.flagSet("config")).keyConflicts() {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			failed = true
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	if checked == 0 {
+		fmt.Fprintln(stdout, "no config files found; using defaults")
+	}
+	return 0
+}
+
+// mergeLayers combines already validated config files, and the environment
+// if env is set, the way Load does but without migrating any file.
+func mergeLayers(layers []configLayer, env bool) *Config {
+	merged := NewConfig()
+	for _, layer := range layers {
+		if fc, _, err := loadConfigFile(layer.path); err == nil && fc != nil {
+			merged.applyFile(fc, layer.source())
+		}
+	}
+	if env {
+		merged.loadEnv(os.Getenv)
+	}
+	return merged
+}
+
+// validateConfigFile checks a single config layer and reports whether it is
+// valid and whether the file exists.
+func validateConfigFile(layer configLayer, stdout, stderr io.Writer) (ok, found bool) {
+	fc, meta, err := loadConfigFile(layer.path)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return false, true
+	}
+	if fc == nil {
+		if layer.required {
+			fmt.Fprintf(stderr, "error: %s does not exist\n", layer.path)
+			return false, false
+		}
+		return true, false
+	}
+
+	if meta.Version < configSchemaVersion {
+		fmt.Fprintf(stderr, "note: %s uses config schema version %d; it will be migrated to version %d (with a backup) the next time go-brew starts\n", layer.path, meta.Version, configSchemaVersion)
+	}
+	for _, key := range meta.Unknown {
+		fmt.Fprintf(stderr, "warning: %s: unknown setting %q\n", layer.path, key)
+	}
+	errs := fc.validate()
+	for _, err := range errs {
+		fmt.Fprintf(stderr, "error: %s: %v\n", layer.path, err)
+	}
+	if len(errs) > 0 {
+		return false, true
+	}
+	if len(fc.Presets) > 0 {
+		presets := NewConfig()
+		presets.applyFile(fc, layer.source())
+		for _, w := range lintPresets(presets.Presets) {
+			fmt.Fprintf(stderr, "warning: %s: %s\n", layer.path, w)
+		}
+	}
+
+	fmt.Fprintf(stdout, "%s: ok\n", layer.path)
+	return true, true
+}
+
+// showConfig prints the effective configuration, after merging every layer
+// over the built-in defaults, in config file format.
+func showConfig(config *Config, stdout, stderr io.Writer) int {
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "# Effective configuration (see \"go-brew config sources\" for origins)")
+	fc := config.toFile()
+	// Keep the bridge credential out of shared terminal output, as sources does
+	if l := fc.Alerts.Light; l != nil && l.Token != "" {
+		light := *l
+		light.Token = "(set)"
+		fc.Alerts.Light = &light
+	}
+	enc := toml.NewEncoder(stdout)
+	enc.Indent = ""
+	if err := enc.Encode(fc); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// showSources prints the config files in precedence order and, for every
+// setting, its effective value and the layer it came from.
+func showSources(config *Config, stdout, stderr io.Writer) int {
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "Layers (lowest precedence first):")
+	fmt.Fprintln(stdout, "  default  built-in settings")
+	for _, layer := range config.layers() {
+		status := "not found"
+		if _, err := os.Stat(layer.path); err == nil {
+			status = "loaded"
+		}
+		fmt.Fprintf(stdout, "  %-8s %s (%s)\n", layer.name, layer.path, status)
+	}
+	fmt.Fprintln(stdout, "  env      GOBREW_* environment variables")
+	fmt.Fprintln(stdout, "  flag     command line flags")
+	fmt.Fprintln(stdout)
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+	for _, key := range settingKeys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, config.settingValue(key), config.source(key))
+	}
+	fmt.Fprintf(tw, "alerts.routes\t%s\t%s\n", config.Routes, config.source("alerts.routes"))
+	kiosk := "all"
+	if len(config.KioskPresets) > 0 {
+		kiosk = strings.Join(config.KioskPresets, ", ")
+	}
+	fmt.Fprintf(tw, "kiosk.presets\t%s\t%s\n", kiosk, config.source("kiosk.presets"))
+	fmt.Fprintf(tw, "presets\t%d defined\t%s\n", len(config.Presets), config.source("presets"))
+	return errorExit(tw.Flush(), stderr)
+}
+
+// errorExit reports err, if any, and converts it into an exit code.
+func errorExit(err error, stderr io.Writer) int {
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
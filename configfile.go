@@ -0,0 +1,846 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configFileName is the name of the configuration file inside the
+// application's configuration directory.
+const configFileName = "config.toml"
+
+// Duration is a time.Duration that is written in config files as a Go
+// duration string such as "2m30s". Invalid strings are rejected while the
+// file is decoded, so the error carries the offending line number.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText parses a duration string like "4m" or "2m45s".
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (use values like \"3m\" or \"2m30s\")", text)
+	}
+	d.Duration = v
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
+
+// Color is a terminal color as accepted by lipgloss: a hex value ("#FFA500"
+// or "#FA0") or an ANSI color number from 0 to 255.
+type Color string
+
+// Caffeine is a preset's caffeine level in config.toml: none, low, medium
+// or high.
+type Caffeine string
+
+// UnmarshalText validates and stores a caffeine level, ignoring case.
+func (c *Caffeine) UnmarshalText(text []byte) error {
+	s := strings.ToLower(string(text))
+	if !slices.Contains(caffeineLevels, s) {
+		return fmt.Errorf("invalid caffeine level %q (use %s)", text, strings.Join(caffeineLevels, ", "))
+	}
+	*c = Caffeine(s)
+	return nil
+}
+
+// hexColorPattern matches 3- or 6-digit hex colors with a leading '#'.
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// UnmarshalText validates and stores a color value.
+func (c *Color) UnmarshalText(text []byte) error {
+	s := string(text)
+	if hexColorPattern.MatchString(s) {
+		*c = Color(s)
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		*c = Color(s)
+		return nil
+	}
+	return fmt.Errorf("invalid color %q (use \"#RRGGBB\", \"#RGB\" or an ANSI number 0-255)", s)
+}
+
+// KeyName is a key as reported by Bubbletea, such as "s", "up", "space",
+// "ctrl+r" or "f5".
+type KeyName string
+
+// namedKeys lists the key names that can be bound besides single characters.
+var namedKeys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"enter": true, "space": true, "tab": true, "esc": true,
+	"backspace": true, "delete": true, "insert": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true,
+}
+
+// UnmarshalText validates and stores a key name.
+func (k *KeyName) UnmarshalText(text []byte) error {
+	s := string(text)
+	if !validKeyName(s) {
+		return fmt.Errorf("invalid key %q (use a single character, a name like \"up\" or \"space\", or \"ctrl+x\")", s)
+	}
+	*k = KeyName(s)
+	return nil
+}
+
+// validKeyName reports whether s is a key name Bubbletea can produce.
+func validKeyName(s string) bool {
+	base := s
+	for _, mod := range []string{"ctrl+", "alt+", "shift+"} {
+		base = strings.TrimPrefix(base, mod)
+	}
+	switch {
+	case base == "":
+		return false
+	case utf8.RuneCountInString(base) == 1:
+		return base != " "
+	case namedKeys[base]:
+		return true
+	case strings.HasPrefix(base, "f"):
+		n, err := strconv.Atoi(base[1:])
+		return err == nil && n >= 1 && n <= 20
+	}
+	return false
+}
+
+// fileConfig is the on-disk layout of config.toml. Every setting is optional;
+// unset values keep their defaults.
+type fileConfig struct {
+	Version   int           `toml:"version"`            // Schema version, see configSchemaVersion
+	Duration  *Duration     `toml:"duration,omitempty"` // Custom brew time, like -duration
+	Alerts    fileAlerts    `toml:"alerts"`             // How finished brews are announced
+	Behavior  fileBehavior  `toml:"behavior"`           // How the timer reacts to input
+	Cues      fileCues      `toml:"cues"`               // Accents during the countdown
+	Cooling   fileCooling   `toml:"cooling"`            // Cool-down model for the water
+	Display   fileDisplay   `toml:"display"`            // How times are shown
+	Colors    fileColors    `toml:"colors"`             // State colors
+	Keys      fileKeys      `toml:"keys"`               // Key bindings
+	Kiosk     fileKiosk     `toml:"kiosk"`              // What -kiosk offers guests
+	History   fileHistory   `toml:"history"`            // How much of the brew log to keep
+	Scale     fileScale     `toml:"scale"`              // Bluetooth scale to weigh leaf on
+	Hooks     fileHooks     `toml:"hooks"`              // Where brew events are posted
+	Overlay   fileOverlay   `toml:"overlay"`            // Countdown file for stream overlays
+	Sync      *fileSync     `toml:"sync,omitempty"`     // Where "go-brew sync" keeps shared copies
+	Telemetry fileTelemetry `toml:"telemetry"`          // Where opted-in usage counts are reported
+	Presets   []filePreset  `toml:"presets,omitempty"`  // Replaces the built-in presets
+	Vessels   []fileVessel  `toml:"vessels,omitempty"`  // Replaces the built-in vessels
+}
+
+// fileAlerts holds the alert switches in config.toml.
+type fileAlerts struct {
+	Sound             *bool       `toml:"sound,omitempty"`              // Play the alert sound
+	Desktop           *bool       `toml:"desktop,omitempty"`            // Send desktop notifications
+	NotificationSound *bool       `toml:"notification_sound,omitempty"` // Desktop notifications play the desktop's own chime
+	QuietHours        QuietHours  `toml:"quiet_hours,omitempty"`        // Daily span such as "22:00-07:00" without alert sounds
+	Urgency           string      `toml:"urgency,omitempty"`            // Linux notification urgency: "low", "normal" or "critical"
+	Expire            *Duration   `toml:"expire,omitempty"`             // How long Linux notifications stay up, "0s" for the desktop's default
+	Remind            *Duration   `toml:"remind,omitempty"`             // Alert again this often until the brew is collected
+	ColdAfter         *Duration   `toml:"cold_after,omitempty"`         // An uncollected brew goes cold after this long
+	NotifyStart       *bool       `toml:"notify_start,omitempty"`       // Also notify when a brew starts
+	NotifyPause       *bool       `toml:"notify_pause,omitempty"`       // Notify when a brew has been paused for 5 minutes
+	NotifyResume      *bool       `toml:"notify_resume,omitempty"`      // Notify when a paused brew resumes
+	Speaker           string      `toml:"speaker,omitempty"`            // Play the sound on this Sonos speaker
+	GPIOPin           *int        `toml:"gpio_pin,omitempty"`           // Pulse this GPIO pin (Linux on ARM)
+	Light             *fileLight  `toml:"light,omitempty"`              // Blink a Hue or WLED light
+	Routes            AlertRoutes `toml:"routes,omitempty"`             // Channels each event fires, e.g. halfway = ["light"]
+}
+
+// fileLight holds the smart light settings in config.toml.
+type fileLight struct {
+	Type  string `toml:"type,omitempty"`  // "hue" or "wled"
+	Host  string `toml:"host,omitempty"`  // Hue bridge or WLED controller address
+	Token string `toml:"token,omitempty"` // Hue bridge API username
+	ID    int    `toml:"id,omitempty"`    // Hue light number
+	Color Color  `toml:"color,omitempty"` // Color to blink as "#RRGGBB"
+}
+
+// fileBehavior holds the interaction settings in config.toml.
+type fileBehavior struct {
+	QuickStart      *bool  `toml:"quick_start,omitempty"`      // Number keys start the preset immediately
+	ConfirmQuit     *bool  `toml:"confirm_quit,omitempty"`     // Quitting a running brew needs a second press
+	ConfirmDuration *bool  `toml:"confirm_duration,omitempty"` // A custom brew far from the preset's time needs a second press
+	PauseOnSuspend  *bool  `toml:"pause_on_suspend,omitempty"` // A brew pauses while go-brew is suspended with ctrl+z
+	DigitEntry      *bool  `toml:"digit_entry,omitempty"`      // Digit keys type a duration instead of picking presets
+	EnterStarts     string `toml:"enter_starts,omitempty"`     // Preset enter starts right away, or "favorite"
+	Vessel          string `toml:"vessel,omitempty"`           // Vessel selected at startup
+	VesselTiming    *bool  `toml:"vessel_timing,omitempty"`    // The selected vessel nudges preset brew times
+}
+
+// fileCues holds the countdown cue settings in config.toml.
+type fileCues struct {
+	Halfway *int      `toml:"halfway,omitempty"` // Percent of the brew for the halfway cue, 0 disables
+	Final   *Duration `toml:"final,omitempty"`   // Remaining time for the final stretch cue, 0 disables
+	Sound   *bool     `toml:"sound,omitempty"`   // Play a soft sound at each cue
+}
+
+// fileCooling holds the water cooling model in config.toml.
+type fileCooling struct {
+	Boil     *int      `toml:"boil,omitempty"`      // Temperature of boiling water in °C
+	Room     *int      `toml:"room,omitempty"`      // Room temperature in °C
+	HalfLife *Duration `toml:"half_life,omitempty"` // Time to lose half the heat above room temperature
+}
+
+// fileSync holds the "go-brew sync" remote in config.toml. The WebDAV
+// password comes from GOBREW_SYNC_PASSWORD, since this file is synced too.
+type fileSync struct {
+	Type   string `toml:"type,omitempty"`   // "git" or "webdav"
+	Remote string `toml:"remote,omitempty"` // Repository URL or WebDAV folder URL
+	User   string `toml:"user,omitempty"`   // WebDAV user name
+}
+
+// fileTelemetry holds the usage report settings in config.toml. Whether to
+// report at all is asked on first run and kept out of this file.
+type fileTelemetry struct {
+	Endpoint string `toml:"endpoint,omitempty"` // URL usage reports are posted to
+}
+
+// fileHistory holds the brew log location, retention limits and encryption
+// in config.toml. A passphrase comes from GOBREW_HISTORY_PASSPHRASE, never from
+// this file.
+type fileHistory struct {
+	File       string `toml:"file,omitempty"`        // Brew log, an SQLite database if it ends in .db
+	MaxEntries *int   `toml:"max_entries,omitempty"` // Most recent brews to keep, 0 for no limit
+	MaxDays    *int   `toml:"max_days,omitempty"`    // Days of brews to keep, 0 for no limit
+	Encrypt    *bool  `toml:"encrypt,omitempty"`     // Encrypt the brew log
+	Keyfile    string `toml:"keyfile,omitempty"`     // File holding the key, instead of a passphrase
+}
+
+// fileScale holds the Bluetooth scale settings in config.toml.
+type fileScale struct {
+	Device string `toml:"device,omitempty"` // Bluetooth address or name of the scale
+}
+
+// fileHooks holds the webhook settings in config.toml.
+type fileHooks struct {
+	URL string `toml:"url,omitempty"` // URL brew events are posted to
+}
+
+// fileOverlay holds the stream overlay settings in config.toml.
+type fileOverlay struct {
+	File string `toml:"file,omitempty"` // Text or HTML file kept showing the countdown
+}
+
+// fileKiosk holds the kiosk settings in config.toml.
+type fileKiosk struct {
+	Presets []string `toml:"presets,omitempty"` // Presets offered in kiosk mode, all when empty
+}
+
+// fileDisplay holds the display settings in config.toml.
+type fileDisplay struct {
+	TimeFormat      TimeFormat `toml:"time_format,omitempty"`      // mm:ss, h:mm:ss, seconds or words
+	Locale          string     `toml:"locale,omitempty"`           // Locale for clock times, dates and temperatures, e.g. "de_DE"
+	Images          *bool      `toml:"images,omitempty"`           // Draw tea pictures in kitty or iTerm2
+	Strength        *bool      `toml:"strength,omitempty"`         // Show the brewing cup's estimated strength
+	Emoji           *bool      `toml:"emoji,omitempty"`            // Decorate the view with emoji, or use text labels
+	Screensaver     *Duration  `toml:"screensaver,omitempty"`      // Idle time before dimming to a clock, 0 disables
+	TickInterval    *Duration  `toml:"tick_interval,omitempty"`    // Brew time per tick, 100ms for smooth bars to 5s for slow links
+	TaskbarProgress *bool      `toml:"taskbar_progress,omitempty"` // Show progress on the taskbar icon in Windows Terminal and ConEmu
+}
+
+// fileColors holds the state colors in config.toml.
+type fileColors struct {
+	Theme   Theme `toml:"theme,omitempty"` // Palette the colors below override
+	Ready   Color `toml:"ready,omitempty"`
+	Brewing Color `toml:"brewing,omitempty"`
+	Paused  Color `toml:"paused,omitempty"`
+	Idle    Color `toml:"idle,omitempty"`
+	Halfway Color `toml:"halfway,omitempty"`
+	Final   Color `toml:"final,omitempty"`
+}
+
+// fileKeys holds the key bindings in config.toml.
+type fileKeys struct {
+	Start     KeyName `toml:"start,omitempty"`
+	Pause     KeyName `toml:"pause,omitempty"`
+	Reset     KeyName `toml:"reset,omitempty"`
+	Quit      KeyName `toml:"quit,omitempty"`
+	Up        KeyName `toml:"up,omitempty"`
+	Down      KeyName `toml:"down,omitempty"`
+	Duration  KeyName `toml:"duration,omitempty"`
+	Undo      KeyName `toml:"undo,omitempty"`
+	Stopwatch KeyName `toml:"stopwatch,omitempty"`
+	Lap       KeyName `toml:"lap,omitempty"`
+	Stats     KeyName `toml:"stats,omitempty"`
+	Note      KeyName `toml:"note,omitempty"`
+	Copy      KeyName `toml:"copy,omitempty"`
+	Edit      KeyName `toml:"edit,omitempty"`
+	Longer    KeyName `toml:"longer,omitempty"`
+	Shorter   KeyName `toml:"shorter,omitempty"`
+	Catalog   KeyName `toml:"catalog,omitempty"`
+	Info      KeyName `toml:"info,omitempty"`
+	Cool      KeyName `toml:"cool,omitempty"`
+	Rate      KeyName `toml:"rate,omitempty"`
+	Suggest   KeyName `toml:"suggest,omitempty"`
+	Calc      KeyName `toml:"calc,omitempty"`
+	Vessel    KeyName `toml:"vessel,omitempty"`
+	Collect   KeyName `toml:"collect,omitempty"`
+}
+
+// fileVessel is a cup or pot for the leaf calculator in config.toml.
+type fileVessel struct {
+	Name      string  `toml:"name"`
+	Ml        int     `toml:"ml"`
+	Retention float64 `toml:"retention,omitempty"` // Relative to a ceramic mug's 1, which is the default
+}
+
+// filePreset is a tea preset in config.toml.
+type filePreset struct {
+	Name     string     `toml:"name"`
+	Duration Duration   `toml:"duration"`
+	Temp     string     `toml:"temp,omitempty"`
+	Notes    string     `toml:"notes,omitempty"`
+	Steeps   []Duration `toml:"steeps,omitempty"`
+	Origin   string     `toml:"origin,omitempty"`
+	Caffeine Caffeine   `toml:"caffeine,omitempty"`
+	Flavor   string     `toml:"flavor,omitempty"`
+	Ratio    string     `toml:"ratio,omitempty"`
+}
+
+// defaultConfigPath returns the path of the user's config file.
+func defaultConfigPath() string {
+	return filepath.Join(dirs().Config, configFileName)
+}
+
+// configMeta describes a decoded config file beyond its settings.
+type configMeta struct {
+	Version int      // Schema version the file was written in
+	Unknown []string // Settings present in the file that go-brew doesn't know
+}
+
+// loadConfigFile reads and decodes the config file at path. A missing file is
+// not an error and yields a nil config. Syntax errors and invalid values are
+// reported with their line number. Files in an older schema are upgraded in
+// memory before decoding; migrateConfigFile persists the upgrade.
+func loadConfigFile(path string) (*fileConfig, configMeta, error) {
+	var meta configMeta
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, meta, nil
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+
+	data, meta.Version, err = upgradeConfig(data)
+	if err != nil {
+		return nil, meta, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var fc fileConfig
+	md, err := toml.Decode(string(data), &fc)
+	if err != nil {
+		return nil, meta, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, key := range md.Undecoded() {
+		meta.Unknown = append(meta.Unknown, key.String())
+	}
+	return &fc, meta, nil
+}
+
+// validate checks the decoded values that are well-formed but out of range.
+// Each problem names the setting it refers to.
+func (fc *fileConfig) validate() []error {
+	var errs []error
+	if fc.Duration != nil {
+		if err := checkBrewTime(fc.Duration.Duration); err != nil {
+			errs = append(errs, fmt.Errorf("duration: %w", err))
+		}
+	}
+	if fc.Cues.Halfway != nil && (*fc.Cues.Halfway < 0 || *fc.Cues.Halfway > 99) {
+		errs = append(errs, fmt.Errorf("cues.halfway: must be a percentage from 0 to 99"))
+	}
+	if fc.Cues.Final != nil && (fc.Cues.Final.Duration < 0 || fc.Cues.Final.Duration > MaxBrewTime) {
+		errs = append(errs, fmt.Errorf("cues.final: must be between 0 and %v", MaxBrewTime))
+	}
+	if d := fc.Display.TickInterval; d != nil && (d.Duration < MinTickInterval || d.Duration > MaxTickInterval) {
+		errs = append(errs, fmt.Errorf("display.tick_interval: must be between %v and %v", MinTickInterval, MaxTickInterval))
+	}
+	if fc.Display.Screensaver != nil && fc.Display.Screensaver.Duration < 0 {
+		errs = append(errs, fmt.Errorf("display.screensaver: must not be negative"))
+	}
+	if fc.Cooling.HalfLife != nil && fc.Cooling.HalfLife.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("cooling.half_life: must be positive"))
+	}
+	if fc.Sync != nil && fc.Sync.Type != "" && fc.Sync.Type != SyncGit && fc.Sync.Type != SyncWebDAV {
+		errs = append(errs, fmt.Errorf("sync.type: must be %q or %q", SyncGit, SyncWebDAV))
+	}
+	if u := fc.Hooks.URL; u != "" && !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		errs = append(errs, fmt.Errorf("hooks.url: must be an http:// or https:// URL"))
+	}
+	switch fc.Alerts.Urgency {
+	case "", UrgencyLow, UrgencyNormal, UrgencyCritical:
+	default:
+		errs = append(errs, fmt.Errorf("alerts.urgency: must be %q, %q or %q", UrgencyLow, UrgencyNormal, UrgencyCritical))
+	}
+	if fc.Alerts.Expire != nil && fc.Alerts.Expire.Duration < 0 {
+		errs = append(errs, fmt.Errorf("alerts.expire: must not be negative"))
+	}
+	if fc.Alerts.Remind != nil && fc.Alerts.Remind.Duration != 0 && fc.Alerts.Remind.Duration < MinRemind {
+		errs = append(errs, fmt.Errorf("alerts.remind: must be 0 or at least %v", MinRemind))
+	}
+	if fc.Alerts.ColdAfter != nil && fc.Alerts.ColdAfter.Duration < 0 {
+		errs = append(errs, fmt.Errorf("alerts.cold_after: must not be negative"))
+	}
+	if fc.History.MaxEntries != nil && *fc.History.MaxEntries < 0 {
+		errs = append(errs, fmt.Errorf("history.max_entries: must not be negative"))
+	}
+	if fc.History.MaxDays != nil && *fc.History.MaxDays < 0 {
+		errs = append(errs, fmt.Errorf("history.max_days: must not be negative"))
+	}
+	if fc.Alerts.GPIOPin != nil && *fc.Alerts.GPIOPin < 0 {
+		errs = append(errs, fmt.Errorf("alerts.gpio_pin: must not be negative"))
+	}
+	errs = append(errs, fc.Alerts.Routes.validate()...)
+	if l := fc.Alerts.Light; l != nil {
+		if l.Type != "" && l.Type != LightHue && l.Type != LightWLED {
+			errs = append(errs, fmt.Errorf("alerts.light.type: must be %q or %q", LightHue, LightWLED))
+		}
+		if l.Color != "" && !strings.HasPrefix(string(l.Color), "#") {
+			errs = append(errs, fmt.Errorf("alerts.light.color: must be a hex color like \"#00FF7F\""))
+		}
+		if l.ID < 0 {
+			errs = append(errs, fmt.Errorf("alerts.light.id: must be positive"))
+		}
+	}
+	for i, p := range fc.Presets {
+		if strings.TrimSpace(p.Name) == "" {
+			errs = append(errs, fmt.Errorf("presets[%d].name: must not be empty", i))
+		}
+		// A preset with steeps starts with the first one, so its
+		// duration may be left out
+		if p.Duration.Duration <= 0 && (p.Duration.Duration < 0 || len(p.Steeps) == 0) {
+			errs = append(errs, fmt.Errorf("presets[%d].duration: must be positive", i))
+		} else if p.Duration.Duration > MaxBrewTime {
+			errs = append(errs, fmt.Errorf("presets[%d].duration: cannot exceed %v", i, MaxBrewTime))
+		}
+		for j, steep := range p.Steeps {
+			if steep.Duration <= 0 || steep.Duration > MaxBrewTime {
+				errs = append(errs, fmt.Errorf("presets[%d].steeps[%d]: must be positive and at most %v", i, j, MaxBrewTime))
+			}
+		}
+	}
+	for i, v := range fc.Vessels {
+		if strings.TrimSpace(v.Name) == "" {
+			errs = append(errs, fmt.Errorf("vessels[%d].name: must not be empty", i))
+		}
+		if v.Ml <= 0 {
+			errs = append(errs, fmt.Errorf("vessels[%d].ml: must be positive", i))
+		}
+		if v.Retention < 0 {
+			errs = append(errs, fmt.Errorf("vessels[%d].retention: must be positive", i))
+		}
+	}
+	return errs
+}
+
+// applyFile merges the settings from one configuration layer into c and
+// records source as their origin. Settings given as command line flags take
+// precedence and are left untouched.
+func (c *Config) applyFile(fc *fileConfig, source string) {
+	if fc.Duration != nil && !c.flagSet("duration") {
+		c.BrewTime = fc.Duration.Duration
+		c.CustomDuration = true
+		c.Sources["duration"] = source
+	}
+	if fc.Alerts.Sound != nil {
+		c.SoundEnabled = *fc.Alerts.Sound
+		c.Sources["alerts.sound"] = source
+	}
+	if fc.Alerts.Desktop != nil {
+		c.NotifyEnabled = *fc.Alerts.Desktop
+		c.Sources["alerts.desktop"] = source
+	}
+	if fc.Alerts.NotificationSound != nil {
+		c.NotificationSound = *fc.Alerts.NotificationSound
+		c.Sources["alerts.notification_sound"] = source
+	}
+	c.setString("alerts.urgency", &c.NotifyUrgency, fc.Alerts.Urgency, source)
+	if fc.Alerts.QuietHours != "" {
+		c.QuietHours = fc.Alerts.QuietHours
+		c.Sources["alerts.quiet_hours"] = source
+	}
+	if fc.Alerts.Expire != nil {
+		c.NotifyExpire = fc.Alerts.Expire.Duration
+		c.Sources["alerts.expire"] = source
+	}
+	if fc.Alerts.Remind != nil {
+		c.Remind = fc.Alerts.Remind.Duration
+		c.Sources["alerts.remind"] = source
+	}
+	if fc.Alerts.ColdAfter != nil {
+		c.ColdAfter = fc.Alerts.ColdAfter.Duration
+		c.Sources["alerts.cold_after"] = source
+	}
+	if fc.Alerts.NotifyStart != nil {
+		c.NotifyStart = *fc.Alerts.NotifyStart
+		c.Sources["alerts.notify_start"] = source
+	}
+	if fc.Alerts.NotifyPause != nil {
+		c.NotifyPause = *fc.Alerts.NotifyPause
+		c.Sources["alerts.notify_pause"] = source
+	}
+	if fc.Alerts.NotifyResume != nil {
+		c.NotifyResume = *fc.Alerts.NotifyResume
+		c.Sources["alerts.notify_resume"] = source
+	}
+	c.setString("alerts.speaker", &c.Speaker, fc.Alerts.Speaker, source)
+	if fc.Alerts.GPIOPin != nil {
+		c.GPIOPin = *fc.Alerts.GPIOPin
+		c.Sources["alerts.gpio_pin"] = source
+	}
+	if l := fc.Alerts.Light; l != nil {
+		c.setString("alerts.light.type", &c.Light.Kind, l.Type, source)
+		c.setString("alerts.light.host", &c.Light.Host, l.Host, source)
+		c.setString("alerts.light.token", &c.Light.Token, l.Token, source)
+		if l.ID > 0 {
+			c.Light.ID = strconv.Itoa(l.ID)
+			c.Sources["alerts.light.id"] = source
+		}
+		c.setString("alerts.light.color", &c.Light.Color, string(l.Color), source)
+	}
+	// Routes merge event by event, so a project file can reroute one event
+	// and keep the user's routes for the others
+	if len(fc.Alerts.Routes) > 0 {
+		routes := maps.Clone(c.Routes)
+		if routes == nil {
+			routes = AlertRoutes{}
+		}
+		maps.Copy(routes, fc.Alerts.Routes)
+		c.Routes = routes
+		c.Sources["alerts.routes"] = source
+	}
+	if len(fc.Kiosk.Presets) > 0 {
+		c.KioskPresets = fc.Kiosk.Presets
+		c.Sources["kiosk.presets"] = source
+	}
+	c.setString("telemetry.endpoint", &c.TelemetryEndpoint, fc.Telemetry.Endpoint, source)
+	if s := fc.Sync; s != nil {
+		c.setString("sync.type", &c.Sync.Kind, s.Type, source)
+		c.setString("sync.remote", &c.Sync.Remote, s.Remote, source)
+		c.setString("sync.user", &c.Sync.User, s.User, source)
+	}
+
+	if fc.Display.TimeFormat != "" {
+		c.TimeFormat = fc.Display.TimeFormat
+		c.Sources["display.time_format"] = source
+	}
+	c.setString("display.locale", &c.Locale, fc.Display.Locale, source)
+	if fc.Cues.Halfway != nil {
+		c.HalfwayCue = *fc.Cues.Halfway
+		c.Sources["cues.halfway"] = source
+	}
+	if fc.Cues.Final != nil {
+		c.FinalCue = fc.Cues.Final.Duration
+		c.Sources["cues.final"] = source
+	}
+	if fc.Cues.Sound != nil {
+		c.CueSound = *fc.Cues.Sound
+		c.Sources["cues.sound"] = source
+	}
+	if fc.Cooling.Boil != nil {
+		c.BoilTemp = *fc.Cooling.Boil
+		c.Sources["cooling.boil"] = source
+	}
+	if fc.Cooling.Room != nil {
+		c.RoomTemp = *fc.Cooling.Room
+		c.Sources["cooling.room"] = source
+	}
+	if fc.Cooling.HalfLife != nil {
+		c.CoolHalfLife = fc.Cooling.HalfLife.Duration
+		c.Sources["cooling.half_life"] = source
+	}
+	if fc.History.MaxEntries != nil {
+		c.HistoryLimits.MaxEntries = *fc.History.MaxEntries
+		c.Sources["history.max_entries"] = source
+	}
+	if fc.History.MaxDays != nil {
+		c.HistoryLimits.MaxDays = *fc.History.MaxDays
+		c.Sources["history.max_days"] = source
+	}
+	if fc.History.Encrypt != nil {
+		c.HistoryEncrypt = *fc.History.Encrypt
+		c.Sources["history.encrypt"] = source
+	}
+	c.setString("history.keyfile", &c.HistoryKeyfile, fc.History.Keyfile, source)
+	c.setString("history.file", &c.HistoryFile, fc.History.File, source)
+	c.setString("scale.device", &c.ScaleDevice, fc.Scale.Device, source)
+	c.setString("hooks.url", &c.HookURL, fc.Hooks.URL, source)
+	if !c.flagSet("overlay") {
+		c.setString("overlay.file", &c.OverlayFile, fc.Overlay.File, source)
+	}
+	if fc.Display.Strength != nil {
+		c.ShowStrength = *fc.Display.Strength
+		c.Sources["display.strength"] = source
+	}
+	if fc.Display.Screensaver != nil {
+		c.Screensaver = fc.Display.Screensaver.Duration
+		c.Sources["display.screensaver"] = source
+	}
+	if fc.Display.TickInterval != nil {
+		c.TickInterval = fc.Display.TickInterval.Duration
+		c.Sources["display.tick_interval"] = source
+	}
+	if fc.Display.Images != nil {
+		c.Images = *fc.Display.Images
+		c.Sources["display.images"] = source
+	}
+	if fc.Display.Emoji != nil {
+		c.Emoji = *fc.Display.Emoji
+		c.Sources["display.emoji"] = source
+	}
+	if fc.Display.TaskbarProgress != nil {
+		c.TaskbarProgress = *fc.Display.TaskbarProgress
+		c.Sources["display.taskbar_progress"] = source
+	}
+
+	// A theme replaces the whole palette; colors given alongside it, or in
+	// later layers, override single states
+	if fc.Colors.Theme != "" {
+		c.Theme = fc.Colors.Theme
+		c.Colors = themePalettes[c.Theme]
+		for _, key := range []string{"colors.theme", "colors.ready", "colors.brewing", "colors.paused", "colors.idle", "colors.halfway", "colors.final"} {
+			c.Sources[key] = source
+		}
+	}
+	c.setString("colors.ready", &c.Colors.Ready, string(fc.Colors.Ready), source)
+	c.setString("colors.brewing", &c.Colors.Brewing, string(fc.Colors.Brewing), source)
+	c.setString("colors.paused", &c.Colors.Paused, string(fc.Colors.Paused), source)
+	c.setString("colors.idle", &c.Colors.Idle, string(fc.Colors.Idle), source)
+	c.setString("colors.halfway", &c.Colors.Halfway, string(fc.Colors.Halfway), source)
+	c.setString("colors.final", &c.Colors.Final, string(fc.Colors.Final), source)
+
+	c.setString("keys.start", &c.Keys.Start, string(fc.Keys.Start), source)
+	c.setString("keys.pause", &c.Keys.Pause, string(fc.Keys.Pause), source)
+	c.setString("keys.reset", &c.Keys.Reset, string(fc.Keys.Reset), source)
+	c.setString("keys.quit", &c.Keys.Quit, string(fc.Keys.Quit), source)
+	c.setString("keys.up", &c.Keys.Up, string(fc.Keys.Up), source)
+	c.setString("keys.down", &c.Keys.Down, string(fc.Keys.Down), source)
+	c.setString("keys.duration", &c.Keys.Duration, string(fc.Keys.Duration), source)
+	c.setString("keys.undo", &c.Keys.Undo, string(fc.Keys.Undo), source)
+	c.setString("keys.stopwatch", &c.Keys.Stopwatch, string(fc.Keys.Stopwatch), source)
+	c.setString("keys.lap", &c.Keys.Lap, string(fc.Keys.Lap), source)
+	c.setString("keys.stats", &c.Keys.Stats, string(fc.Keys.Stats), source)
+	c.setString("keys.note", &c.Keys.Note, string(fc.Keys.Note), source)
+	c.setString("keys.copy", &c.Keys.Copy, string(fc.Keys.Copy), source)
+	c.setString("keys.edit", &c.Keys.Edit, string(fc.Keys.Edit), source)
+	c.setString("keys.longer", &c.Keys.Longer, string(fc.Keys.Longer), source)
+	c.setString("keys.shorter", &c.Keys.Shorter, string(fc.Keys.Shorter), source)
+	c.setString("keys.catalog", &c.Keys.Catalog, string(fc.Keys.Catalog), source)
+	c.setString("keys.info", &c.Keys.Info, string(fc.Keys.Info), source)
+	c.setString("keys.cool", &c.Keys.Cool, string(fc.Keys.Cool), source)
+	c.setString("keys.rate", &c.Keys.Rate, string(fc.Keys.Rate), source)
+	c.setString("keys.suggest", &c.Keys.Suggest, string(fc.Keys.Suggest), source)
+	c.setString("keys.calc", &c.Keys.Calc, string(fc.Keys.Calc), source)
+	c.setString("keys.vessel", &c.Keys.Vessel, string(fc.Keys.Vessel), source)
+	c.setString("keys.collect", &c.Keys.Collect, string(fc.Keys.Collect), source)
+	if fc.Behavior.QuickStart != nil {
+		c.QuickStart = *fc.Behavior.QuickStart
+		c.Sources["behavior.quick_start"] = source
+	}
+	if fc.Behavior.ConfirmQuit != nil {
+		c.ConfirmQuit = *fc.Behavior.ConfirmQuit
+		c.Sources["behavior.confirm_quit"] = source
+	}
+	if fc.Behavior.PauseOnSuspend != nil {
+		c.PauseOnSuspend = *fc.Behavior.PauseOnSuspend
+		c.Sources["behavior.pause_on_suspend"] = source
+	}
+	if fc.Behavior.ConfirmDuration != nil {
+		c.ConfirmDuration = *fc.Behavior.ConfirmDuration
+		c.Sources["behavior.confirm_duration"] = source
+	}
+	if fc.Behavior.DigitEntry != nil {
+		c.DigitEntry = *fc.Behavior.DigitEntry
+		c.Sources["behavior.digit_entry"] = source
+	}
+	c.setString("behavior.enter_starts", &c.EnterStarts, fc.Behavior.EnterStarts, source)
+	c.setString("behavior.vessel", &c.Vessel, fc.Behavior.Vessel, source)
+	if fc.Behavior.VesselTiming != nil {
+		c.VesselTiming = *fc.Behavior.VesselTiming
+		c.Sources["behavior.vessel_timing"] = source
+	}
+	c.KeyBindings = c.bindings()
+
+	if len(fc.Presets) > 0 {
+		presets := make([]TeaPreset, len(fc.Presets))
+		for i, p := range fc.Presets {
+			presets[i] = TeaPreset{p.Name, p.Duration.Duration, p.Temp, p.Notes, nil, PresetInfo{p.Origin, string(p.Caffeine), p.Flavor, p.Ratio}}
+			for _, steep := range p.Steeps {
+				presets[i].Steeps = append(presets[i].Steeps, steep.Duration)
+			}
+			if presets[i].Duration == 0 {
+				presets[i].Duration = presets[i].Steeps[0]
+			}
+		}
+		c.Presets = presets
+		c.Sources["presets"] = source
+	}
+	if len(fc.Vessels) > 0 {
+		vessels := make([]Vessel, len(fc.Vessels))
+		for i, v := range fc.Vessels {
+			vessels[i] = Vessel{v.Name, v.Ml, v.Retention}
+			if v.Retention == 0 {
+				vessels[i].Retention = 1
+			}
+		}
+		c.Vessels = vessels
+		c.Sources["vessels"] = source
+	}
+}
+
+// setString overwrites *dst with v unless v is empty, recording source as the
+// origin of the setting key.
+func (c *Config) setString(key string, dst *string, v, source string) {
+	if v != "" {
+		*dst = v
+		c.Sources[key] = source
+	}
+}
+
+// toFile converts the effective configuration back into the config file
+// layout, for display by "go-brew config show".
+func (c *Config) toFile() fileConfig {
+	fc := fileConfig{
+		Version: configSchemaVersion,
+		Alerts: fileAlerts{
+			Sound:             &c.SoundEnabled,
+			Desktop:           &c.NotifyEnabled,
+			NotificationSound: &c.NotificationSound,
+			QuietHours:        c.QuietHours,
+			Urgency:           c.NotifyUrgency,
+			Expire:            &Duration{c.NotifyExpire},
+			Remind:            &Duration{c.Remind},
+			ColdAfter:         &Duration{c.ColdAfter},
+			NotifyStart:       &c.NotifyStart,
+			NotifyPause:       &c.NotifyPause,
+			NotifyResume:      &c.NotifyResume,
+			Speaker:           c.Speaker,
+		},
+		Behavior: fileBehavior{
+			QuickStart:      &c.QuickStart,
+			ConfirmQuit:     &c.ConfirmQuit,
+			ConfirmDuration: &c.ConfirmDuration,
+			PauseOnSuspend:  &c.PauseOnSuspend,
+			DigitEntry:      &c.DigitEntry,
+			EnterStarts:     c.EnterStarts,
+			Vessel:          c.Vessel,
+			VesselTiming:    &c.VesselTiming,
+		},
+		Cues: fileCues{
+			Halfway: &c.HalfwayCue,
+			Final:   &Duration{c.FinalCue},
+			Sound:   &c.CueSound,
+		},
+		Cooling: fileCooling{
+			Boil:     &c.BoilTemp,
+			Room:     &c.RoomTemp,
+			HalfLife: &Duration{c.CoolHalfLife},
+		},
+		History: fileHistory{
+			File:       c.HistoryFile,
+			MaxEntries: &c.HistoryLimits.MaxEntries,
+			MaxDays:    &c.HistoryLimits.MaxDays,
+			Encrypt:    &c.HistoryEncrypt,
+			Keyfile:    c.HistoryKeyfile,
+		},
+		Scale: fileScale{
+			Device: c.ScaleDevice,
+		},
+		Hooks: fileHooks{
+			URL: c.HookURL,
+		},
+		Overlay: fileOverlay{
+			File: c.OverlayFile,
+		},
+		Display: fileDisplay{
+			TimeFormat:      c.TimeFormat,
+			Locale:          c.Locale,
+			Images:          &c.Images,
+			Strength:        &c.ShowStrength,
+			Emoji:           &c.Emoji,
+			Screensaver:     &Duration{c.Screensaver},
+			TickInterval:    &Duration{c.TickInterval},
+			TaskbarProgress: &c.TaskbarProgress,
+		},
+		Colors: fileColors{
+			Theme:   c.Theme,
+			Ready:   Color(c.Colors.Ready),
+			Brewing: Color(c.Colors.Brewing),
+			Paused:  Color(c.Colors.Paused),
+			Idle:    Color(c.Colors.Idle),
+			Halfway: Color(c.Colors.Halfway),
+			Final:   Color(c.Colors.Final),
+		},
+		Keys: fileKeys{
+			Start:     KeyName(c.Keys.Start),
+			Pause:     KeyName(c.Keys.Pause),
+			Reset:     KeyName(c.Keys.Reset),
+			Quit:      KeyName(c.Keys.Quit),
+			Up:        KeyName(c.Keys.Up),
+			Down:      KeyName(c.Keys.Down),
+			Duration:  KeyName(c.Keys.Duration),
+			Undo:      KeyName(c.Keys.Undo),
+			Stopwatch: KeyName(c.Keys.Stopwatch),
+			Lap:       KeyName(c.Keys.Lap),
+			Stats:     KeyName(c.Keys.Stats),
+			Note:      KeyName(c.Keys.Note),
+			Copy:      KeyName(c.Keys.Copy),
+			Edit:      KeyName(c.Keys.Edit),
+			Longer:    KeyName(c.Keys.Longer),
+			Shorter:   KeyName(c.Keys.Shorter),
+			Catalog:   KeyName(c.Keys.Catalog),
+			Info:      KeyName(c.Keys.Info),
+			Cool:      KeyName(c.Keys.Cool),
+			Rate:      KeyName(c.Keys.Rate),
+			Suggest:   KeyName(c.Keys.Suggest),
+			Calc:      KeyName(c.Keys.Calc),
+			Vessel:    KeyName(c.Keys.Vessel),
+			Collect:   KeyName(c.Keys.Collect),
+		},
+	}
+	if c.CustomDuration {
+		fc.Duration = &Duration{c.BrewTime}
+	}
+	if c.GPIOPin >= 0 {
+		fc.Alerts.GPIOPin = &c.GPIOPin
+	}
+	if c.Light.Kind != "" {
+		id, _ := strconv.Atoi(c.Light.ID)
+		fc.Alerts.Light = &fileLight{c.Light.Kind, c.Light.Host, c.Light.Token, id, Color(c.Light.Color)}
+	}
+	fc.Alerts.Routes = c.Routes
+	fc.Kiosk.Presets = c.KioskPresets
+	fc.Telemetry.Endpoint = c.TelemetryEndpoint
+	if c.Sync.Kind != "" {
+		fc.Sync = &fileSync{c.Sync.Kind, c.Sync.Remote, c.Sync.User}
+	}
+	for _, p := range c.Presets {
+		fp := filePreset{p.Name, Duration{p.Duration}, p.Temp, p.Notes, nil, p.Info.Origin, Caffeine(p.Info.Caffeine), p.Info.Flavor, p.Info.Ratio}
+		for _, steep := range p.Steeps {
+			fp.Steeps = append(fp.Steeps, Duration{steep})
+		}
+		fc.Presets = append(fc.Presets, fp)
+	}
+	for _, v := range c.Vessels {
+		fc.Vessels = append(fc.Vessels, fileVessel{v.Name, v.Ml, v.Retention})
+	}
+	return fc
+}
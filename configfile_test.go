@@ -0,0 +1,359 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes contents to a config file in a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), configFileName)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadConfigFileErrors verifies that malformed values are rejected with
+// the line number they appear on.
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"bad duration", "version = 2\nduration = \"4mm\"\n", "line 2"},
+		{"bad color", "[colors]\nready = \"green\"\n", "line 2"},
+		{"bad key", "[keys]\n\nstart = \"ctrl+\"\n", "line 3"},
+		{"bad light color", "[alerts.light]\ncolor = \"green\"\n", "line 2"},
+		{"bad caffeine level", "[[presets]]\nname = \"Sencha\"\ncaffeine = \"lots\"\n", "line 3"},
+		{"bad time format", "[display]\ntime_format = \"hh:mm\"\n", "line 2"},
+		{"bad preset duration", "[[presets]]\nname = \"Sencha\"\nduration = \"soon\"\n", "line 3"},
+		{"syntax error", "duration = \n", "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := loadConfigFile(writeConfig(t, tt.contents))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestLoadConfigFileMissing verifies that a missing config file is not an error.
+func TestLoadConfigFileMissing(t *testing.T) {
+	fc, _, err := loadConfigFile(filepath.Join(t.TempDir(), configFileName))
+	if err != nil || fc != nil {
+		t.Errorf("Expected no config and no error, got %v, %v", fc, err)
+	}
+}
+
+// TestLoadFileMerge verifies that config file settings are merged over the
+// defaults while explicit command line flags keep precedence.
+func TestLoadFileMerge(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, `
+version = 2
+duration = "3m"
+
+[alerts]
+desktop = false
+
+[colors]
+paused = "208"
+
+[keys]
+start = "enter"
+
+[[presets]]
+name = "Sencha"
+duration = "1m30s"
+temp = "75°C"
+`)
+	config.BrewTime = 2 * time.Minute
+	config.setFlags = map[string]bool{"duration": true}
+
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if config.BrewTime != 2*time.Minute {
+		t.Errorf("Expected -duration flag to win, got %v", config.BrewTime)
+	}
+	if config.NotifyEnabled {
+		t.Error("Expected notifications to be disabled")
+	}
+	if config.Colors.Paused != "208" || config.Colors.Ready != ColorReady {
+		t.Errorf("Unexpected colors %+v", config.Colors)
+	}
+	if config.Keys.Start != "enter" || config.KeyBindings[0].Key != "enter" {
+		t.Errorf("Expected start key and its help to be rebound, got %+v", config.Keys)
+	}
+	if got := config.source("keys.start"); got != "user "+config.ConfigPath {
+		t.Errorf("Expected keys.start from the user config, got %q", got)
+	}
+	if got := config.source("keys.quit"); got != "default" {
+		t.Errorf("Expected keys.quit from the defaults, got %q", got)
+	}
+	if len(config.Presets) != 1 || config.Presets[0].Duration != 90*time.Second {
+		t.Errorf("Unexpected presets %+v", config.Presets)
+	}
+}
+
+// TestConfigFileValidate verifies the range checks applied after decoding.
+func TestConfigFileValidate(t *testing.T) {
+	fc, _, err := loadConfigFile(writeConfig(t, `
+duration = "10s"
+
+[[presets]]
+name = ""
+duration = "45m"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := fc.validate()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 problems, got %v", errs)
+	}
+}
+
+// TestPresetSteeps verifies that a preset may give its infusions instead of
+// a single duration.
+func TestPresetSteeps(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, `
+[[presets]]
+name = "Oolong"
+steeps = ["25s", "30s"]
+`)
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	p := config.Presets[0]
+	if p.Duration != 25*time.Second || len(p.Steeps) != 2 || p.Steeps[1] != 30*time.Second {
+		t.Errorf("Unexpected preset %+v", p)
+	}
+
+	fc, _, err := loadConfigFile(writeConfig(t, "[[presets]]\nname = \"Oolong\"\nsteeps = [\"25s\", \"0s\"]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := fc.validate(); len(errs) != 1 {
+		t.Errorf("Expected the zero steep to be rejected, got %v", errs)
+	}
+}
+
+// TestPresetInfo verifies that the optional preset details are read and the
+// caffeine level is normalised.
+func TestPresetInfo(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, `
+[[presets]]
+name = "Sencha"
+duration = "1m30s"
+origin = "Shizuoka, Japan"
+caffeine = "Medium"
+ratio = "2g per 200ml"
+`)
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	want := PresetInfo{Origin: "Shizuoka, Japan", Caffeine: "medium", Ratio: "2g per 200ml"}
+	if got := config.Presets[0].Info; got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// TestMigrateConfigFile verifies that a version 1 file is rewritten in the
+// current schema, keeps its settings and leaves a backup of the original.
+func TestMigrateConfigFile(t *testing.T) {
+	original := "duration = \"3m\"\nsound = false\nnotify = false\n\n[keys]\nstart = \"enter\"\n"
+	path := writeConfig(t, original)
+
+	backup, err := migrateConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != original {
+		t.Fatalf("Expected backup with original contents, got %q, %v", data, err)
+	}
+
+	fc, meta, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Version != configSchemaVersion || len(meta.Unknown) != 0 {
+		t.Errorf("Expected current schema without unknown keys, got %+v", meta)
+	}
+	if fc.Alerts.Sound == nil || *fc.Alerts.Sound || fc.Alerts.Desktop == nil || *fc.Alerts.Desktop {
+		t.Errorf("Expected alert switches to be carried over, got %+v", fc.Alerts)
+	}
+	if fc.Keys.Start != "enter" || fc.Duration.Duration != 3*time.Minute {
+		t.Errorf("Expected other settings to survive, got %+v", fc)
+	}
+
+	// A second run finds nothing to do
+	if backup, err := migrateConfigFile(path); err != nil || backup != "" {
+		t.Errorf("Expected no further migration, got %q, %v", backup, err)
+	}
+}
+
+// TestConfigFromNewerRelease verifies that files from a newer schema are rejected.
+func TestConfigFromNewerRelease(t *testing.T) {
+	_, _, err := loadConfigFile(writeConfig(t, "version = 99\n"))
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected newer-schema error, got %v", err)
+	}
+}
+
+// TestLayerPrecedence verifies that later layers override earlier ones and
+// that environment variables override every file.
+func TestLayerPrecedence(t *testing.T) {
+	config := NewConfig()
+	user := configLayer{name: "user", path: writeConfig(t, "duration = \"3m\"\n[keys]\nstart = \"enter\"\nreset = \"x\"\n")}
+	project := configLayer{name: "project", path: writeConfig(t, "[keys]\nstart = \"b\"\n")}
+	for _, layer := range []configLayer{user, project} {
+		if err := config.loadLayer(layer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env := map[string]string{"GOBREW_DURATION": "5m", "GOBREW_ALERTS_SOUND": "false"}
+	if err := config.loadEnv(func(name string) string { return env[name] }); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct{ key, value, source string }{
+		{"duration", "5m0s", "env GOBREW_DURATION"},
+		{"alerts.sound", "false", "env GOBREW_ALERTS_SOUND"},
+		{"keys.start", "b", project.source()},
+		{"keys.reset", "x", user.source()},
+		{"keys.quit", KeyQuit, "default"},
+	}
+	for _, c := range checks {
+		if got := config.settingValue(c.key); got != c.value {
+			t.Errorf("%s = %q, want %q", c.key, got, c.value)
+		}
+		if got := config.source(c.key); got != c.source {
+			t.Errorf("%s from %q, want %q", c.key, got, c.source)
+		}
+	}
+}
+
+// TestLoadEnvErrors verifies that invalid environment values are reported by
+// variable name.
+func TestLoadEnvErrors(t *testing.T) {
+	env := map[string]string{"GOBREW_ALERTS_DESKTOP": "maybe", "GOBREW_COLORS_READY": "green"}
+	err := NewConfig().loadEnv(func(name string) string { return env[name] })
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, name := range []string{"GOBREW_ALERTS_DESKTOP", "GOBREW_COLORS_READY"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected error to mention %s, got %v", name, err)
+		}
+	}
+}
+
+// TestProjectConfigRestricted verifies that a project config can change the
+// timer and its looks but not where go-brew reads, writes or connects.
+func TestProjectConfigRestricted(t *testing.T) {
+	config := NewConfig()
+	project := configLayer{name: "project", project: true, path: writeConfig(t, `
+[keys]
+start = "b"
+[overlay]
+file = "/tmp/stolen.txt"
+[hooks]
+url = "https://example.com/hook"
+[history]
+file = "/tmp/history.jsonl"
+[sync]
+type = "git"
+remote = "https://example.com/repo.git"
+[telemetry]
+endpoint = "https://example.com/usage"
+`)}
+	if err := config.loadLayer(project); err != nil {
+		t.Fatal(err)
+	}
+	if config.Keys.Start != "b" {
+		t.Errorf("Expected the project's key binding, got %q", config.Keys.Start)
+	}
+	for _, key := range []string{"overlay.file", "hooks.url", "history.file", "sync.remote", "telemetry.endpoint"} {
+		if got := config.source(key); got != "default" {
+			t.Errorf("Expected %s left to the user's config, got it from %q", key, got)
+		}
+	}
+}
+
+// TestFindProjectConfig verifies that .gobrew.toml is found in a parent directory.
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, projectConfigName)
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := findProjectConfig(nested); got != path {
+		t.Errorf("findProjectConfig() = %q, want %q", got, path)
+	}
+}
+
+// TestGPIOPinSetting verifies that the GPIO pin is read as a number from the
+// config file and the environment, and that negative pins are rejected.
+func TestGPIOPinSetting(t *testing.T) {
+	config := NewConfig()
+	if config.settingValue("alerts.gpio_pin") != "off" {
+		t.Errorf("Expected GPIO alerts to be off by default, got %q", config.settingValue("alerts.gpio_pin"))
+	}
+	config.ConfigPath = writeConfig(t, "[alerts]\ngpio_pin = 17\n")
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if config.GPIOPin != 17 {
+		t.Errorf("Expected GPIO pin 17, got %d", config.GPIOPin)
+	}
+
+	env := map[string]string{"GOBREW_ALERTS_GPIO_PIN": "27"}
+	if err := config.loadEnv(func(name string) string { return env[name] }); err != nil || config.GPIOPin != 27 {
+		t.Errorf("Expected GPIO pin 27 from the environment, got %d, %v", config.GPIOPin, err)
+	}
+	for _, value := range []string{"buzzer", "-1"} {
+		env["GOBREW_ALERTS_GPIO_PIN"] = value
+		if err := config.loadEnv(func(name string) string { return env[name] }); err == nil {
+			t.Errorf("Expected GOBREW_ALERTS_GPIO_PIN=%s to be rejected", value)
+		}
+	}
+}
+
+// TestColorTheme verifies that a theme sets the whole palette and that
+// single colors still override it.
+func TestColorTheme(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, "[colors]\ntheme = \"Tritanopia\"\nidle = \"244\"\n")
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	want := themePalettes[ThemeTritanopia]
+	want.Idle = "244"
+	if config.Theme != ThemeTritanopia || config.Colors != want {
+		t.Errorf("Expected %+v, got %s %+v", want, config.Theme, config.Colors)
+	}
+
+	if _, _, err := loadConfigFile(writeConfig(t, "[colors]\ntheme = \"sepia\"\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an unknown theme to be rejected with its line, got %v", err)
+	}
+}
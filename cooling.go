@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Defaults of the cooling model: water off the boil in a kettle with its
+// lid on loses half its excess heat over the room in about 24 minutes,
+// taking some 10 minutes to drop from 100°C to 80°C.
+const (
+	DefaultBoilTemp     = 100
+	DefaultRoomTemp     = 20
+	DefaultCoolHalfLife = 24 * time.Minute
+)
+
+// coolCurveWidth is the number of characters in the cooling curve.
+const coolCurveWidth = 16
+
+// coolMsg refreshes the cool-down panel once a second.
+type coolMsg struct {
+	id   int       // Cool-down the message belongs to
+	time time.Time // Time at which the tick fired
+}
+
+// tempPattern matches the first temperature in a preset's Temp, such as
+// "80°C", "175°F" or "70-80°C".
+var tempPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)(?:\s*[–-]\s*(\d+(?:\.\d+)?))?\s*°?\s*([CF])`)
+
+// parseTemp returns the temperature in Temp in °C, or false if it names
+// none. For a range, the lower bound is used, which is the later one to
+// reach while the water cools.
+func parseTemp(temp string) (float64, bool) {
+	low, _, ok := parseTempRange(temp)
+	return low, ok
+}
+
+// parseTempRange returns the lowest and highest temperature in Temp in °C,
+// which are the same unless it names a range, or false if it names none.
+func parseTempRange(temp string) (low, high float64, ok bool) {
+	match := tempPattern.FindStringSubmatch(strings.ToUpper(temp))
+	if match == nil {
+		return 0, 0, false
+	}
+	low, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	high = low
+	if match[2] != "" {
+		if high, err = strconv.ParseFloat(match[2], 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	if match[3] == "F" {
+		low, high = (low-32)*5/9, (high-32)*5/9
+	}
+	return low, high, true
+}
+
+// waterTemp estimates the water temperature elapsed after it boiled, by
+// Newton's law of cooling.
+func (c *Config) waterTemp(elapsed time.Duration) float64 {
+	excess := float64(c.BoilTemp - c.RoomTemp)
+	return float64(c.RoomTemp) + excess*math.Exp2(-elapsed.Seconds()/c.CoolHalfLife.Seconds())
+}
+
+// coolTime returns how long boiled water takes to cool to target, or false
+// if it never gets there because target is at or below room temperature.
+func (c *Config) coolTime(target float64) (time.Duration, bool) {
+	if target >= float64(c.BoilTemp) {
+		return 0, true
+	}
+	if target <= float64(c.RoomTemp) {
+		return 0, false
+	}
+	halfLives := math.Log2(float64(c.BoilTemp-c.RoomTemp) / (target - float64(c.RoomTemp)))
+	return time.Duration(halfLives * float64(c.CoolHalfLife)).Round(time.Second), true
+}
+
+// toggleCooling starts timing the water cooling from the boil, or stops it
+// if it is already running.
+func (m model) toggleCooling() (model, tea.Cmd) {
+	if !m.coolStart.IsZero() {
+		return m.stopCooling(), nil
+	}
+	m.coolID++
+	m.coolStart = now()
+	m.coolNow = m.coolStart
+	return m, coolTick(m.coolID)
+}
+
+// stopCooling hides the cool-down panel and lets its ticks run out.
+func (m model) stopCooling() model {
+	m.coolStart = time.Time{}
+	m.coolID++
+	return m
+}
+
+// coolTick returns a command that refreshes the cool-down panel in a second.
+func coolTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return coolMsg{id: id, time: t}
+	})
+}
+
+// coolingLabel describes the water temperature now and when to pour for
+// the selected tea, or returns "" while no cool-down runs.
+func (m model) coolingLabel() string {
+	if m.coolStart.IsZero() {
+		return ""
+	}
+	elapsed := m.coolNow.Sub(m.coolStart)
+	label := fmt.Sprintf("%s~%s now", m.icon("🌡", "Water"), m.config.Formats.temp(m.config.waterTemp(elapsed)))
+	preset := m.currentPreset()
+	target, ok := parseTemp(preset.Temp)
+	if !ok {
+		return label
+	}
+	pourAt, ok := m.config.coolTime(target)
+	switch {
+	case !ok:
+		return label + fmt.Sprintf(", too warm a room to reach %s", m.config.Formats.temp(target))
+	case elapsed >= pourAt:
+		return label + fmt.Sprintf(", pour now for %s", preset.Name)
+	}
+	wait := (pourAt - elapsed).Round(time.Second)
+	return label + fmt.Sprintf(", pour in ~%v for %s (%s)", wait, preset.Name, m.config.Formats.temp(target))
+}
+
+// renderCooling renders the cool-down panel: the estimate and a curve of the
+// cooling from the boil down to the selected tea's temperature, with the
+// part still ahead dimmed.
+func (m model) renderCooling(style lipgloss.Style) string {
+	label := m.coolingLabel()
+	target, ok := parseTemp(m.currentPreset().Temp)
+	pourAt, reachable := m.config.coolTime(target)
+	if !ok || !reachable || pourAt == 0 {
+		return style.Render(label)
+	}
+
+	// Heights above the target temperature, sampled across the cool-down
+	heights := make([]int, coolCurveWidth)
+	for i := range heights {
+		t := m.config.waterTemp(pourAt * time.Duration(i) / coolCurveWidth)
+		heights[i] = int(math.Ceil(t - target))
+	}
+	curve := []rune(sparkline(heights))
+	done := min(len(curve), int(float64(len(curve))*m.coolNow.Sub(m.coolStart).Seconds()/pourAt.Seconds()))
+	ready := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Colors.Ready))
+	return style.Render(label) + "\n" +
+		style.Render(m.config.Formats.temp(float64(m.config.BoilTemp))+" ") +
+		string(curve[:done]) + style.Render(string(curve[done:])) +
+		ready.Render(" "+m.config.Formats.temp(target))
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseTemp(t *testing.T) {
+	tests := []struct {
+		temp string
+		want float64
+		ok   bool
+	}{
+		{"80°C", 80, true},
+		{"75 °c", 75, true},
+		{"70-80°C", 70, true},
+		{"212°F", 100, true},
+		{"hot", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTemp(tt.temp)
+		if ok != tt.ok || math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("Expected %v, %v for %q, got %v, %v", tt.want, tt.ok, tt.temp, got, ok)
+		}
+	}
+}
+
+func TestCoolingModel(t *testing.T) {
+	c := NewConfig()
+	if got := c.waterTemp(0); got != 100 {
+		t.Errorf("Expected boiling water at the start, got %v", got)
+	}
+	if got := c.waterTemp(DefaultCoolHalfLife); got != 60 {
+		t.Errorf("Expected half the excess heat gone after one half-life, got %v", got)
+	}
+	d, ok := c.coolTime(60)
+	if !ok || d != DefaultCoolHalfLife {
+		t.Errorf("Expected 60°C after one half-life, got %v, %v", d, ok)
+	}
+	if d, ok := c.coolTime(100); !ok || d != 0 {
+		t.Errorf("Expected boiling water to be ready at once, got %v, %v", d, ok)
+	}
+	if _, ok := c.coolTime(15); ok {
+		t.Error("Expected water never to cool below room temperature")
+	}
+}
+
+func TestCoolingPanel(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.presetIdx = 1 // Green Tea at 80°C
+	send := func(msg tea.Msg) {
+		newModel, _ := m.Update(msg)
+		m = newModel.(model)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCool)})
+	if m.coolStart.IsZero() {
+		t.Fatal("Expected the cool key to start a cool-down")
+	}
+	if got := m.coolingLabel(); got != "🌡 ~100°C now, pour in ~9m58s for Green Tea (80°C)" {
+		t.Errorf("Unexpected label %q", got)
+	}
+
+	send(coolMsg{id: m.coolID, time: m.coolStart.Add(9 * time.Minute)})
+	if got := m.coolingLabel(); !strings.HasPrefix(got, "🌡 ~82°C now, pour in ~58s") {
+		t.Errorf("Unexpected label %q", got)
+	}
+	send(coolMsg{id: m.coolID, time: m.coolStart.Add(10 * time.Minute)})
+	if got := m.coolingLabel(); !strings.HasSuffix(got, "pour now for Green Tea") {
+		t.Errorf("Unexpected label %q", got)
+	}
+	m.width, m.height = 100, 50
+	if view := m.View(); !strings.Contains(view, "100°C ") || !strings.Contains(view, " 80°C") {
+		t.Error("Expected the cooling curve in the view")
+	}
+
+	// Starting the brew ends the cool-down and its refreshes
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	if !m.coolStart.IsZero() || m.coolingLabel() != "" {
+		t.Error("Expected starting the brew to end the cool-down")
+	}
+	newModel, cmd := m.Update(coolMsg{id: m.coolID - 1, time: now()})
+	if cmd != nil || !newModel.(model).coolStart.IsZero() {
+		t.Error("Expected a stale cool-down tick to be dropped")
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cue marks how far a countdown has got, for the accent color and the soft
+// cue sound.
+type cue int
+
+const (
+	// cueNone is the first part of the brew, before any cue
+	cueNone cue = iota
+	// cueHalfway is past the configured share of the brew
+	cueHalfway
+	// cueFinal is the final stretch before the tea is ready
+	cueFinal
+)
+
+// cueVolume is the volume of the soft cue sound relative to the alert.
+const cueVolume = 0.3
+
+// brewCue returns the cue the running or paused countdown has reached.
+// The stopwatch has no end and so no cues.
+func (m model) brewCue() cue {
+	if m.stopwatch || !(m.isBrewing() || m.isPaused()) {
+		return cueNone
+	}
+	total := m.brewDuration()
+	switch {
+	case m.config.FinalCue > 0 && m.timer <= m.config.FinalCue:
+		return cueFinal
+	case m.config.HalfwayCue > 0 && (total-m.timer)*100 >= total*time.Duration(m.config.HalfwayCue):
+		return cueHalfway
+	}
+	return cueNone
+}
+
+// brewingColor returns the accent for the running countdown.
+func (m model) brewingColor() string {
+	switch m.brewCue() {
+	case cueFinal:
+		return m.config.Colors.Final
+	case cueHalfway:
+		return m.config.Colors.Halfway
+	}
+	return m.config.Colors.Brewing
+}
+
+// cueAlertCmd raises the alerts routed for a cue the countdown has just
+// reached, by default the alert sound played quietly with cues.sound. They
+// are cut short by the same things that stop the finish alert.
+func (m model) cueAlertCmd(before cue) (model, tea.Cmd) {
+	reached := m.brewCue()
+	if reached == before || reached == cueNone {
+		return m, nil
+	}
+	event, body := EventHalfway, fmt.Sprintf("Your %s is halfway done", m.brewTea)
+	if reached == cueFinal {
+		event, body = EventFinal, fmt.Sprintf("Your %s is ready in %s", m.brewTea, m.config.TimeFormat.Format(m.timer))
+	}
+	if !m.config.routedAny(event) {
+		return m, nil
+	}
+	m = m.silence()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopAlert = cancel
+	return m, m.eventAlertCmd(ctx, event, body)
+}
+
+// cueCmd plays the alert sound quietly. Failures are only logged, since the
+// finished brew's alert reports sound problems.
+func cueCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		if err := playCue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Cue sound failed: %v", err)
+		}
+		return nil
+	}
+}
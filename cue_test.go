@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBrewCue(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.customDuration = time.Minute
+	m.state = StateBrewing
+
+	tests := []struct {
+		remaining time.Duration
+		want      cue
+		color     string
+	}{
+		{time.Minute, cueNone, ColorBrewing},
+		{31 * time.Second, cueNone, ColorBrewing},
+		{30 * time.Second, cueHalfway, ColorHalfway},
+		{11 * time.Second, cueHalfway, ColorHalfway},
+		{10 * time.Second, cueFinal, ColorFinal},
+		{time.Second, cueFinal, ColorFinal},
+	}
+	for _, tt := range tests {
+		m.timer = tt.remaining
+		if got := m.brewCue(); got != tt.want || m.brewingColor() != tt.color {
+			t.Errorf("Expected cue %d with %s at %v left, got %d with %s", tt.want, tt.color, tt.remaining, got, m.brewingColor())
+		}
+	}
+
+	// Disabled cues and the stopwatch keep the brewing color
+	m.config.HalfwayCue, m.config.FinalCue = 0, 0
+	m.timer = 5 * time.Second
+	if got := m.brewCue(); got != cueNone {
+		t.Errorf("Expected no cue when disabled, got %d", got)
+	}
+	m.config.FinalCue = 10 * time.Second
+	m.stopwatch = true
+	if got := m.brewCue(); got != cueNone {
+		t.Errorf("Expected no cue on the stopwatch, got %d", got)
+	}
+}
+
+func TestCueSound(t *testing.T) {
+	config := NewConfig()
+	config.CueSound = true
+	m := initialModel(config)
+	m.customDuration = time.Minute
+	m.state = StateBrewing
+	m.timer = 31 * time.Second
+
+	newModel, _ := m.Update(tickLeft(m, 30*time.Second))
+	m = newModel.(model)
+	if m.brewCue() != cueHalfway || m.stopAlert == nil {
+		t.Error("Expected crossing the halfway point to start the cue sound")
+	}
+	m = m.silence()
+
+	newModel, _ = m.Update(tickLeft(m, 29*time.Second))
+	if newModel.(model).stopAlert != nil {
+		t.Error("Expected no cue sound while staying past halfway")
+	}
+}
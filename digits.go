@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxDigits is the number of digits a duration can be typed with: up to
+// "mmss".
+const maxDigits = 4
+
+// digitEntry reports whether digit keys compose a duration, which they do
+// with behavior.digit_entry set, while idle and choosing presets outside
+// -kiosk.
+func (m model) digitEntry() bool {
+	return m.config.DigitEntry && !m.config.Kiosk && m.state == StateIdle && m.presetsSelectable()
+}
+
+// updateDigits handles a key press for direct duration entry and reports
+// whether it was used: digits extend the typed duration, backspace removes
+// the last digit, enter applies it and esc cancels. Other keys discard the
+// typed digits and are handled as usual.
+func (m model) updateDigits(key string) (model, tea.Cmd, bool) {
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		if len(m.digits) < maxDigits && (m.digits != "" || key != "0") {
+			m.digits += key
+		}
+		return m, nil, true
+	}
+	if m.digits == "" {
+		return m, nil, false
+	}
+	switch key {
+	case "backspace":
+		m.digits = m.digits[:len(m.digits)-1]
+		return m, nil, true
+	case "esc":
+		m.digits = ""
+		return m, nil, true
+	case "enter":
+		d := digitsDuration(m.digits)
+		m.digits = ""
+		if err := checkBrewTime(d); err != nil {
+			m, cmd := m.showStatus(err.Error())
+			return m, cmd, true
+		}
+		m = m.saveUndo("duration change")
+		m = m.clearCustom()
+		m.customDuration = d
+		m.timer = d
+		return m, nil, true
+	}
+	m.digits = ""
+	return m, nil, false
+}
+
+// digitsDuration converts typed digits to a duration like a microwave keypad:
+// the last two digits are seconds and any before them minutes, so "230" is
+// 2m30s and "90" is 1m30s.
+func digitsDuration(digits string) time.Duration {
+	n, _ := strconv.Atoi(digits)
+	return time.Duration(n/100)*time.Minute + time.Duration(n%100)*time.Second
+}
+
+// digitsView shows the typed digits in mm:ss form, filling from the right.
+func digitsView(digits string) string {
+	n, _ := strconv.Atoi(digits)
+	return fmt.Sprintf("Duration: %d:%02d", n/100, n%100)
+}
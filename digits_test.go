@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDigitsDuration(t *testing.T) {
+	for digits, want := range map[string]time.Duration{
+		"230":  2*time.Minute + 30*time.Second,
+		"90":   90 * time.Second,
+		"2500": 25 * time.Minute,
+		"5":    5 * time.Second,
+	} {
+		if got := digitsDuration(digits); got != want {
+			t.Errorf("digitsDuration(%q) = %v, want %v", digits, got, want)
+		}
+	}
+}
+
+func TestDigitEntry(t *testing.T) {
+	config := NewConfig()
+	config.DigitEntry = true
+	m := initialModel(config)
+	send := func(msg tea.KeyMsg) {
+		newModel, _ := m.Update(msg)
+		m = newModel.(model)
+	}
+	typeKeys := func(keys string) {
+		for _, r := range keys {
+			send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	// Digits compose a duration instead of picking presets
+	typeKeys("2305")
+	send(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.digits != "230" || m.presetIdx != 0 || !contains(m.View(), "Duration: 2:30") {
+		t.Fatalf("Expected typed duration 2:30, got digits %q and preset %d", m.digits, m.presetIdx)
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.digits != "" || m.timer != 2*time.Minute+30*time.Second || m.customDuration != m.timer {
+		t.Errorf("Expected enter to set 2m30s, got %v", m.timer)
+	}
+
+	// Esc cancels without changing the duration
+	typeKeys("45")
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.digits != "" || m.timer != 2*time.Minute+30*time.Second {
+		t.Errorf("Expected esc to cancel, got digits %q and timer %v", m.digits, m.timer)
+	}
+
+	// Durations outside the supported range are rejected
+	typeKeys("10")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.timer != 2*time.Minute+30*time.Second || m.status == "" {
+		t.Errorf("Expected 10s to be rejected, got timer %v and status %q", m.timer, m.status)
+	}
+}
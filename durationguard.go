@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// oddDurationRatio is how many times shorter or longer than the selected
+// preset's time a custom brew must be before starting it asks for a second
+// press. A 30s brew on a 5m herbal is far more likely a typo than a choice.
+const oddDurationRatio = 2
+
+// presetTime returns the time the selected preset would brew for, ignoring
+// any custom duration: its current infusion or its duration.
+func (m model) presetTime() time.Duration {
+	if d, ok := m.steepDuration(); ok {
+		return d
+	}
+	return m.currentPreset().Duration
+}
+
+// oddDuration reports whether the custom duration of the next brew is at
+// most half or at least twice the selected preset's time, and describes the
+// difference for the confirmation.
+func (m model) oddDuration() (string, bool) {
+	d, preset := m.brewDuration(), m.presetTime()
+	if d*oddDurationRatio > preset && d < preset*oddDurationRatio {
+		return "", false
+	}
+	return fmt.Sprintf("%v is far from %s's usual %v", d, m.currentPreset().Name, preset), true
+}
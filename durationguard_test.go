@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOddDuration(t *testing.T) {
+	m := initialModel(NewConfig()) // Rooibos, 4m
+	for _, tc := range []struct {
+		d   time.Duration
+		odd bool
+	}{
+		{30 * time.Second, true},
+		{2 * time.Minute, true},
+		{2*time.Minute + time.Second, false},
+		{6 * time.Minute, false},
+		{8 * time.Minute, true},
+	} {
+		m.customDuration = tc.d
+		if _, odd := m.oddDuration(); odd != tc.odd {
+			t.Errorf("Expected odd=%v for %v on a 4m preset", tc.odd, tc.d)
+		}
+	}
+}
+
+func TestConfirmOddDuration(t *testing.T) {
+	press := func(m model, key string) model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(model)
+	}
+	config := NewConfig()
+	config.ConfirmDuration = true
+	m := initialModel(config)
+	m.customDuration = 30 * time.Second
+
+	m = press(m, KeyStart)
+	if m.inputKind != inputNone || !m.startArmed {
+		t.Fatal("Expected the first press to ask for confirmation")
+	}
+	if !strings.Contains(m.status, "30s is far from Rooibos's usual 4m0s") {
+		t.Errorf("Expected the status to explain the difference, got %q", m.status)
+	}
+	if m = press(m, KeyStart); m.inputKind != inputBrewName {
+		t.Error("Expected the second press to go ahead with the brew")
+	}
+
+	// Any other key cancels the confirmation
+	m = m.closeInput()
+	m = press(m, KeyStart)
+	m = press(m, KeyInfo)
+	if m = press(m, KeyStart); m.inputKind != inputNone {
+		t.Error("Expected a press after another key to ask again")
+	}
+
+	// Durations close to the preset's start without asking
+	m = m.closeInput()
+	m.startArmed = false
+	m.customDuration = 3 * time.Minute
+	if m = press(m, KeyStart); m.inputKind != inputBrewName {
+		t.Error("Expected a usual duration to start without confirmation")
+	}
+
+	// Off by default
+	m = initialModel(NewConfig())
+	m.customDuration = 30 * time.Second
+	if m = press(m, KeyStart); m.inputKind != inputBrewName {
+		t.Error("Expected no confirmation with confirm_duration off")
+	}
+}
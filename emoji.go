@@ -0,0 +1,15 @@
+package main
+
+// icon returns emoji followed by a space or, with display.emoji turned off,
+// the text label that stands in for it. Some fonts draw emoji double width
+// and others as tofu, which throws off centering. An empty label drops the
+// icon where the words next to it already say what it means.
+func (m model) icon(emoji, label string) string {
+	switch {
+	case m.config.Emoji:
+		return emoji + " "
+	case label != "":
+		return label + " "
+	}
+	return ""
+}
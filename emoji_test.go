@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmojiOff(t *testing.T) {
+	config := NewConfig()
+	config.Emoji = false
+	m := initialModel(config)
+	m.note = "second steep"
+
+	emoji := []string{"🫖", "⏰", "⏱", "⏸", "🍵", "📝", "👉", "🌡", "🔇", "🔕", "⚠"}
+	check := func(state string, text string) {
+		t.Helper()
+		for _, e := range emoji {
+			if strings.Contains(text, e) {
+				t.Errorf("Expected no %s with emoji off while %s, got:\n%s", e, state, text)
+			}
+		}
+	}
+
+	check("idle", m.View())
+	m.state = StateBrewing
+	check("brewing", m.View()+"\n"+m.windowTitle())
+	if !strings.Contains(m.windowTitle(), "Brewing ") {
+		t.Errorf("Expected a text label in the window title, got %q", m.windowTitle())
+	}
+	m.state = StateFinished
+	m.soundFailed = true
+	check("finished", m.View()+"\n"+m.windowTitle())
+	if !strings.Contains(m.View(), "Note: second steep") {
+		t.Errorf("Expected the note labelled in text, got:\n%s", m.View())
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// enterFavorite is the behavior.enter_starts value that starts the most
+// brewed preset.
+const enterFavorite = "favorite"
+
+// checkEnterStarts reports a behavior.enter_starts value that names no
+// preset.
+func (c *Config) checkEnterStarts() error {
+	if c.EnterStarts == "" || c.EnterStarts == enterFavorite {
+		return nil
+	}
+	if presetByName(c.Presets, c.EnterStarts) < 0 {
+		return fmt.Errorf("behavior.enter_starts: there is no preset called %q (or use %q)", c.EnterStarts, enterFavorite)
+	}
+	return nil
+}
+
+// presetByName returns the index of the preset called name, regardless of
+// case, or -1 if there is none.
+func presetByName(presets []TeaPreset, name string) int {
+	for i, p := range presets {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// enterPreset returns the index of the preset enter starts: the one named by
+// behavior.enter_starts or, for "favorite", the preset brewed most often in
+// the brew log, falling back to the first preset before there are any brews.
+func (m model) enterPreset() int {
+	if m.config.EnterStarts != enterFavorite {
+		return max(presetByName(m.config.Presets, m.config.EnterStarts), 0)
+	}
+	teas, _ := brewsPerTea(m.history)
+	for _, name := range teas {
+		if i := presetByName(m.config.Presets, name); i >= 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// startEnterPreset selects the preset enter starts and brews it right away.
+func (m model) startEnterPreset() (model, tea.Cmd) {
+	m = m.clearFinishedNote()
+	m.presetIdx = m.enterPreset()
+	m.infusion = 0
+	m = m.clearCustom()
+	return m.start()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEnterStarts(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	tests := []struct {
+		name    string
+		setting string
+		history []brewRecord
+		want    string // Tea brewing after enter, "" for none
+	}{
+		{"off", "", nil, ""},
+		{"named preset", "oolong", nil, "Oolong"},
+		{"favorite", enterFavorite, []brewRecord{{Tea: "Custom"}, {Tea: "Custom"}, {Tea: "Herbal"}, {Tea: "Black Tea"}, {Tea: "Herbal"}}, "Herbal"},
+		{"favorite without brews", enterFavorite, nil, DefaultTeaPresets[0].Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.Presets = DefaultTeaPresets
+			config.SoundEnabled = false
+			config.NotifyEnabled = false
+			config.EnterStarts = tt.setting
+			m := initialModel(config)
+			m.history = tt.history
+
+			newModel, _ := m.Update(enter)
+			m = newModel.(model)
+			if tt.want == "" {
+				if m.isBrewing() {
+					t.Error("Expected enter to do nothing")
+				}
+				return
+			}
+			if !m.isBrewing() || m.brewTea != tt.want {
+				t.Errorf("Expected enter to brew %s, got %q in state %v", tt.want, m.brewTea, m.state)
+			}
+		})
+	}
+}
+
+func TestEnterStartsUnknownPreset(t *testing.T) {
+	config := NewConfig()
+	config.Presets = DefaultTeaPresets
+	config.EnterStarts = "Sencha"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an error for an unknown behavior.enter_starts preset")
+	}
+	config.EnterStarts = enterFavorite
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected favorite to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Brew lifecycle events. Every brew is created and started at once, may be
+// paused and resumed any number of times, and ends finished or abandoned.
+// A finished brew can then be collected, or abandoned if it goes cold first.
+const (
+	BrewCreated   = "created"
+	BrewStarted   = "started"
+	BrewPaused    = "paused"
+	BrewResumed   = "resumed"
+	BrewFinished  = "finished"
+	BrewAbandoned = "abandoned"
+	BrewCollected = "collected"
+)
+
+// hookClient posts brew events to the hooks.url webhook.
+var hookClient = &http.Client{Timeout: 10 * time.Second}
+
+// brewEvent is one step in the life of a brew, as kept in the event log and
+// posted to the webhook. Consumers can drop an event whose ID they have seen,
+// as a retried post may arrive twice.
+type brewEvent struct {
+	ID        string    `json:"id"`                 // Unique to this event
+	Brew      string    `json:"brew"`               // ID of the brew, as in its history record
+	Type      string    `json:"type"`               // One of the Brew lifecycle events
+	Time      time.Time `json:"time"`               // When it happened
+	Tea       string    `json:"tea"`                // Tea being brewed
+	Duration  Duration  `json:"duration"`           // Full length of the brew
+	Remaining Duration  `json:"remaining,omitzero"` // Brew time left, for pauses and abandoned brews
+}
+
+// eventRecorder is implemented by stores that keep the brew events next to
+// the brews.
+type eventRecorder interface {
+	AppendEvent(ev brewEvent) error
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// eventsPath returns the event log kept beside the JSON Lines brew log at
+// path, e.g. history.events.jsonl for history.jsonl.
+func eventsPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".events.jsonl"
+}
+
+// AppendEvent adds ev to the event log beside the brew log. An encrypted
+// log keeps no events, as they would give away the teas it hides.
+func (s jsonlStore) AppendEvent(ev brewEvent) error {
+	if s.key != nil {
+		return nil
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	path := eventsPath(s.path)
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// logBrewEvent adds ev to the event log of the brew log at path, if its store
+// keeps one.
+func logBrewEvent(path string, key *historyKey, ev brewEvent) error {
+	store, err := openHistory(path, key)
+	if err != nil {
+		return err
+	}
+	if rec, ok := store.(eventRecorder); ok {
+		return rec.AppendEvent(ev)
+	}
+	return nil
+}
+
+// postEvent posts ev as JSON to the webhook at url.
+func postEvent(ctx context.Context, url string, ev brewEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Lets the receiver ignore an event delivered twice
+	req.Header.Set("Idempotency-Key", ev.ID)
+	resp, err := hookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("brew event hook: %s", resp.Status)
+	}
+	return nil
+}
+
+// brewEvents returns the running brew's events of types at time at, or nil
+// if there is nowhere to send them.
+func (m model) brewEvents(at time.Time, types ...string) []brewEvent {
+	if m.brewUUID == "" || (m.config.HistoryFile == "" && m.config.HookURL == "") {
+		return nil
+	}
+	events := make([]brewEvent, len(types))
+	for i, typ := range types {
+		events[i] = brewEvent{
+			ID:       newUUID(),
+			Brew:     m.brewUUID,
+			Type:     typ,
+			Time:     at,
+			Tea:      m.brewTea,
+			Duration: Duration{m.brewDuration()},
+		}
+		if typ == BrewPaused || typ == BrewAbandoned {
+			events[i].Remaining = Duration{m.timer}
+		}
+	}
+	return events
+}
+
+// brewEventCmd returns a command that logs the running brew's events of
+// types, in order, and posts each to the webhook, or nil if there is nowhere
+// to send them. A post that keeps failing is retried like an alert; then the
+// events wait in the queue for a later delivery, if there is one, or the
+// error is shown.
+func (m model) brewEventCmd(at time.Time, types ...string) tea.Cmd {
+	path, key, url, queue := m.config.HistoryFile, m.config.HistoryKey, m.config.HookURL, m.config.HookQueuePath
+	events := m.brewEvents(at, types...)
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		// The webhook still gets the events if they can't be logged
+		var logErr error
+		for _, ev := range events {
+			if path != "" && logErr == nil {
+				logErr = logBrewEvent(path, key, ev)
+			}
+		}
+		ctx := context.Background()
+		switch {
+		case url != "" && queue != "":
+			if err := enqueueHooks(queue, queueable(events, key), at); err != nil {
+				return errMsg{fmt.Errorf("queueing brew event: %w", err)}
+			}
+			if left, err := flushHooks(ctx, queue, url, at); err != nil {
+				return hookFlushMsg{left: left, err: err}
+			}
+		case url != "":
+			for _, ev := range events {
+				if err := retryAlert(ctx, "brew event", func() error { return postEvent(ctx, url, ev) }); err != nil {
+					return errMsg{fmt.Errorf("sending brew event: %w", err)}
+				}
+			}
+		}
+		if logErr != nil {
+			return errMsg{fmt.Errorf("saving brew event: %w", logErr)}
+		}
+		return nil
+	}
+}
+
+// abandonOnExit logs the abandoned event of a brew still running or paused
+// in the final model when the program exits, and queues it for the webhook.
+// It runs after the program has stopped, as a command started on quitting
+// could be cut short. Nothing is posted, so an unreachable webhook can't hold
+// up the exit; the queue is delivered on the next start.
+func abandonOnExit(final tea.Model) error {
+	var m model
+	switch f := final.(type) {
+	case model:
+		m = f
+	case recorder:
+		m = f.model
+	case plainPrinter:
+		m = f.model
+	default:
+		return nil
+	}
+	if !m.isBrewing() && !m.isPaused() {
+		return nil
+	}
+	at := now()
+	events := m.brewEvents(at, BrewAbandoned)
+	if events == nil {
+		return nil
+	}
+	var errs []error
+	if path := m.config.HistoryFile; path != "" {
+		if err := logBrewEvent(path, m.config.HistoryKey, events[0]); err != nil {
+			errs = append(errs, fmt.Errorf("saving brew event: %w", err))
+		}
+	}
+	if m.config.HookURL != "" && m.config.HookQueuePath != "" {
+		if err := enqueueHooks(m.config.HookQueuePath, queueable(events, m.config.HistoryKey), at); err != nil {
+			errs = append(errs, fmt.Errorf("queueing brew event: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
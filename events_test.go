@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	a, b := newUUID(), newUUID()
+	if !pattern.MatchString(a) || a == b {
+		t.Errorf("Expected two different version 4 UUIDs, got %q and %q", a, b)
+	}
+}
+
+// eventHook is a webhook that keeps the events posted to it.
+func eventHook(t *testing.T) (*httptest.Server, func() []brewEvent) {
+	var mu sync.Mutex
+	var events []brewEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev brewEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Expected a JSON event, got %v", err)
+		}
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []brewEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return events
+	}
+}
+
+// readEvents returns the events in the JSON Lines event log at path.
+func readEvents(t *testing.T, path string) []brewEvent {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var events []brewEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev brewEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestBrewLifecycleEvents(t *testing.T) {
+	server, posted := eventHook(t)
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled = false, false
+	config.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	config.HookURL = server.URL
+	m := initialModel(config)
+	press := func(msg tea.Msg) {
+		newModel, cmd := m.Update(msg)
+		m = newModel.(model)
+		for _, msg := range cmdMsgs(cmd) {
+			if err, ok := msg.(errMsg); ok {
+				t.Fatalf("Expected the events to be sent, got %v", err.err)
+			}
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	brew := m.brewUUID
+	m.deadline = now().Add(90 * time.Second)
+	press(tea.KeyMsg{Type: tea.KeySpace})
+	press(tea.KeyMsg{Type: tea.KeySpace})
+	m.timer = time.Second
+	press(tickLeft(m, 0))
+
+	want := []string{BrewCreated, BrewStarted, BrewPaused, BrewResumed, BrewFinished}
+	for name, events := range map[string][]brewEvent{"posted": posted(), "logged": readEvents(t, eventsPath(config.HistoryFile))} {
+		if len(events) != len(want) {
+			t.Fatalf("Expected %d %s events, got %v", len(want), name, events)
+		}
+		seen := map[string]bool{}
+		for i, ev := range events {
+			if ev.Type != want[i] || ev.Brew != brew || ev.Tea != "Rooibos" || seen[ev.ID] {
+				t.Errorf("Expected %s event %d to be a unique %q of brew %s, got %+v", name, i, want[i], brew, ev)
+			}
+			seen[ev.ID] = true
+		}
+		if events[2].Remaining.Duration != 90*time.Second {
+			t.Errorf("Expected the pause to record the time left, got %v", events[2].Remaining)
+		}
+	}
+
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || records[0].ID != brew {
+		t.Errorf("Expected the brew logged with its ID %s, got %v, %v", brew, records, err)
+	}
+}
+
+func TestAbandonedBrewEvents(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled = false, false
+	config.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	m := initialModel(config)
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	m = newModel.(model)
+	first := m.brewUUID
+
+	// Resetting gives up on the brew
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyReset)})
+	m = newModel.(model)
+	cmdMsgs(cmd)
+	// So does quitting halfway through another
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	if err := abandonOnExit(newModel); err != nil {
+		t.Fatal(err)
+	}
+	second := newModel.(model).brewUUID
+	if second == first {
+		t.Error("Expected each brew to get its own ID")
+	}
+
+	var abandoned []string
+	for _, ev := range readEvents(t, eventsPath(config.HistoryFile)) {
+		if ev.Type == BrewAbandoned {
+			abandoned = append(abandoned, ev.Brew)
+		}
+	}
+	if len(abandoned) != 2 || abandoned[0] != first || abandoned[1] != second {
+		t.Errorf("Expected both brews abandoned, got %v", abandoned)
+	}
+}
+
+func TestSQLiteEvents(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	store := sqliteStore{path: filepath.Join(t.TempDir(), "history.db")}
+	ev := brewEvent{ID: newUUID(), Brew: newUUID(), Type: BrewStarted, Time: now(), Tea: "Sencha", Duration: Duration{time.Minute}}
+	// A retried event is only kept once
+	for range 2 {
+		if err := store.AppendEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, err := store.run("SELECT brew, type, tea FROM events;\n", "-csv", "-noheader")
+	if err != nil || string(out) != ev.Brew+",started,Sencha\n" {
+		t.Errorf("Expected the event once, got %q, %v", out, err)
+	}
+
+	rec := brewRecord{ID: ev.Brew, Time: now(), Tea: "Sencha", Duration: Duration{time.Minute}}
+	if err := store.Append(rec); err != nil {
+		t.Fatal(err)
+	}
+	if records, err := store.Load(); err != nil || len(records) != 1 || records[0] != rec {
+		t.Errorf("Expected the brew back with its ID, got %v, %v", records, err)
+	}
+}
+
+func TestHookURLConfig(t *testing.T) {
+	fc, _, err := loadConfigFile(writeConfig(t, "[hooks]\nurl = \"hooks.example.com\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := fc.validate(); len(errs) != 1 {
+		t.Errorf("Expected a URL without a scheme to be rejected, got %v", errs)
+	}
+}
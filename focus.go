@@ -0,0 +1,30 @@
+package main
+
+import "time"
+
+// blurredStep is how coarsely the countdown moves while the terminal window
+// is unfocused. Nobody reads every second of a window in the background,
+// and redrawing it every five seconds instead saves the terminal the work.
+// The window title still counts every second.
+const blurredStep = 5 * time.Second
+
+// shownTimer returns the time displayed in the view. While the window is
+// unfocused a running countdown is rounded up and a running stopwatch down
+// to blurredStep, except in the final stretch, which always counts every
+// second.
+func (m model) shownTimer() time.Duration {
+	if !m.unfocused || !m.isBrewing() {
+		// Between sub-second ticks the stopwatch shows whole seconds gone
+		if m.stopwatch {
+			return m.timer.Truncate(time.Second)
+		}
+		return m.timer
+	}
+	if m.stopwatch {
+		return m.timer.Truncate(blurredStep)
+	}
+	if m.timer <= m.config.FinalCue {
+		return m.timer
+	}
+	return (m.timer + blurredStep - 1).Truncate(blurredStep)
+}
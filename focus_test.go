@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestShownTimerUnfocused(t *testing.T) {
+	m := initialModel(NewConfig())
+	m, _ = m.start()
+	m.timer = 2*time.Minute + 34*time.Second
+
+	newModel, _ := m.Update(tea.BlurMsg{})
+	m = newModel.(model)
+	if got := m.shownTimer(); got != 2*time.Minute+35*time.Second {
+		t.Errorf("Expected the countdown rounded up to 2m35s, got %v", got)
+	}
+	if m.windowTitle() != "⏰ 02:34 · go-brew" {
+		t.Errorf("Expected the window title to stay exact, got %q", m.windowTitle())
+	}
+
+	// The frame only changes every few seconds
+	key := m.viewKey()
+	for range 3 {
+		m.timer -= time.Second
+		if m.viewKey() != key {
+			t.Fatalf("Expected the view to stay unchanged at %v", m.timer)
+		}
+	}
+	m.timer -= time.Second
+	if m.viewKey() == key {
+		t.Error("Expected the view to change after 5 seconds")
+	}
+
+	// The final stretch counts every second
+	m.timer = 7 * time.Second
+	if got := m.shownTimer(); got != 7*time.Second {
+		t.Errorf("Expected the final stretch to be exact, got %v", got)
+	}
+
+	m.timer = 2*time.Minute + 31*time.Second
+	newModel, _ = m.Update(tea.FocusMsg{})
+	if got := newModel.(model).shownTimer(); got != m.timer {
+		t.Errorf("Expected the exact time once focused again, got %v", got)
+	}
+
+	// The stopwatch rounds down
+	m.stopwatch = true
+	m.timer = 14 * time.Second
+	if got := m.shownTimer(); got != 10*time.Second {
+		t.Errorf("Expected the stopwatch rounded down to 10s, got %v", got)
+	}
+}
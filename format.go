@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeFormat selects how the remaining time is written in the timer view,
+// the terminal window title and desktop notifications.
+type TimeFormat string
+
+const (
+	// FormatClock shows minutes and seconds, e.g. "02:30", and switches to
+	// hours, minutes and seconds from one hour up, e.g. "1:05:00"
+	FormatClock TimeFormat = "mm:ss"
+	// FormatLongClock shows hours, minutes and seconds, e.g. "0:02:30"
+	FormatLongClock TimeFormat = "h:mm:ss"
+	// FormatSeconds shows the total number of seconds, e.g. "150s"
+	FormatSeconds TimeFormat = "seconds"
+	// FormatWords shows the non-zero leading units, e.g. "2m 30s"
+	FormatWords TimeFormat = "words"
+)
+
+// timeFormats lists the valid time formats in the order they are documented.
+var timeFormats = []TimeFormat{FormatClock, FormatLongClock, FormatSeconds, FormatWords}
+
+// UnmarshalText validates and stores a time format name.
+func (f *TimeFormat) UnmarshalText(text []byte) error {
+	for _, format := range timeFormats {
+		if string(text) == string(format) {
+			*f = format
+			return nil
+		}
+	}
+	names := make([]string, len(timeFormats))
+	for i, format := range timeFormats {
+		names[i] = fmt.Sprintf("%q", format)
+	}
+	return fmt.Errorf("invalid time format %q (use %s)", text, strings.Join(names, ", "))
+}
+
+// splitDuration breaks d into whole hours, minutes and seconds. Partial
+// seconds round up, so a countdown shows 00:01 until it has really finished
+// rather than 00:00 for most of the last second. Negative durations count as
+// zero.
+func splitDuration(d time.Duration) (total, h, m, s int) {
+	if d <= 0 {
+		return 0, 0, 0, 0
+	}
+	total = int((d + time.Second - 1) / time.Second)
+	return total, total / 3600, total / 60 % 60, total % 60
+}
+
+// displaySeconds returns the whole seconds shown for d, rounded the same way
+// as Format.
+func displaySeconds(d time.Duration) int {
+	total, _, _, _ := splitDuration(d)
+	return total
+}
+
+// Format writes d in the time format. Unknown formats fall back to mm:ss.
+func (f TimeFormat) Format(d time.Duration) string {
+	total, h, m, s := splitDuration(d)
+	switch f {
+	case FormatLongClock:
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	case FormatSeconds:
+		return fmt.Sprintf("%ds", total)
+	case FormatWords:
+		switch {
+		case h > 0:
+			return fmt.Sprintf("%dh %dm %ds", h, m, s)
+		case m > 0:
+			return fmt.Sprintf("%dm %ds", m, s)
+		}
+		return fmt.Sprintf("%ds", s)
+	}
+	if h > 0 {
+		// Minutes above 59 would be misread, so long brews show hours
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	d := 2*time.Minute + 30*time.Second
+	tests := []struct {
+		format TimeFormat
+		d      time.Duration
+		want   string
+	}{
+		{FormatClock, d, "02:30"},
+		{FormatLongClock, d, "0:02:30"},
+		{FormatLongClock, time.Hour + 5*time.Second, "1:00:05"},
+		{FormatSeconds, d, "150s"},
+		{FormatWords, d, "2m 30s"},
+		{FormatWords, 45 * time.Second, "45s"},
+		{FormatWords, time.Hour + 2*time.Minute, "1h 2m 0s"},
+		{"", d, "02:30"},
+
+		// Hour-long durations
+		{FormatClock, 59*time.Minute + 59*time.Second, "59:59"},
+		{FormatClock, time.Hour, "1:00:00"},
+		{FormatClock, 2*time.Hour + 5*time.Minute + 9*time.Second, "2:05:09"},
+		{FormatSeconds, 2 * time.Hour, "7200s"},
+		{FormatWords, 24 * time.Hour, "24h 0m 0s"},
+
+		// Sub-minute and sub-second durations round partial seconds up
+		{FormatClock, 45 * time.Second, "00:45"},
+		{FormatClock, 300 * time.Millisecond, "00:01"},
+		{FormatClock, 2*time.Minute + 59*time.Second + 500*time.Millisecond, "03:00"},
+		{FormatClock, 59*time.Minute + 59*time.Second + time.Millisecond, "1:00:00"},
+		{FormatWords, 1500 * time.Millisecond, "2s"},
+		{FormatClock, 0, "00:00"},
+		{FormatClock, -3 * time.Second, "00:00"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Format(tt.d); got != tt.want {
+			t.Errorf("%q.Format(%v): expected %q, got %q", tt.format, tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestTimeFormatUnmarshal(t *testing.T) {
+	var f TimeFormat
+	if err := f.UnmarshalText([]byte("words")); err != nil || f != FormatWords {
+		t.Errorf("Expected words format, got %q, %v", f, err)
+	}
+	if err := f.UnmarshalText([]byte("hh:mm")); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestWindowTitle(t *testing.T) {
+	config := NewConfig()
+	config.TimeFormat = FormatWords
+	m := initialModel(config)
+	if got := m.windowTitle(); got != "go-brew" {
+		t.Errorf("Expected plain title while idle, got %q", got)
+	}
+
+	m.state, m.timer = StateBrewing, 90*time.Second
+	if got := m.windowTitle(); got != "⏰ 1m 30s · go-brew" {
+		t.Errorf("Expected countdown in the title, got %q", got)
+	}
+	m, cmd := m.syncTitle(nil)
+	if cmd == nil || m.title != "⏰ 1m 30s · go-brew" {
+		t.Error("Expected the title to be set when it changes")
+	}
+	if _, cmd = m.syncTitle(nil); cmd != nil {
+		t.Error("Expected no title command when the title is unchanged")
+	}
+}
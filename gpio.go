@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	gpioPulses = 5                      // Times the GPIO pin is switched on per alert
+	gpioPulse  = 300 * time.Millisecond // How long each pulse stays on, and off in between
+)
+
+// gpioCmd pulses the buzzer or LED on the given GPIO pin when a brew
+// finishes, for a headless kitchen Pi. Failures are reported in the status
+// line; cancellation stops the pulses and is not a failure.
+func gpioCmd(ctx context.Context, pin int) tea.Cmd {
+	return func() tea.Msg {
+		if err := pulseGPIO(ctx, pin, gpioPulses, gpioPulse); err != nil && ctx.Err() == nil {
+			return errMsg{fmt.Errorf("GPIO alert on pin %d: %w", pin, err)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !(linux && (arm || arm64))
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// pulseGPIO is unavailable off Linux on ARM, where there is no GPIO header.
+func pulseGPIO(ctx context.Context, pin, pulses int, period time.Duration) error {
+	return errors.New("GPIO alerts are only supported on Linux on ARM boards such as the Raspberry Pi")
+}
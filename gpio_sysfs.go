@@ -0,0 +1,59 @@
+//go:build linux && (arm || arm64)
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// gpioRoot is the sysfs GPIO interface, which needs no cgo or extra
+// packages and works on every Raspberry Pi OS release.
+const gpioRoot = "/sys/class/gpio"
+
+// pulseGPIO switches the sysfs GPIO pin on and off pulses times, leaving it
+// off. A pin that was not exported before is unexported again afterwards.
+func pulseGPIO(ctx context.Context, pin, pulses int, period time.Duration) error {
+	dir := filepath.Join(gpioRoot, "gpio"+strconv.Itoa(pin))
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(filepath.Join(gpioRoot, "export"), []byte(strconv.Itoa(pin)), 0); err != nil {
+			return fmt.Errorf("exporting pin: %w", err)
+		}
+		defer os.WriteFile(filepath.Join(gpioRoot, "unexport"), []byte(strconv.Itoa(pin)), 0)
+	}
+
+	// udev may need a moment to make a freshly exported pin writable
+	direction := filepath.Join(dir, "direction")
+	var err error
+	for try := 0; try < 10; try++ {
+		if err = os.WriteFile(direction, []byte("out"), 0); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		return fmt.Errorf("setting pin direction: %w", err)
+	}
+
+	value := filepath.Join(dir, "value")
+	defer os.WriteFile(value, []byte("0"), 0)
+	for i := 0; i < pulses; i++ {
+		for _, level := range []string{"1", "0"} {
+			if err := os.WriteFile(value, []byte(level), 0); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(period):
+			}
+		}
+	}
+	return nil
+}
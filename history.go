@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyFileName is the name of the brew log inside the data directory.
+const historyFileName = "history.jsonl"
+
+// customTeaName is recorded for custom-duration brews the user did not name.
+const customTeaName = "Custom"
+
+// brewRecord is one finished brew in the history log. The log is a JSON Lines
+// file by default, so each brew is appended as a single line without
+// rewriting the file; see HistoryStore for the alternative.
+type brewRecord struct {
+	ID        string    `json:"id,omitempty"`        // Brew ID, as in its events; empty for brews logged before IDs
+	Time      time.Time `json:"time"`                // When the brew finished
+	Tea       string    `json:"tea"`                 // Preset name or the name typed for a custom brew
+	Duration  Duration  `json:"duration"`            // Length of the brew
+	Custom    bool      `json:"custom,omitempty"`    // Whether a custom duration was used instead of a preset
+	Note      string    `json:"note,omitempty"`      // Note attached to the brew
+	Infusion  int       `json:"infusion,omitempty"`  // Number of the infusion of a preset with steeps
+	Rating    int       `json:"rating,omitempty"`    // How the brew was rated, 1 to maxRating, 0 if unrated
+	Blind     bool      `json:"blind,omitempty"`     // Whether the tea was brewed in a blind taste test
+	Abandoned bool      `json:"abandoned,omitempty"` // Whether the brew went cold before it was collected
+}
+
+// defaultHistoryPath returns the location of the brew log.
+func defaultHistoryPath() string {
+	return filepath.Join(dirs().Data, historyFileName)
+}
+
+// jsonlStore is a brew log kept as a JSON Lines file, encrypted with key
+// unless it is nil.
+type jsonlStore struct {
+	path string
+	key  *historyKey
+}
+
+// Append adds a record to the log, creating the file and its directory if
+// needed. With a key the record is encrypted, and a log still in plain text
+// is encrypted as a whole first.
+func (s jsonlStore) Append(rec brewRecord) error {
+	path, key := s.path, s.key
+	first, err := readFirstLine(path)
+	if err != nil {
+		return err
+	}
+	h, encrypted := parseHistoryHeader(first)
+	if key == nil && encrypted {
+		return encryptedError(path)
+	}
+	if key != nil && !encrypted {
+		records, err := jsonlStore{path, nil}.Load()
+		if err != nil {
+			return err
+		}
+		return s.Replace(append(records, rec))
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if key != nil {
+		aead, err := key.aead(h)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if line, err = sealRecord(aead, rec); err != nil {
+			return err
+		}
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readFirstLine returns the first line of the file at path, or nil if the
+// file doesn't exist or is empty.
+func readFirstLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return bytes.TrimSpace(line), nil
+}
+
+// encryptedError explains that the log at path needs a key to be read.
+func encryptedError(path string) error {
+	return fmt.Errorf("%s is encrypted: turn on history.encrypt and set history.keyfile or %s", path, historyPassphraseEnv)
+}
+
+// Load reads every record from the log, oldest first. A missing log is not
+// an error and yields no records. An encrypted log needs its key; a plain
+// one is read with or without.
+func (s jsonlStore) Load() ([]brewRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseHistory(data, s.path, s.key)
+}
+
+// parseHistory reads the records of the brew log data read from name.
+func parseHistory(data []byte, name string, key *historyKey) ([]brewRecord, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	var aead cipher.AEAD
+	if h, ok := parseHistoryHeader(bytes.TrimSpace(lines[0])); ok {
+		if key == nil {
+			return nil, encryptedError(name)
+		}
+		var err error
+		if aead, err = key.aead(h); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		lines[0] = nil
+	}
+
+	var records []brewRecord
+	for i, line := range lines {
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+		var rec brewRecord
+		var err error
+		if aead != nil {
+			rec, err = openRecord(aead, line)
+		} else {
+			err = json.Unmarshal(line, &rec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, i+1, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// encodeHistory writes records as a brew log, encrypted under header h of
+// key, or in plain text when key is nil.
+func encodeHistory(records []brewRecord, key *historyKey, h historyHeader) ([]byte, error) {
+	var buf bytes.Buffer
+	if key == nil {
+		enc := json.NewEncoder(&buf)
+		for _, rec := range records {
+			if err := enc.Encode(rec); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	}
+
+	aead, err := key.aead(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(&buf).Encode(h); err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		line, err := sealRecord(aead, rec)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+	}
+	return buf.Bytes(), nil
+}
+
+// header returns the header of the first of logs that key opens, so a
+// rewritten log keeps its salt, or a new header if none does.
+func (k *historyKey) header(logs ...[]byte) (historyHeader, error) {
+	for _, data := range logs {
+		first, _, _ := bytes.Cut(data, []byte("\n"))
+		if h, ok := parseHistoryHeader(bytes.TrimSpace(first)); ok {
+			if _, err := k.aead(h); err == nil {
+				return h, nil
+			}
+		}
+	}
+	return k.newHeader()
+}
+
+// Replace rewrites the log with records, keeping the salt of an encrypted
+// log. The file is written in one go, so a failed write leaves it as it was.
+func (s jsonlStore) Replace(records []brewRecord) error {
+	var h historyHeader
+	if s.key != nil {
+		existing, err := readOptional(s.path)
+		if err != nil {
+			return err
+		}
+		if h, err = s.key.header(existing); err != nil {
+			return err
+		}
+	}
+	data, err := encodeHistory(records, s.key, h)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// recordBrewCmd returns a command that appends rec to the brew log, then
+// drops the brews outside keep, or nil when history is disabled. Failures
+// are reported in the status line.
+func recordBrewCmd(path string, key *historyKey, rec brewRecord, keep HistoryLimits) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := appendHistory(path, key, rec); err != nil {
+			return errMsg{fmt.Errorf("saving brew history: %w", err)}
+		}
+		if _, err := pruneHistory(path, key, keep, rec.Time); err != nil {
+			return errMsg{fmt.Errorf("pruning brew history: %w", err)}
+		}
+		return nil
+	}
+}
+
+// rateBrew sets the rating of the brew that finished at t in the brew log at
+// path.
+func rateBrew(path string, key *historyKey, t time.Time, rating int) error {
+	return updateBrew(path, key, t, func(rec *brewRecord) { rec.Rating = rating })
+}
+
+// updateBrew applies change to the brew that finished at t in the brew log
+// at path. The log is rewritten in one go, so a failed write leaves it as it
+// was.
+func updateBrew(path string, key *historyKey, t time.Time, change func(*brewRecord)) error {
+	records, err := loadHistory(path, key)
+	if err != nil {
+		return err
+	}
+	i := len(records) - 1
+	for i >= 0 && !records[i].Time.Equal(t) {
+		i--
+	}
+	if i < 0 {
+		return fmt.Errorf("no brew finished at %s in %s", t.Format(time.RFC3339), path)
+	}
+	change(&records[i])
+	return writeHistory(path, key, records)
+}
+
+// abandonBrewCmd returns a command that flags the brew that finished at t as
+// abandoned in the brew log, or nil when history is disabled.
+func abandonBrewCmd(path string, key *historyKey, t time.Time) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := updateBrew(path, key, t, func(rec *brewRecord) { rec.Abandoned = true }); err != nil {
+			return errMsg{fmt.Errorf("saving abandoned brew: %w", err)}
+		}
+		return nil
+	}
+}
+
+// rateBrewCmd returns a command that saves a rating to the brew log, or nil
+// when history is disabled. Failures are reported in the status line.
+func rateBrewCmd(path string, key *historyKey, t time.Time, rating int) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := rateBrew(path, key, t, rating); err != nil {
+			return errMsg{fmt.Errorf("saving brew rating: %w", err)}
+		}
+		return nil
+	}
+}
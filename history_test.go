@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", historyFileName)
+
+	records, err := loadHistory(path, nil)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("Expected empty history for missing file, got %v, %v", records, err)
+	}
+
+	finished := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	want := []brewRecord{
+		{Time: finished, Tea: "Green Tea", Duration: Duration{2 * time.Minute}},
+		{Time: finished.Add(time.Hour), Tea: "Sencha", Duration: Duration{90 * time.Second}, Custom: true},
+	}
+	for _, rec := range want {
+		if err := appendHistory(path, nil, rec); err != nil {
+			t.Fatalf("Failed to append history: %v", err)
+		}
+	}
+
+	records, err = loadHistory(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(records) != len(want) {
+		t.Fatalf("Expected %d records, got %d", len(want), len(records))
+	}
+	for i := range want {
+		if !records[i].Time.Equal(want[i].Time) || records[i].Tea != want[i].Tea ||
+			records[i].Duration != want[i].Duration || records[i].Custom != want[i].Custom {
+			t.Errorf("Record %d: expected %+v, got %+v", i, want[i], records[i])
+		}
+	}
+
+	// A corrupt line is reported with its line number
+	if err := os.WriteFile(path, []byte("{not json}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadHistory(path, nil); err == nil || !contains(err.Error(), ":1:") {
+		t.Errorf("Expected error with line number, got %v", err)
+	}
+}
+
+func TestCustomBrewNamedInHistory(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	mdl := initialModel(config)
+	mdl.customDuration = MinBrewTime
+
+	// Starting a custom brew asks for its name first
+	newModel, _ := mdl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m := newModel.(model)
+	if m.inputKind != inputBrewName || m.isBrewing() {
+		t.Fatal("Expected custom brew to ask for a name before starting")
+	}
+	m.input.SetValue("  Genmaicha ")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	if !m.isBrewing() || m.brewTea != "Genmaicha" {
+		t.Fatalf("Expected brewing Genmaicha, got %q in state %v", m.brewTea, m.state)
+	}
+
+	// Finish the brew and run the commands it returns
+	m.timer = time.Second
+	newModel, cmd := m.Update(tickLeft(m, 0))
+	if !newModel.(model).isFinished() || cmd == nil {
+		t.Fatal("Expected brew to finish with a history command")
+	}
+	for _, msg := range cmdMsgs(cmd) {
+		if err, ok := msg.(errMsg); ok {
+			t.Fatalf("Expected history to be saved, got %v", err.err)
+		}
+	}
+
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected one history record, got %v, %v", records, err)
+	}
+	if rec := records[0]; rec.Tea != "Genmaicha" || !rec.Custom || rec.Duration.Duration != MinBrewTime {
+		t.Errorf("Unexpected history record %+v", rec)
+	}
+
+	// Preset brews start right away under the preset's name
+	m = newModel.(model)
+	m.customDuration = 0
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m = newModel.(model); !m.isBrewing() || m.brewTea != config.Presets[0].Name {
+		t.Errorf("Expected preset brew %q to start, got %q in state %v", config.Presets[0].Name, m.brewTea, m.state)
+	}
+}
+
+func TestBrewNote(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	m := initialModel(config)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyNote)})
+	m = newModel.(model)
+	if m.inputKind != inputNote {
+		t.Fatal("Expected note key to open the note prompt")
+	}
+	m.input.SetValue(" new tin ")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	if m.note != "new tin" || !contains(m.View(), "📝 new tin") {
+		t.Fatalf("Expected note to be shown, got %q", m.note)
+	}
+
+	// The note is saved with the brew and stays on the finished screen
+	m.timer = time.Second
+	newModel, cmd := m.Update(tickLeft(m, 0))
+	cmdMsgs(cmd)
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || records[0].Note != "new tin" {
+		t.Fatalf("Expected the note in the history, got %+v, %v", records, err)
+	}
+	if m = newModel.(model); m.note != "new tin" {
+		t.Error("Expected the note to stay shown on the finished screen")
+	}
+
+	// Starting the next brew leaves the note behind
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m = newModel.(model); m.note != "" {
+		t.Errorf("Expected the next brew to start without the note, got %q", m.note)
+	}
+}
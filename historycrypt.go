@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// historyPassphraseEnv holds the passphrase of an encrypted brew log. It is
+// read from the environment rather than the config file, which may be synced.
+const historyPassphraseEnv = "GOBREW_HISTORY_PASSPHRASE"
+
+// historyFormat marks the first line of an encrypted brew log.
+const historyFormat = "go-brew encrypted history v1"
+
+// historyKDFIterations is how many PBKDF2 rounds turn a passphrase into the
+// key of a new encrypted log. Tests lower it to keep them fast.
+var historyKDFIterations = 600_000
+
+// historyCheck is sealed into the header of an encrypted log, so a wrong
+// passphrase is told apart from a damaged log.
+var historyCheck = []byte("go-brew")
+
+// errHistoryKey is returned when a passphrase or keyfile doesn't open an
+// encrypted brew log.
+var errHistoryKey = errors.New("wrong passphrase or keyfile")
+
+// historyHeader is the first line of an encrypted brew log. Every line after
+// it is one brew, sealed with AES-256-GCM and written in base64.
+type historyHeader struct {
+	Format     string `json:"format"`     // Always historyFormat
+	Iterations int    `json:"iterations"` // PBKDF2-SHA256 rounds deriving the key
+	Salt       []byte `json:"salt"`       // PBKDF2 salt, random per log
+	Check      []byte `json:"check"`      // historyCheck sealed with the key
+}
+
+// parseHistoryHeader reports whether line is the header of an encrypted log.
+func parseHistoryHeader(line []byte) (historyHeader, bool) {
+	var h historyHeader
+	if !bytes.HasPrefix(line, []byte("{")) || json.Unmarshal(line, &h) != nil {
+		return h, false
+	}
+	return h, h.Format == historyFormat
+}
+
+// historyKey is the secret an encrypted brew log is locked with. Keys are
+// derived once per log and kept, as deriving from a passphrase is slow on
+// purpose.
+type historyKey struct {
+	secret     []byte // Passphrase or keyfile contents
+	iterations int    // PBKDF2 rounds for new logs
+
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD // Derived ciphers by salt
+}
+
+// newHistoryKey returns the key for secret. A keyfile is expected to hold
+// random bytes already, so it needs a single round.
+func newHistoryKey(secret []byte, iterations int) *historyKey {
+	return &historyKey{secret: secret, iterations: iterations, aeads: make(map[string]cipher.AEAD)}
+}
+
+// historySecret reads the keyfile set in history.keyfile or, without one,
+// the passphrase in historyPassphraseEnv. It returns nil when neither is
+// set.
+func (c *Config) historySecret(getenv func(string) string) (*historyKey, error) {
+	if c.HistoryKeyfile != "" {
+		data, err := os.ReadFile(c.HistoryKeyfile)
+		if err != nil {
+			return nil, fmt.Errorf("reading history.keyfile: %w", err)
+		}
+		if data = bytes.TrimSpace(data); len(data) == 0 {
+			return nil, fmt.Errorf("history.keyfile %s is empty", c.HistoryKeyfile)
+		}
+		return newHistoryKey(data, 1), nil
+	}
+	if p := getenv(historyPassphraseEnv); p != "" {
+		return newHistoryKey([]byte(p), historyKDFIterations), nil
+	}
+	return nil, nil
+}
+
+// historyKey returns the key new brews are encrypted with, or nil when
+// history.encrypt is off.
+func (c *Config) historyKey(getenv func(string) string) (*historyKey, error) {
+	if !c.HistoryEncrypt {
+		return nil, nil
+	}
+	key, err := c.historySecret(getenv)
+	if err == nil && key == nil {
+		err = fmt.Errorf("history.encrypt is on, but neither history.keyfile nor %s is set", historyPassphraseEnv)
+	}
+	return key, err
+}
+
+// commandHistoryKey resolves the history key from the config files for
+// subcommands that read the brew log but take no config of their own.
+func commandHistoryKey() (*historyKey, error) {
+	config := NewConfig()
+	if err := config.Load(); err != nil {
+		return nil, err
+	}
+	return config.historyKey(os.Getenv)
+}
+
+// newHeader starts a new encrypted log with a fresh salt.
+func (k *historyKey) newHeader() (historyHeader, error) {
+	h := historyHeader{Format: historyFormat, Iterations: k.iterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(h.Salt); err != nil {
+		return h, err
+	}
+	aead, err := k.derive(h)
+	if err != nil {
+		return h, err
+	}
+	h.Check, err = seal(aead, historyCheck)
+	if err != nil {
+		return h, err
+	}
+	k.mu.Lock()
+	k.aeads[string(h.Salt)] = aead
+	k.mu.Unlock()
+	return h, nil
+}
+
+// aead returns the cipher of the log with header h, checking that the key
+// opens it.
+func (k *historyKey) aead(h historyHeader) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if aead, ok := k.aeads[string(h.Salt)]; ok {
+		return aead, nil
+	}
+	aead, err := k.derive(h)
+	if err != nil {
+		return nil, err
+	}
+	if check, err := open(aead, h.Check); err != nil || !bytes.Equal(check, historyCheck) {
+		return nil, errHistoryKey
+	}
+	k.aeads[string(h.Salt)] = aead
+	return aead, nil
+}
+
+// derive turns the secret into the cipher for the salt and rounds of h.
+func (k *historyKey) derive(h historyHeader) (cipher.AEAD, error) {
+	if h.Iterations < 1 {
+		return nil, fmt.Errorf("invalid header: %d iterations", h.Iterations)
+	}
+	key, err := pbkdf2.Key(sha256.New, string(k.secret), h.Salt, h.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data under a random nonce, which is put in front of it.
+func seal(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts data sealed by seal.
+func open(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+// sealRecord encodes rec as one line of an encrypted log.
+func sealRecord(aead cipher.AEAD, rec brewRecord) ([]byte, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := seal(aead, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(base64.StdEncoding.AppendEncode(nil, sealed), '\n'), nil
+}
+
+// openRecord decodes one line of an encrypted log.
+func openRecord(aead cipher.AEAD, line []byte) (brewRecord, error) {
+	var rec brewRecord
+	sealed, err := base64.StdEncoding.DecodeString(string(line))
+	if err != nil {
+		return rec, errors.New("damaged encrypted brew")
+	}
+	data, err := open(aead, sealed)
+	if err != nil {
+		return rec, errors.New("damaged encrypted brew")
+	}
+	return rec, json.Unmarshal(data, &rec)
+}
+
+// convertHistory encrypts the brew log of config right away, or turns it
+// back into plain text, for "go-brew history encrypt" and "decrypt". Either
+// way it needs the passphrase or keyfile, whether or not history.encrypt is
+// on yet.
+func convertHistory(config *Config, encrypt bool, stdout, stderr io.Writer) int {
+	key, err := config.historySecret(os.Getenv)
+	if err == nil && key == nil {
+		err = fmt.Errorf("set history.keyfile or %s to the key of the brew log", historyPassphraseEnv)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	records, err := loadHistory(config.HistoryFile, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	n := fmt.Sprintf("%d %s", len(records), plural(len(records), "brew", "brews"))
+	if !encrypt {
+		if err := writeHistory(config.HistoryFile, nil, records); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Decrypted %s in %s\n", n, config.HistoryFile)
+		if config.HistoryEncrypt {
+			fmt.Fprintln(stdout, "Turn off history.encrypt, or the next brew encrypts the log again")
+		}
+		return 0
+	}
+	if err := writeHistory(config.HistoryFile, key, records); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Encrypted %s in %s\n", n, config.HistoryFile)
+	if !config.HistoryEncrypt {
+		fmt.Fprintln(stdout, "Turn on history.encrypt so new brews can be added to it")
+	}
+	return 0
+}
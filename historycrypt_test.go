@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastHistoryKey returns a passphrase key cheap enough to derive in tests.
+func fastHistoryKey(t *testing.T, passphrase string) *historyKey {
+	t.Helper()
+	iterations := historyKDFIterations
+	t.Cleanup(func() { historyKDFIterations = iterations })
+	historyKDFIterations = 10
+	return newHistoryKey([]byte(passphrase), historyKDFIterations)
+}
+
+func TestEncryptedHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	key := fastHistoryKey(t, "second flush")
+	for i, note := range []string{"smoky", "too bitter"} {
+		rec := brewRecord{Time: now().Add(time.Duration(i) * time.Hour), Tea: "Oolong", Duration: Duration{3 * time.Minute}, Note: note}
+		if err := appendHistory(path, key, rec); err != nil {
+			t.Fatalf("Failed to append an encrypted brew: %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("Oolong")) || bytes.Contains(data, []byte("bitter")) {
+		t.Errorf("Expected the brews to be unreadable on disk, got:\n%s", data)
+	}
+
+	// A new key for the same passphrase derives the key from the header
+	records, err := loadHistory(path, newHistoryKey([]byte("second flush"), 1))
+	if err != nil || len(records) != 2 || records[1].Note != "too bitter" {
+		t.Fatalf("Expected both brews back, got %+v, %v", records, err)
+	}
+	if _, err := loadHistory(path, nil); err == nil || !strings.Contains(err.Error(), "is encrypted") {
+		t.Errorf("Expected reading without a key to explain the log is encrypted, got %v", err)
+	}
+	if _, err := loadHistory(path, newHistoryKey([]byte("first flush"), 10)); !errors.Is(err, errHistoryKey) {
+		t.Errorf("Expected a wrong passphrase to be reported, got %v", err)
+	}
+	if err := appendHistory(path, nil, brewRecord{Time: now(), Tea: "Oolong"}); err == nil {
+		t.Error("Expected a plain brew not to be appended to an encrypted log")
+	}
+}
+
+func TestEncryptPlainHistoryOnAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	if err := writeHistory(path, nil, dailyBrews(3)); err != nil {
+		t.Fatal(err)
+	}
+	key := fastHistoryKey(t, "sencha")
+	if err := appendHistory(path, key, brewRecord{Time: now(), Tea: "Sencha"}); err != nil {
+		t.Fatalf("Failed to append to a plain log: %v", err)
+	}
+	first, err := readFirstLine(path)
+	if _, ok := parseHistoryHeader(first); err != nil || !ok {
+		t.Fatalf("Expected the whole log to be encrypted, got first line %q", first)
+	}
+	if records, err := loadHistory(path, key); err != nil || len(records) != 4 {
+		t.Errorf("Expected the 3 plain brews and the new one, got %d, %v", len(records), err)
+	}
+}
+
+func TestMergeEncryptedHistory(t *testing.T) {
+	key := fastHistoryKey(t, "genmaicha")
+	brews := dailyBrews(3)
+	encode := func(records []brewRecord) []byte {
+		t.Helper()
+		h, err := key.newHeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := encodeHistory(records, key, h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	local, remote := encode(brews[:2]), encode(brews[1:])
+
+	merged, err := mergeHistory(local, remote, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records, err := parseHistory(merged, "merged", key); err != nil || !sameRecords(records, brews) {
+		t.Errorf("Expected the union of both logs, got %+v, %v", records, err)
+	}
+	again, err := mergeHistory(merged, remote, key)
+	if err != nil || !bytes.Equal(again, merged) {
+		t.Error("Expected a log that holds every brew to be kept as it is")
+	}
+	if _, err := mergeHistory(local, remote, nil); err == nil {
+		t.Error("Expected merging encrypted logs without a key to fail")
+	}
+}
+
+func TestHistoryEncryptCommand(t *testing.T) {
+	fastHistoryKey(t, "")
+	t.Setenv(historyPassphraseEnv, "dragonwell")
+	path := filepath.Join(t.TempDir(), historyFileName)
+	if err := writeHistory(path, nil, dailyBrews(2)); err != nil {
+		t.Fatal(err)
+	}
+	config := writeConfig(t, "[history]\nencrypt = true\n")
+	var stdout, stderr bytes.Buffer
+
+	if code := runHistoryCommand([]string{"encrypt", "-config", config, "-history", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Encrypted 2 brews") {
+		t.Errorf("Expected the encrypted brews to be counted, got %q", stdout.String())
+	}
+	if _, err := loadHistory(path, nil); err == nil {
+		t.Error("Expected the log to be encrypted")
+	}
+
+	stdout.Reset()
+	if code := runHistoryCommand([]string{"decrypt", "-config", config, "-history", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if records, err := loadHistory(path, nil); err != nil || len(records) != 2 {
+		t.Errorf("Expected a plain log with both brews, got %d, %v", len(records), err)
+	}
+	if !strings.Contains(stdout.String(), "Turn off history.encrypt") {
+		t.Errorf("Expected a reminder to turn off history.encrypt, got %q", stdout.String())
+	}
+
+	t.Setenv(historyPassphraseEnv, "")
+	if code := runHistoryCommand([]string{"encrypt", "-config", config, "-history", path}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 without a passphrase, got %d", code)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyHistorySearch opens the filter prompt on the stats screen.
+const KeyHistorySearch = "/"
+
+// historyFilter selects brew log records by text and date.
+type historyFilter struct {
+	words    []string  // Lowercase words that must all appear in the tea name or note
+	from, to time.Time // First and last day included, zero for no limit
+}
+
+// parseHistoryFilter parses a filter such as "sencha from:2024-02-01
+// to:2024-02-29". Plain words match the tea name and note, ignoring case;
+// from: and to: limit the dates, both days included. Dates are read in loc.
+func parseHistoryFilter(query string, loc *time.Location) (historyFilter, error) {
+	var f historyFilter
+	for _, field := range strings.Fields(query) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || (key != "from" && key != "to") {
+			f.words = append(f.words, strings.ToLower(field))
+			continue
+		}
+		day, err := time.ParseInLocation(time.DateOnly, value, loc)
+		if err != nil {
+			return historyFilter{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", value)
+		}
+		if key == "from" {
+			f.from = day
+		} else {
+			f.to = day
+		}
+	}
+	return f, nil
+}
+
+// match reports whether rec passes the filter.
+func (f historyFilter) match(rec brewRecord) bool {
+	if !f.from.IsZero() || !f.to.IsZero() {
+		// Dates in YYYY-MM-DD form compare in calendar order
+		day := rec.Time.In(f.location()).Format(time.DateOnly)
+		if !f.from.IsZero() && day < f.from.Format(time.DateOnly) {
+			return false
+		}
+		if !f.to.IsZero() && day > f.to.Format(time.DateOnly) {
+			return false
+		}
+	}
+	text := strings.ToLower(rec.Tea + " " + rec.Note)
+	for _, word := range f.words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// location returns the time zone the filter's dates were given in.
+func (f historyFilter) location() *time.Location {
+	if !f.from.IsZero() {
+		return f.from.Location()
+	}
+	return f.to.Location()
+}
+
+// filteredHistory returns the records of the brew log that match the
+// current filter, oldest first.
+func (m model) filteredHistory() []brewRecord {
+	if m.historyQuery == "" {
+		return m.history
+	}
+	f, err := parseHistoryFilter(m.historyQuery, m.clock.Location())
+	if err != nil {
+		return m.history // Invalid filters are rejected when typed
+	}
+	var matches []brewRecord
+	for _, rec := range m.history {
+		if f.match(rec) {
+			matches = append(matches, rec)
+		}
+	}
+	return matches
+}
+
+// openHistorySearch asks for a filter for the stats screen, starting from
+// the current one.
+func (m model) openHistorySearch() (model, tea.Cmd) {
+	m, cmd := m.openInput(inputHistoryFilter, "Filter: ", "sencha from:2024-02-01 to:2024-02-29")
+	m.input.Width = 40
+	m.input.CharLimit = 80
+	m.input.SetValue(m.historyQuery)
+	m.input.CursorEnd()
+	return m, cmd
+}
+
+// setHistoryFilter applies a filter typed on the stats screen.
+func (m model) setHistoryFilter(query string) (model, tea.Cmd) {
+	query = strings.TrimSpace(query)
+	if _, err := parseHistoryFilter(query, m.clock.Location()); err != nil {
+		return m.showStatus(err.Error())
+	}
+	m = m.closeInput()
+	m.historyQuery = query
+	return m, nil
+}
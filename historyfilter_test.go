@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistoryFilter(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d.Add(12 * time.Hour)
+	}
+	records := []brewRecord{
+		{Time: day("2024-02-01"), Tea: "Sencha", Note: "new tin"},
+		{Time: day("2024-02-10"), Tea: "Sencha"},
+		{Time: day("2024-02-20"), Tea: "Oolong", Note: "second flush from the new tin"},
+		{Time: day("2024-03-01"), Tea: "Assam"},
+	}
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 4},
+		{"SENCHA", 2},
+		{"new tin", 2},
+		{"sencha tin", 1},
+		{"from:2024-02-10", 3},
+		{"to:2024-02-10", 2},
+		{"from:2024-02-05 to:2024-02-25 tin", 1},
+	}
+	for _, tt := range tests {
+		f, err := parseHistoryFilter(tt.query, time.UTC)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tt.query, err)
+		}
+		got := 0
+		for _, rec := range records {
+			if f.match(rec) {
+				got++
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Expected %d matches for %q, got %d", tt.want, tt.query, got)
+		}
+	}
+
+	if _, err := parseHistoryFilter("from:yesterday", time.UTC); err == nil {
+		t.Error("Expected an invalid date to be rejected")
+	}
+}
+
+func TestStatsScreenFilter(t *testing.T) {
+	config := NewConfig()
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	for _, rec := range []brewRecord{{Tea: "Sencha", Note: "new tin"}, {Tea: "Sencha"}, {Tea: "Oolong"}} {
+		rec.Time = now()
+		if err := appendHistory(config.HistoryFile, nil, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := initialModel(config)
+	send := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := m.Update(msg)
+		m = newModel.(model)
+		return cmd
+	}
+	send(send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStats)})())
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyHistorySearch)})
+	if m.inputKind != inputHistoryFilter {
+		t.Fatal("Expected / to open the filter prompt")
+	}
+	m.input.SetValue("from:soon")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.inputKind != inputHistoryFilter || m.historyQuery != "" {
+		t.Fatal("Expected an invalid filter to keep the prompt open")
+	}
+	m.input.SetValue("sencha")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.historyQuery != "sencha" || len(m.filteredHistory()) != 2 {
+		t.Fatalf("Expected two Sencha brews, got %d", len(m.filteredHistory()))
+	}
+	view := m.View()
+	for _, want := range []string{"Filter: sencha (2 of 3 brews)", "Latest matches:", "📝 new tin"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the filtered stats to contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Oolong") {
+		t.Error("Expected Oolong to be filtered out")
+	}
+}
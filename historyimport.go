@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats "go-brew history import" reads.
+const (
+	ImportCSV       = "csv"       // Any CSV file with a header row
+	ImportSteepster = "steepster" // A Steepster-style tasting note export
+)
+
+// importFields are the brew fields a CSV column can be mapped to.
+var importFields = []string{"time", "tea", "duration", "note", "rating"}
+
+// importGuesses are the column names each field is looked for under when
+// no mapping is given, compared regardless of case.
+var importGuesses = map[string][]string{
+	"time":     {"time", "date", "datetime", "timestamp", "finished", "brewed"},
+	"tea":      {"tea", "tea name", "name"},
+	"duration": {"duration", "steep time", "steep", "brew time", "length"},
+	"note":     {"note", "notes", "tasting note", "comment", "comments"},
+	"rating":   {"rating", "score", "stars"},
+}
+
+// steepsterMapping is how a Steepster-style export names its columns. Those
+// present are used unless -map says otherwise.
+var steepsterMapping = map[string]string{
+	"time":     "Date",
+	"tea":      "Tea Name",
+	"duration": "Steep Time",
+	"note":     "Tasting Note",
+	"rating":   "Rating",
+}
+
+// importTimeLayouts are the date formats tried for the time column when no
+// -time-format is given.
+var importTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	time.DateOnly,
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// historyImporter turns the rows of an export into brew records.
+type historyImporter struct {
+	Columns     map[string]int // Column of each mapped field
+	TimeFormat  string         // Layout of the time column, "" to try importTimeLayouts
+	RatingScale int            // Best rating in the export, converted to 1-maxRating
+	Duration    time.Duration  // Duration of brews without one, 0 to skip them
+	Presets     []TeaPreset    // Presets whose durations fill in missing ones by tea name
+}
+
+// parseImportMapping reads a -map value such as "tea=Tea Name,time=Date"
+// into the column name of each field.
+func parseImportMapping(spec string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("%q: map fields as field=column, e.g. tea=Tea Name", pair)
+		}
+		known := false
+		for _, f := range importFields {
+			known = known || f == field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q (use %s)", field, strings.Join(importFields, ", "))
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
+
+// resolveColumns finds the column of each field in header: the column named
+// in mapping if there is one, otherwise the first column with a name from
+// importGuesses. Time and tea are required.
+func resolveColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	columns := make(map[string]int)
+	for _, field := range importFields {
+		if name, ok := mapping[field]; ok {
+			i := find(name)
+			if i < 0 {
+				return nil, fmt.Errorf("there is no column %q for %s", name, field)
+			}
+			columns[field] = i
+			continue
+		}
+		for _, name := range importGuesses[field] {
+			if i := find(name); i >= 0 {
+				columns[field] = i
+				break
+			}
+		}
+	}
+	for _, field := range []string{"time", "tea"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("no %s column found; name it with -map %s=column", field, field)
+		}
+	}
+	return columns, nil
+}
+
+// readImportCSV reads the rows of a CSV export. A byte order mark is
+// skipped, and files that separate columns with semicolons, as spreadsheet
+// apps do in many locales, are read as well.
+func readImportCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	r := csv.NewReader(bytes.NewReader(data))
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	return rows, nil
+}
+
+// field returns the value of a mapped field in row, or "" if the field is
+// not mapped or the row is short.
+func (imp historyImporter) field(row []string, name string) string {
+	i, ok := imp.Columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// record turns one row into a brew.
+func (imp historyImporter) record(row []string) (brewRecord, error) {
+	var rec brewRecord
+	t, err := imp.parseTime(imp.field(row, "time"))
+	if err != nil {
+		return rec, err
+	}
+	rec.Time = t
+	if rec.Tea = imp.field(row, "tea"); rec.Tea == "" {
+		return rec, fmt.Errorf("no tea name")
+	}
+	rec.Note = imp.field(row, "note")
+
+	if s := imp.field(row, "duration"); s != "" {
+		d, err := parseImportDuration(s)
+		if err != nil {
+			return rec, err
+		}
+		rec.Duration = Duration{d}
+	} else if i := presetByName(imp.Presets, rec.Tea); i >= 0 {
+		rec.Duration = Duration{imp.Presets[i].Duration}
+	} else if imp.Duration > 0 {
+		rec.Duration = Duration{imp.Duration}
+	} else {
+		return rec, fmt.Errorf("no duration for %s; map a duration column or pass -duration", rec.Tea)
+	}
+
+	if s := imp.field(row, "rating"); s != "" {
+		r, err := strconv.ParseFloat(s, 64)
+		if err != nil || r < 0 || r > float64(imp.RatingScale) {
+			return rec, fmt.Errorf("rating %q is not a number from 0 to %d", s, imp.RatingScale)
+		}
+		// A rating of 0 in most exports means "not rated"
+		if r > 0 {
+			rec.Rating = max(1, int(math.Round(r*maxRating/float64(imp.RatingScale))))
+		}
+	}
+	return rec, nil
+}
+
+// parseTime reads the time column in the local time zone, as exports
+// rarely say which zone they mean.
+func (imp historyImporter) parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("no time")
+	}
+	layouts := importTimeLayouts
+	if imp.TimeFormat != "" {
+		layouts = []string{imp.TimeFormat}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("time %q is not in a known format; pass -time-format", s)
+}
+
+// parseImportDuration reads a steep time written as a Go duration ("3m"),
+// as minutes and seconds ("3:00") or as a number of seconds ("180").
+func parseImportDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	if mins, secs, ok := strings.Cut(s, ":"); ok {
+		m, errM := strconv.Atoi(mins)
+		sc, errS := strconv.Atoi(secs)
+		if errM == nil && errS == nil && m >= 0 && sc >= 0 && sc < 60 && m+sc > 0 {
+			return time.Duration(m)*time.Minute + time.Duration(sc)*time.Second, nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return time.Duration(n) * time.Second, nil
+	}
+	return 0, fmt.Errorf("duration %q is not like 3m, 3:00 or 180", s)
+}
+
+// importRecords reads an export's rows into brews, oldest first. Rows that
+// can't be read are skipped and described in the returned problems.
+func (imp historyImporter) importRecords(rows [][]string) (records []brewRecord, problems []string) {
+	for i, row := range rows {
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		rec, err := imp.record(row)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("row %d: %v", i+2, err))
+			continue
+		}
+		records = append(records, rec)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, problems
+}
+
+// runHistoryImport implements "go-brew history import" and returns the
+// process exit code:
+//
+//	go-brew history import [-format csv|steepster] [-map field=column,...] [-dry-run] file
+//
+// Brews already in the history, told apart by finish time and tea as when
+// syncing, are not added twice.
+func runHistoryImport(args []string, stdout, stderr io.Writer) int {
+	const usage = "usage: go-brew history import [-format csv|steepster] [-map field=column,...] [-time-format layout] [-rating-scale n] [-duration d] [-dry-run] [-history file] [-config file] export.csv"
+	fs := flag.NewFlagSet("history import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to read")
+	fs.StringVar(&config.HistoryFile, "history", config.HistoryFile, "brew history `file` to import into")
+	format := fs.String("format", ImportCSV, "export `format`: csv or steepster")
+	mapSpec := fs.String("map", "", "columns of each field, e.g. \"tea=Tea Name,time=Date\" (fields: time, tea, duration, note, rating)")
+	timeFormat := fs.String("time-format", "", "Go time `layout` of the time column, e.g. \"02.01.2006\" (default: common formats)")
+	ratingScale := fs.Int("rating-scale", 0, "best rating in the export, `n` (default 5, or 100 for steepster)")
+	duration := fs.Duration("duration", 0, "steep time of brews without one that match no preset")
+	dryRun := fs.Bool("dry-run", false, "show what would be imported without changing the history")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+
+	mapping, err := parseImportMapping(*mapSpec)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: -map %v\n", err)
+		return 2
+	}
+	scale := 5
+	switch *format {
+	case ImportCSV:
+	case ImportSteepster:
+		scale = 100
+	default:
+		fmt.Fprintf(stderr, "unknown format %q\n%s\n", *format, usage)
+		return 2
+	}
+	if config.setFlags["rating-scale"] {
+		scale = *ratingScale
+	}
+	if scale < 1 {
+		fmt.Fprintln(stderr, "error: -rating-scale must be at least 1")
+		return 2
+	}
+
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	key, err := config.historyKey(os.Getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	rows, err := readImportCSV(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	if *format == ImportSteepster {
+		for field, column := range steepsterMapping {
+			if _, ok := mapping[field]; !ok && slices.ContainsFunc(rows[0], func(h string) bool { return strings.EqualFold(strings.TrimSpace(h), column) }) {
+				mapping[field] = column
+			}
+		}
+	}
+	columns, err := resolveColumns(rows[0], mapping)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	// The columns used are shown, so a wrong guess can be corrected with -map
+	for _, field := range importFields {
+		if i, ok := columns[field]; ok {
+			fmt.Fprintf(stdout, "%-8s <- %q\n", field, rows[0][i])
+		}
+	}
+
+	imp := historyImporter{Columns: columns, TimeFormat: *timeFormat, RatingScale: scale, Duration: *duration, Presets: config.Presets}
+	imported, problems := imp.importRecords(rows[1:])
+	for _, p := range problems {
+		fmt.Fprintf(stderr, "skipped %s\n", p)
+	}
+
+	existing, err := loadHistory(config.HistoryFile, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	seen := make(map[string]bool)
+	for _, rec := range existing {
+		seen[brewID(rec)] = true
+	}
+	var added []brewRecord
+	for _, rec := range imported {
+		if id := brewID(rec); !seen[id] {
+			seen[id] = true
+			added = append(added, rec)
+		}
+	}
+	summary := fmt.Sprintf("%d %s", len(added), plural(len(added), "brew", "brews"))
+	if dup := len(imported) - len(added); dup > 0 {
+		summary += fmt.Sprintf(" (%d already in the history)", dup)
+	}
+	if *dryRun || len(added) == 0 {
+		verb := "Would import"
+		if !*dryRun {
+			verb = "Imported"
+		}
+		fmt.Fprintf(stdout, "%s %s into %s\n", verb, summary, config.HistoryFile)
+		return 0
+	}
+
+	records := append(existing, added...)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	if err := writeHistory(config.HistoryFile, key, records); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Imported %s into %s\n", summary, config.HistoryFile)
+	return 0
+}
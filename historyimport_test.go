@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseImportDuration(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+	}{
+		{"3m", 3 * time.Minute},
+		{"2m30s", 150 * time.Second},
+		{"3:00", 3 * time.Minute},
+		{"0:45", 45 * time.Second},
+		{"180", 3 * time.Minute},
+		{"", 0},
+		{"0", 0},
+		{"3:75", 0},
+		{"long", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseImportDuration(tt.s)
+		if (err != nil) != (tt.want == 0) || got != tt.want {
+			t.Errorf("parseImportDuration(%q): expected %v, got %v, %v", tt.s, tt.want, got, err)
+		}
+	}
+}
+
+func TestParseImportMapping(t *testing.T) {
+	mapping, err := parseImportMapping("Tea=Tea Name, time = Logged at")
+	if err != nil || mapping["tea"] != "Tea Name" || mapping["time"] != "Logged at" {
+		t.Errorf("Expected the columns of tea and time, got %v, %v", mapping, err)
+	}
+	for _, spec := range []string{"tea", "tea=", "caffeine=Caffeine"} {
+		if _, err := parseImportMapping(spec); err == nil {
+			t.Errorf("Expected -map %q to be rejected", spec)
+		}
+	}
+}
+
+// runImport writes export to a file and imports it into history.
+func runImport(t *testing.T, history, export string, args ...string) (string, int) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	if err := os.WriteFile(path, []byte(export), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	args = append(args, "-config", writeConfig(t, ""), "-history", history, path)
+	code := runHistoryImport(args, &stdout, &stderr)
+	return stdout.String() + stderr.String(), code
+}
+
+func TestImportCSV(t *testing.T) {
+	history := filepath.Join(t.TempDir(), historyFileName)
+	export := "Brewed;Tea;Steep;Notes;Score\n" +
+		"2024-02-27 08:15;Sencha;1:30;grassy;4\n" +
+		"2024-02-26 19:00;Earl Grey;240;;5\n" +
+		"yesterday;Sencha;1:30;;3\n"
+
+	out, code := runImport(t, history, export, "-dry-run")
+	if code != 0 || !strings.Contains(out, "Would import 2 brews") || !strings.Contains(out, "skipped row 4") {
+		t.Fatalf("Expected a dry run of 2 brews with row 4 skipped, got %d:\n%s", code, out)
+	}
+	if records, _ := loadHistory(history, nil); len(records) != 0 {
+		t.Fatal("Expected the dry run not to write the history")
+	}
+
+	if out, code = runImport(t, history, export); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d:\n%s", code, out)
+	}
+	records, err := loadHistory(history, nil)
+	if err != nil || len(records) != 2 {
+		t.Fatalf("Expected 2 imported brews, got %+v, %v", records, err)
+	}
+	if r := records[1]; r.Tea != "Sencha" || r.Duration.Duration != 90*time.Second || r.Note != "grassy" || r.Rating != 4 {
+		t.Errorf("Expected the Sencha row in full, got %+v", r)
+	}
+	if records[0].Tea != "Earl Grey" || records[0].Duration.Duration != 4*time.Minute {
+		t.Errorf("Expected the brews oldest first, got %+v", records[0])
+	}
+
+	if out, _ = runImport(t, history, export); !strings.Contains(out, "Imported 0 brews (2 already in the history)") {
+		t.Errorf("Expected a second import to add nothing, got:\n%s", out)
+	}
+}
+
+func TestImportSteepster(t *testing.T) {
+	history := filepath.Join(t.TempDir(), historyFileName)
+	export := "Tea Name,Company,Date,Rating,Tasting Note\n" +
+		"Green Tea,Yunnan Sourcing,\"March 1, 2024\",85,\"Sweet, a little nutty\"\n" +
+		"Oriental Beauty,Eco-Cha,\"February 29, 2024\",0,\n"
+
+	out, code := runImport(t, history, export, "-format", "steepster")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d:\n%s", code, out)
+	}
+	if !strings.Contains(out, "skipped row 3: no duration for Oriental Beauty") {
+		t.Errorf("Expected the brew without a duration to be skipped, got:\n%s", out)
+	}
+	records, err := loadHistory(history, nil)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 imported brew, got %+v, %v", records, err)
+	}
+	if r := records[0]; r.Duration.Duration != 2*time.Minute || r.Rating != 4 || r.Note != "Sweet, a little nutty" {
+		t.Errorf("Expected the preset's duration and 85/100 as 4/5, got %+v", r)
+	}
+
+	out, code = runImport(t, history, export, "-format", "steepster", "-duration", "3m", "-map", "tea=Company")
+	if code != 0 || !strings.Contains(out, "Imported 2 brews") {
+		t.Errorf("Expected -map and -duration to import both rows by company, got %d:\n%s", code, out)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqliteSchema creates the brews and events tables of an SQLite brew log.
+// Brews are kept in the order they were added, as in a JSON Lines log, and
+// indexed by time and tea for queries run on the database directly. Events
+// are indexed by the brew they belong to.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS brews (
+	id TEXT NOT NULL DEFAULT '',
+	time TEXT NOT NULL,
+	tea TEXT NOT NULL,
+	duration INTEGER NOT NULL,
+	custom INTEGER NOT NULL DEFAULT 0,
+	note TEXT NOT NULL DEFAULT '',
+	infusion INTEGER NOT NULL DEFAULT 0,
+	rating INTEGER NOT NULL DEFAULT 0,
+	blind INTEGER NOT NULL DEFAULT 0,
+	abandoned INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS brews_time ON brews (time);
+CREATE INDEX IF NOT EXISTS brews_tea ON brews (tea);
+CREATE TABLE IF NOT EXISTS events (
+	id TEXT PRIMARY KEY,
+	brew TEXT NOT NULL,
+	type TEXT NOT NULL,
+	time TEXT NOT NULL,
+	tea TEXT NOT NULL,
+	duration INTEGER NOT NULL,
+	remaining INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS events_brew ON events (brew);
+`
+
+// sqliteColumns are the columns of the brews table in the order Load reads
+// them.
+const sqliteColumns = "id, time, tea, duration, custom, note, infusion, rating, blind, abandoned"
+
+// sqliteStore is a brew log kept in an SQLite database. It is read and
+// written with the sqlite3 command, as sync uses git, so go-brew needs no
+// cgo and stays small for everyone who keeps the JSON Lines log.
+type sqliteStore struct {
+	path string
+	loc  *time.Location // Time zone sqlite3 works in, the local one if nil
+}
+
+// Load reads every brew from the database, oldest first.
+func (s sqliteStore) Load() ([]brewRecord, error) {
+	if _, err := os.Stat(s.path); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	out, err := s.run(sqliteSchema+"SELECT "+sqliteColumns+" FROM brews ORDER BY rowid;\n", "-csv", "-noheader")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	records := make([]brewRecord, 0, len(rows))
+	for i, row := range rows {
+		rec, err := sqliteRecord(row)
+		if err != nil {
+			return nil, fmt.Errorf("%s: brew %d: %w", s.path, i+1, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Append adds a brew to the database, creating it if needed.
+func (s sqliteStore) Append(rec brewRecord) error {
+	_, err := s.run(sqliteSchema + sqliteInsert(rec))
+	return err
+}
+
+// Replace swaps every brew in the database for records in one transaction.
+func (s sqliteStore) Replace(records []brewRecord) error {
+	var script strings.Builder
+	script.WriteString(sqliteSchema + "BEGIN;\nDELETE FROM brews;\n")
+	for _, rec := range records {
+		script.WriteString(sqliteInsert(rec))
+	}
+	script.WriteString("COMMIT;\n")
+	_, err := s.run(script.String())
+	return err
+}
+
+// AppendEvent adds ev to the events table. An event already there, as when
+// a post is retried, is left as it is.
+func (s sqliteStore) AppendEvent(ev brewEvent) error {
+	_, err := s.run(sqliteSchema + fmt.Sprintf("INSERT OR IGNORE INTO events (id, brew, type, time, tea, duration, remaining) VALUES (%s, %s, %s, %s, %s, %d, %d);\n",
+		sqliteQuote(ev.ID), sqliteQuote(ev.Brew), sqliteQuote(ev.Type), sqliteQuote(ev.Time.Format(time.RFC3339Nano)),
+		sqliteQuote(ev.Tea), int64(ev.Duration.Duration), int64(ev.Remaining.Duration)))
+	return err
+}
+
+// run feeds script to sqlite3 on the database and returns what it printed.
+// A database it creates is only readable by the user, like the JSON Lines
+// log.
+func (s sqliteStore) run(script string, args ...string) ([]byte, error) {
+	if err := ensureDir(filepath.Dir(s.path)); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	cmd := exec.Command("sqlite3", append(append([]string{"-batch", "-bail"}, args...), s.path)...)
+	cmd.Stdin = strings.NewReader(script)
+	if s.loc != nil && s.loc != time.Local {
+		cmd.Env = append(os.Environ(), "TZ="+s.loc.String())
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%s: an SQLite brew log needs the sqlite3 command", s.path)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", s.path, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return out, nil
+}
+
+// Summarize works out the stats screen's figures as of today with indexed
+// queries, counting days and hours in today's time zone.
+func (s sqliteStore) Summarize(today time.Time) (historyStats, error) {
+	stats := historyStats{PerDay: make([]int, statsDays)}
+	if _, err := os.Stat(s.path); errors.Is(err, fs.ErrNotExist) {
+		return stats, nil
+	}
+	days := make(map[string]int, statsDays)
+	for i := range statsDays {
+		days[today.AddDate(0, 0, i+1-statsDays).Format(time.DateOnly)] = i
+	}
+	// Times are stored with the offset they were recorded at, so the index
+	// narrows the brews down to the days with one to spare and date()
+	// sorts out the rest
+	since := today.AddDate(0, 0, -statsDays).Format(time.DateOnly)
+	script := sqliteSchema + fmt.Sprintf(`SELECT 'total', count(*), count(DISTINCT tea) FROM brews;
+SELECT 'day', date(time, 'localtime'), count(*) FROM brews WHERE time >= %s GROUP BY 2;
+SELECT 'tea', tea, count(*) FROM brews GROUP BY tea ORDER BY count(*) DESC, tea LIMIT %d;
+SELECT 'hour', CAST(strftime('%%H', time, 'localtime') AS INTEGER), count(*) FROM brews GROUP BY 2;
+`, sqliteQuote(since), statsTopTeas)
+	s.loc = today.Location()
+	out, err := s.run(script, "-csv", "-noheader")
+	if err != nil {
+		return stats, err
+	}
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		return stats, fmt.Errorf("%s: %w", s.path, err)
+	}
+	for _, row := range rows {
+		if len(row) != 3 {
+			return stats, fmt.Errorf("%s: unexpected stats row %q", s.path, row)
+		}
+		n, err := strconv.Atoi(row[2])
+		if err != nil {
+			return stats, fmt.Errorf("%s: %w", s.path, err)
+		}
+		switch row[0] {
+		case "total":
+			stats.Total, _ = strconv.Atoi(row[1])
+			stats.MoreTeas = max(0, n-statsTopTeas)
+		case "day":
+			if i, ok := days[row[1]]; ok {
+				stats.PerDay[i] = n
+			}
+		case "tea":
+			stats.Teas = append(stats.Teas, row[1])
+			stats.TeaCounts = append(stats.TeaCounts, n)
+		case "hour":
+			if hour, err := strconv.Atoi(row[1]); err == nil && hour >= 0 && hour < len(stats.PerHour) {
+				stats.PerHour[hour] = n
+			}
+		}
+	}
+	return stats, nil
+}
+
+// sqliteInsert returns the statement adding rec to the brews table.
+func sqliteInsert(rec brewRecord) string {
+	return fmt.Sprintf("INSERT INTO brews (%s) VALUES (%s, %s, %s, %d, %d, %s, %d, %d, %d, %d);\n", sqliteColumns,
+		sqliteQuote(rec.ID), sqliteQuote(rec.Time.Format(time.RFC3339Nano)), sqliteQuote(rec.Tea), int64(rec.Duration.Duration),
+		sqliteBool(rec.Custom), sqliteQuote(rec.Note), rec.Infusion, rec.Rating, sqliteBool(rec.Blind), sqliteBool(rec.Abandoned))
+}
+
+// sqliteQuote returns s as an SQL string literal.
+func sqliteQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqliteBool returns b as SQLite stores booleans.
+func sqliteBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sqliteRecord decodes a row of sqliteColumns printed by sqlite3 -csv.
+func sqliteRecord(row []string) (brewRecord, error) {
+	if len(row) != 10 {
+		return brewRecord{}, fmt.Errorf("expected 10 columns, got %d", len(row))
+	}
+	rec := brewRecord{ID: row[0]}
+	var err error
+	if rec.Time, err = time.Parse(time.RFC3339Nano, row[1]); err != nil {
+		return rec, err
+	}
+	rec.Tea, rec.Note = row[2], row[5]
+	var ints [6]int64
+	for i, col := range []int{3, 4, 6, 7, 8, 9} {
+		if ints[i], err = strconv.ParseInt(row[col], 10, 64); err != nil {
+			return rec, fmt.Errorf("column %d: %w", col+1, err)
+		}
+	}
+	rec.Duration = Duration{time.Duration(ints[0])}
+	rec.Custom = ints[1] != 0
+	rec.Infusion = int(ints[2])
+	rec.Rating = int(ints[3])
+	rec.Blind = ints[4] != 0
+	rec.Abandoned = ints[5] != 0
+	return rec, nil
+}
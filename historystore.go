@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HistoryStore keeps the brew log. Everything that reads or writes brews goes
+// through one, so the log can live in another format without changing them.
+type HistoryStore interface {
+	// Load returns every brew, oldest first; a log that doesn't exist yet
+	// has none
+	Load() ([]brewRecord, error)
+	// Append adds a brew after the others, creating the log if needed
+	Append(rec brewRecord) error
+	// Replace rewrites the log with records in one go, so a failed write
+	// leaves it as it was
+	Replace(records []brewRecord) error
+}
+
+// historySummarizer is implemented by stores that can work out the stats
+// screen's figures without reading every brew, which keeps the screen quick
+// for a log of tens of thousands.
+type historySummarizer interface {
+	Summarize(today time.Time) (historyStats, error)
+}
+
+// sqliteExtensions are the history file extensions that select an SQLite
+// database instead of a JSON Lines file.
+var sqliteExtensions = []string{".db", ".sqlite", ".sqlite3"}
+
+// isSQLiteHistory reports whether the brew log at path is an SQLite
+// database, going by its extension.
+func isSQLiteHistory(path string) bool {
+	return slices.Contains(sqliteExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// openHistory returns the store for the brew log at path: an SQLite
+// database for the sqliteExtensions, otherwise a JSON Lines file encrypted
+// with key unless it is nil. Databases aren't encrypted.
+func openHistory(path string, key *historyKey) (HistoryStore, error) {
+	if !isSQLiteHistory(path) {
+		return jsonlStore{path, key}, nil
+	}
+	if key != nil {
+		return nil, fmt.Errorf("%s: history.encrypt needs a .jsonl brew log, not a database", path)
+	}
+	return sqliteStore{path: path}, nil
+}
+
+// loadHistory reads every record from the brew log at path, oldest first.
+// A missing log is not an error and yields no records. An encrypted log
+// needs its key; a plain one is read with or without.
+func loadHistory(path string, key *historyKey) ([]brewRecord, error) {
+	store, err := openHistory(path, key)
+	if err != nil {
+		return nil, err
+	}
+	return store.Load()
+}
+
+// appendHistory adds a record to the brew log at path, creating it and its
+// directory if needed.
+func appendHistory(path string, key *historyKey, rec brewRecord) error {
+	store, err := openHistory(path, key)
+	if err != nil {
+		return err
+	}
+	return store.Append(rec)
+}
+
+// writeHistory replaces the brew log at path with records, encrypted with
+// key unless it is nil. The log is written in one go, so a failed write
+// leaves it as it was.
+func writeHistory(path string, key *historyKey, records []brewRecord) error {
+	store, err := openHistory(path, key)
+	if err != nil {
+		return err
+	}
+	return store.Replace(records)
+}
+
+// readHistoryLog returns the brew log at path as JSON Lines, the format it
+// is synced in, or nil if there is none yet. A JSON Lines log is returned
+// as stored, encrypted or not.
+func readHistoryLog(path string) ([]byte, error) {
+	if !isSQLiteHistory(path) {
+		return readOptional(path)
+	}
+	records, err := loadHistory(path, nil)
+	if err != nil || records == nil {
+		return nil, err
+	}
+	return encodeHistory(records, nil, historyHeader{})
+}
+
+// writeHistoryLog replaces the brew log at path with data in JSON Lines, as
+// read by readHistoryLog.
+func writeHistoryLog(path string, data []byte) error {
+	if !isSQLiteHistory(path) {
+		return writeFileAtomic(path, data)
+	}
+	records, err := parseHistory(data, path, nil)
+	if err != nil {
+		return err
+	}
+	return writeHistory(path, nil, records)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryStores(t *testing.T) {
+	brews := []brewRecord{
+		{Time: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), Tea: "Green Tea", Duration: Duration{2 * time.Minute}, Rating: 4},
+		{Time: time.Date(2024, 3, 1, 10, 30, 15, 500, time.FixedZone("CET", 3600)), Tea: "Bob's \"Chai\", spiced", Duration: Duration{5 * time.Minute},
+			Custom: true, Note: "with milk,\nthen honey", Infusion: 2, Blind: true},
+	}
+	for _, name := range []string{"history.jsonl", "history.db"} {
+		t.Run(name, func(t *testing.T) {
+			if isSQLiteHistory(name) {
+				if _, err := exec.LookPath("sqlite3"); err != nil {
+					t.Skip("sqlite3 is not installed")
+				}
+			}
+			path := filepath.Join(t.TempDir(), "data", name)
+			store, err := openHistory(path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if records, err := store.Load(); err != nil || records != nil {
+				t.Fatalf("Expected a missing log to hold no brews, got %v, %v", records, err)
+			}
+			for _, rec := range brews {
+				if err := store.Append(rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			records, err := store.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != len(brews) {
+				t.Fatalf("Expected %d brews, got %v", len(brews), records)
+			}
+			for i, rec := range records {
+				if !rec.Time.Equal(brews[i].Time) {
+					t.Errorf("Expected brew %d at %v, got %v", i, brews[i].Time, rec.Time)
+				}
+				rec.Time = brews[i].Time
+				if !reflect.DeepEqual(rec, brews[i]) {
+					t.Errorf("Expected brew %+v, got %+v", brews[i], rec)
+				}
+			}
+
+			if err := store.Replace(brews[1:]); err != nil {
+				t.Fatal(err)
+			}
+			if records, err := store.Load(); err != nil || len(records) != 1 || records[0].Tea != brews[1].Tea {
+				t.Errorf("Expected only the chai after replacing, got %v, %v", records, err)
+			}
+		})
+	}
+}
+
+func TestOpenHistory(t *testing.T) {
+	for path, sqlite := range map[string]bool{"history.jsonl": false, "brews.DB": true, "tea.sqlite3": true, "log": false} {
+		if isSQLiteHistory(path) != sqlite {
+			t.Errorf("Expected %s to be an SQLite log: %v", path, sqlite)
+		}
+	}
+	if _, err := openHistory("history.db", fastHistoryKey(t, "secret")); err == nil || !strings.Contains(err.Error(), "needs a .jsonl brew log") {
+		t.Errorf("Expected encryption to be refused for a database, got %v", err)
+	}
+}
+
+func TestSyncSQLiteHistory(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	path := filepath.Join(t.TempDir(), "history.db")
+	mine := brewRecord{Time: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), Tea: "Sencha", Duration: Duration{time.Minute}}
+	if err := appendHistory(path, nil, mine); err != nil {
+		t.Fatal(err)
+	}
+	theirs := brewRecord{Time: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), Tea: "Oolong", Duration: Duration{3 * time.Minute}}
+	remote, _ := encodeHistory([]brewRecord{theirs}, nil, historyHeader{})
+
+	local, err := readHistoryLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := mergeHistory(local, remote, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeHistoryLog(path, merged); err != nil {
+		t.Fatal(err)
+	}
+	records, err := loadHistory(path, nil)
+	if err != nil || len(records) != 2 || records[0].Tea != "Oolong" || records[1].Tea != "Sencha" {
+		t.Errorf("Expected both brews in the database, oldest first, got %v, %v", records, err)
+	}
+}
+
+// manyBrews returns n brews spread over the hours of the last few weeks
+// before now, of a dozen teas.
+func manyBrews(n int) []brewRecord {
+	records := make([]brewRecord, n)
+	start := now().AddDate(0, 0, -30)
+	for i := range records {
+		records[i] = brewRecord{
+			Time:     start.Add(time.Duration(i) * 30 * 24 * time.Hour / time.Duration(n)).In(time.FixedZone("", (i%3-1)*3600)),
+			Tea:      fmt.Sprintf("Tea %d", i*7%12+i%5),
+			Duration: Duration{3 * time.Minute},
+		}
+	}
+	return records
+}
+
+func TestSQLiteSummarize(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	records := manyBrews(500)
+	store := sqliteStore{path: filepath.Join(t.TempDir(), "history.db")}
+	if err := store.Replace(records); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Summarize(now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := summarizeHistory(records, now()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the database to agree with the brews:\n%+v\ngot\n%+v", want, got)
+	}
+
+	config := NewConfig()
+	config.HistoryFile = store.path
+	m := initialModel(config)
+	m.width, m.height = 80, 30
+	m.history = records
+	newModel, cmd := m.toggleStats()
+	msg := cmd()
+	if _, ok := msg.(historyStatsMsg); !ok {
+		t.Fatalf("Expected the stats screen to query the database, got %T", msg)
+	}
+	updated, _ := newModel.Update(msg)
+	if view := updated.View(); !strings.Contains(view, "By hour of day: │") || !strings.Contains(view, "Brews per tea: 500 in total") || !strings.Contains(view, "and 8 more") {
+		t.Errorf("Expected the database's stats on the stats screen, got:\n%s", view)
+	}
+}
+
+func BenchmarkSQLiteSummarize(b *testing.B) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		b.Skip("sqlite3 is not installed")
+	}
+	store := sqliteStore{path: filepath.Join(b.TempDir(), "history.db")}
+	if err := store.Replace(manyBrews(50_000)); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for range b.N {
+		if _, err := store.Summarize(now()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
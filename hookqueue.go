@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hookQueueFileName is the name of the webhook delivery queue inside the
+// state directory.
+const hookQueueFileName = "hook-queue.jsonl"
+
+// hookQueueLimit is how many undelivered events the queue keeps; the oldest
+// are dropped beyond it.
+const hookQueueLimit = 100
+
+// hookQueueMaxAge is how long an event waits for delivery before it is
+// dropped, when it is of no more use to an automation.
+const hookQueueMaxAge = 24 * time.Hour
+
+// hookRetryInterval is how long the queue waits after a failed delivery
+// before trying again.
+var hookRetryInterval = time.Minute
+
+// hookQueueMu serialises access to the queue, so two deliveries running at
+// once can't post the same event twice.
+var hookQueueMu sync.Mutex
+
+// hookDelivery is an event waiting in the queue.
+type hookDelivery struct {
+	Event  brewEvent `json:"event"`  // Event to post
+	Queued time.Time `json:"queued"` // When it was queued
+	Tries  int       `json:"tries"`  // Failed deliveries so far
+}
+
+// hookFlushMsg reports a delivery of the queue: how many events are still
+// waiting and why the first of them could not be delivered. background is
+// set for retries, whose failures are only logged.
+type hookFlushMsg struct {
+	left       int
+	err        error
+	background bool
+}
+
+// hookRetryMsg tries to deliver the queue again.
+type hookRetryMsg struct{}
+
+// defaultHookQueuePath returns the location of the webhook delivery queue.
+func defaultHookQueuePath() string {
+	return filepath.Join(dirs().State, hookQueueFileName)
+}
+
+// loadHookQueue reads the queue at path. A missing file is an empty queue.
+func loadHookQueue(path string) ([]hookDelivery, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var queue []hookDelivery
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var d hookDelivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		queue = append(queue, d)
+	}
+	return queue, scanner.Err()
+}
+
+// saveHookQueue replaces the queue at path with queue, removing the file
+// once it is empty.
+func saveHookQueue(path string, queue []hookDelivery) error {
+	if len(queue) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, d := range queue {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// enqueueHooks adds events to the queue at path, skipping any already in it,
+// and drops those past hookQueueMaxAge or beyond hookQueueLimit.
+func enqueueHooks(path string, events []brewEvent, at time.Time) error {
+	hookQueueMu.Lock()
+	defer hookQueueMu.Unlock()
+	queue, err := loadHookQueue(path)
+	if err != nil {
+		return err
+	}
+	queued := make(map[string]bool, len(queue))
+	for _, d := range queue {
+		queued[d.Event.ID] = true
+	}
+	for _, ev := range events {
+		if !queued[ev.ID] {
+			queue = append(queue, hookDelivery{Event: ev, Queued: at})
+		}
+	}
+	return saveHookQueue(path, pruneHookQueue(queue, at))
+}
+
+// queueable returns events as they may be kept in the hook queue. Like the
+// event log, the queue gives away no teas of an encrypted brew log (key set),
+// so its events are queued and delivered without them.
+func queueable(events []brewEvent, key *historyKey) []brewEvent {
+	if key == nil {
+		return events
+	}
+	hidden := make([]brewEvent, len(events))
+	for i, ev := range events {
+		ev.Tea = ""
+		hidden[i] = ev
+	}
+	return hidden
+}
+
+// pruneHookQueue drops the deliveries that have waited too long and the
+// oldest beyond hookQueueLimit.
+func pruneHookQueue(queue []hookDelivery, at time.Time) []hookDelivery {
+	kept := queue[:0]
+	for _, d := range queue {
+		if at.Sub(d.Queued) > hookQueueMaxAge {
+			log.Printf("Dropping brew event %s, undelivered since %s", d.Event.ID, d.Queued.Format(time.RFC3339))
+			continue
+		}
+		kept = append(kept, d)
+	}
+	if extra := len(kept) - hookQueueLimit; extra > 0 {
+		log.Printf("Dropping the %d oldest undelivered brew events", extra)
+		kept = kept[extra:]
+	}
+	return kept
+}
+
+// flushHooks posts the events in the queue at path to url, oldest first,
+// each with retryAlert's retries. Delivered events leave the queue; on a
+// failure the rest stay in it, in order, for the next try. It returns how
+// many events are still waiting.
+func flushHooks(ctx context.Context, path, url string, at time.Time) (int, error) {
+	hookQueueMu.Lock()
+	defer hookQueueMu.Unlock()
+	queue, err := loadHookQueue(path)
+	if err != nil {
+		return 0, err
+	}
+	queue = pruneHookQueue(queue, at)
+	for len(queue) > 0 {
+		ev := queue[0].Event
+		if err = retryAlert(ctx, "brew event", func() error { return postEvent(ctx, url, ev) }); err != nil {
+			queue[0].Tries++
+			break
+		}
+		queue = queue[1:]
+	}
+	if saveErr := saveHookQueue(path, queue); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return len(queue), err
+}
+
+// flushHooksCmd returns a command that delivers the queue in the background,
+// or nil if there is no webhook or queue.
+func (m model) flushHooksCmd() tea.Cmd {
+	path, url := m.config.HookQueuePath, m.config.HookURL
+	if path == "" || url == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		left, err := flushHooks(context.Background(), path, url, now())
+		return hookFlushMsg{left: left, err: err, background: true}
+	}
+}
+
+// hookRetryCmd schedules another delivery of the queue, unless one is
+// already on its way.
+func (m model) hookRetryCmd() (model, tea.Cmd) {
+	if m.hookRetrying {
+		return m, nil
+	}
+	m.hookRetrying = true
+	return m, tea.Tick(hookRetryInterval, func(time.Time) tea.Msg { return hookRetryMsg{} })
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHookQueueRetriesFailedEvents(t *testing.T) {
+	var down atomic.Bool
+	var mu sync.Mutex
+	var delivered []brewEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "offline", http.StatusServiceUnavailable)
+			return
+		}
+		var ev brewEvent
+		json.NewDecoder(r.Body).Decode(&ev)
+		if key := r.Header.Get("Idempotency-Key"); key != ev.ID {
+			t.Errorf("Expected the event ID %q as the idempotency key, got %q", ev.ID, key)
+		}
+		mu.Lock()
+		delivered = append(delivered, ev)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled = false, false
+	config.HistoryFile = ""
+	config.HookURL = server.URL
+	config.HookQueuePath = filepath.Join(t.TempDir(), hookQueueFileName)
+	m := initialModel(config)
+
+	// The network is down when the brew starts
+	down.Store(true)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	m = newModel.(model)
+	var flushed hookFlushMsg
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(hookFlushMsg); ok {
+			flushed = msg
+		}
+	}
+	if flushed.err == nil || flushed.left != 2 {
+		t.Fatalf("Expected both events left in the queue, got %+v", flushed)
+	}
+	queue, err := loadHookQueue(config.HookQueuePath)
+	if err != nil || len(queue) != 2 || queue[0].Event.Type != BrewCreated || queue[0].Tries != 1 {
+		t.Fatalf("Expected the events queued with a failed try, got %+v, %v", queue, err)
+	}
+
+	newModel, cmd = m.Update(flushed)
+	m = newModel.(model)
+	if !m.hookRetrying || cmd == nil || m.status == "" {
+		t.Fatalf("Expected the failure shown and a retry scheduled, got %q", m.status)
+	}
+	// A second failure doesn't schedule another retry
+	if _, cmd := m.Update(hookFlushMsg{left: 2, err: flushed.err, background: true}); cmd != nil {
+		t.Error("Expected one retry at a time")
+	}
+
+	// Back online, the retry delivers the queue in order
+	down.Store(false)
+	newModel, cmd = m.Update(hookRetryMsg{})
+	m = newModel.(model)
+	for _, msg := range cmdMsgs(cmd) {
+		if msg, ok := msg.(hookFlushMsg); !ok || msg.err != nil || msg.left != 0 {
+			t.Errorf("Expected the queue delivered, got %+v", msg)
+		}
+	}
+	if len(delivered) != 2 || delivered[0].Type != BrewCreated || delivered[1].Type != BrewStarted || delivered[0].Brew != m.brewUUID {
+		t.Errorf("Expected the queued events delivered in order, got %+v", delivered)
+	}
+	if _, err := os.Stat(config.HookQueuePath); !os.IsNotExist(err) {
+		t.Errorf("Expected the empty queue removed, got %v", err)
+	}
+}
+
+func TestExitQueuesAbandonedEvent(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		http.Error(w, "offline", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := NewConfig()
+	config.HistoryFile = ""
+	config.HookURL = server.URL
+	config.HookQueuePath = filepath.Join(t.TempDir(), hookQueueFileName)
+	m := initialModel(config)
+	m, _ = m.start()
+	posts.Store(0)
+
+	// Quitting mid-brew doesn't wait for the webhook
+	if err := abandonOnExit(m); err != nil {
+		t.Fatal(err)
+	}
+	if n := posts.Load(); n != 0 {
+		t.Errorf("Expected nothing posted on exit, got %d posts", n)
+	}
+	queue, err := loadHookQueue(config.HookQueuePath)
+	if err != nil || len(queue) != 1 || queue[0].Event.Type != BrewAbandoned {
+		t.Errorf("Expected the abandoned event queued for the next start, got %+v, %v", queue, err)
+	}
+}
+
+func TestHookQueueHidesEncryptedTeas(t *testing.T) {
+	config := NewConfig()
+	config.HistoryFile = ""
+	config.HistoryKey = &historyKey{}
+	config.HookURL = "http://127.0.0.1:1"
+	config.HookQueuePath = filepath.Join(t.TempDir(), hookQueueFileName)
+	m := initialModel(config)
+	m, _ = m.start()
+	if err := abandonOnExit(m); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(config.HookQueuePath)
+	if err != nil || strings.Contains(string(data), "Rooibos") {
+		t.Errorf("Expected the queue to keep no teas of an encrypted log, got %s, %v", data, err)
+	}
+}
+
+func TestHookQueueLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), hookQueueFileName)
+	at := now()
+	old := brewEvent{ID: newUUID(), Type: BrewFinished}
+	if err := enqueueHooks(path, []brewEvent{old}, at.Add(-hookQueueMaxAge-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	events := make([]brewEvent, hookQueueLimit+1)
+	for i := range events {
+		events[i] = brewEvent{ID: newUUID(), Type: BrewStarted}
+	}
+	if err := enqueueHooks(path, events, at); err != nil {
+		t.Fatal(err)
+	}
+	// Queueing an event again doesn't add it twice
+	if err := enqueueHooks(path, events[len(events)-1:], at); err != nil {
+		t.Fatal(err)
+	}
+	queue, err := loadHookQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queue) != hookQueueLimit || queue[0].Event.ID != events[1].ID {
+		t.Errorf("Expected the stale and oldest events dropped, got %d starting with %+v", len(queue), queue[0])
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icsStamp is the iCalendar layout of a time in UTC.
+const icsStamp = "20060102T150405Z"
+
+// icsLineLimit is the longest content line iCalendar allows, in bytes,
+// before it has to be folded onto continuation lines.
+const icsLineLimit = 75
+
+// writeICS writes records as an iCalendar file, one event per brew from
+// when it started steeping to when it finished, so the brews show up in a
+// calendar app next to the rest of the day. stamp is the time the file is
+// created. Each event's UID is derived from its finish time, so importing a
+// newer export updates the brews already there rather than adding them twice.
+func writeICS(w io.Writer, records []brewRecord, stamp time.Time) {
+	line := func(s string) {
+		fmt.Fprint(w, foldICS(s)+"\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-brew//go-brew " + version + "//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Tea brews")
+	for _, rec := range records {
+		end := rec.Time.UTC()
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%d@go-brew", end.UnixNano()))
+		line("DTSTAMP:" + stamp.UTC().Format(icsStamp))
+		line("DTSTART:" + end.Add(-rec.Duration.Duration).Format(icsStamp))
+		line("DTEND:" + end.Format(icsStamp))
+		line("SUMMARY:" + escapeICS(rec.Tea))
+		if description := icsDescription(rec); description != "" {
+			line("DESCRIPTION:" + escapeICS(description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+}
+
+// icsDescription describes the details of a brew that are not in the
+// event's title and times: the infusion and any note.
+func icsDescription(rec brewRecord) string {
+	var parts []string
+	if rec.Infusion > 0 {
+		parts = append(parts, fmt.Sprintf("Infusion %d", rec.Infusion))
+	}
+	if rec.Note != "" {
+		parts = append(parts, rec.Note)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// icsEscaper escapes the characters iCalendar TEXT values reserve.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeICS escapes text for an iCalendar TEXT value.
+func escapeICS(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// foldICS splits a content line longer than icsLineLimit bytes into
+// continuation lines starting with a space, without cutting a UTF-8
+// character in two.
+func foldICS(s string) string {
+	var b strings.Builder
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// The leading space counts towards the continuation line's length
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
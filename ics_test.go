@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICS(t *testing.T) {
+	finished := time.Date(2024, 3, 1, 15, 4, 0, 0, time.FixedZone("CET", 3600))
+	records := []brewRecord{
+		{Time: finished, Tea: "Oolong", Duration: Duration{3 * time.Minute}, Infusion: 2, Note: "Roasty, sweet; try 90°C"},
+	}
+	var buf bytes.Buffer
+	writeICS(&buf, records, now())
+	ics := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"DTSTAMP:20240301T140000Z\r\n",
+		"DTSTART:20240301T140100Z\r\nDTEND:20240301T140400Z\r\n",
+		"SUMMARY:Oolong\r\n",
+		`DESCRIPTION:Infusion 2\nRoasty\, sweet\; try 90°C` + "\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected the calendar to contain %q, got:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(ics, "\r\n", ""), "\n") {
+		t.Error("Expected every line to end in CRLF")
+	}
+}
+
+func TestFoldICS(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("玉露", 20)
+	folded := foldICS(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > icsLineLimit {
+			t.Errorf("Expected lines of at most %d bytes, got %d: %q", icsLineLimit, len(part), part)
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+		t.Errorf("Expected unfolding to restore the line, got %q", unfolded)
+	}
+}
+
+func TestStatsExportICS(t *testing.T) {
+	history := filepath.Join(t.TempDir(), historyFileName)
+	if err := appendHistory(history, nil, brewRecord{Time: now(), Tea: "Sencha", Duration: Duration{time.Minute}}); err != nil {
+		t.Fatalf("Failed to append history: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runStatsCommand([]string{"export", "--ics", "-history", history}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "BEGIN:VCALENDAR") || !strings.Contains(stdout.String(), "SUMMARY:Sencha") {
+		t.Errorf("Expected a calendar with the brew, got:\n%s", stdout.String())
+	}
+	if code := runStatsCommand([]string{"export", "--svg", "--ics", "-history", history}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for two formats, got %d", code)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+)
+
+// Terminal image protocols go-brew can draw preset pictures with.
+const (
+	ImageKitty = "kitty"  // kitty graphics protocol, also spoken by Ghostty and Konsole
+	ImageITerm = "iterm2" // iTerm2 inline images, also spoken by WezTerm
+)
+
+const (
+	imageSize  = 24   // Width and height of a preset picture in pixels
+	imageCells = 2    // Terminal cells a preset picture is scaled to, one row high
+	kittyChunk = 4096 // Largest base64 payload kitty accepts per escape sequence
+)
+
+// kittyClear deletes every image placed with the kitty protocol, so a
+// picture doesn't linger once the preset line is gone.
+const kittyClear = "\x1b_Ga=d,q=2\x1b\\"
+
+// liquorColors gives the color a brewed tea is drawn in, by a word in the
+// preset name. The first match wins, so specific names come first.
+var liquorColors = []struct{ word, color string }{
+	{"matcha", "#7FA33A"},
+	{"rooibos", "#B5442A"},
+	{"hibiscus", "#A3243B"},
+	{"pu-erh", "#4A2412"},
+	{"puerh", "#4A2412"},
+	{"green", "#B8C55A"},
+	{"sencha", "#B8C55A"},
+	{"white", "#E8D9A0"},
+	{"oolong", "#C88A2E"},
+	{"black", "#6B2E12"},
+	{"assam", "#6B2E12"},
+	{"earl grey", "#6B2E12"},
+	{"herbal", "#C9A83A"},
+	{"chamomile", "#D9B83A"},
+	{"mint", "#9DA845"},
+}
+
+// defaultLiquor is the color of teas liquorColors doesn't know.
+const defaultLiquor = "#C8902E"
+
+// teaImages caches the encoded picture for each liquor color.
+var teaImages sync.Map
+
+// detectImageProtocol returns the image protocol the terminal described by
+// the environment supports, or "" if it supports none that go-brew knows.
+func detectImageProtocol(getenv func(string) string) string {
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", getenv("TERM") == "xterm-kitty", getenv("TERM") == "xterm-ghostty":
+		return ImageKitty
+	case getenv("TERM_PROGRAM") == "iTerm.app", getenv("TERM_PROGRAM") == "WezTerm":
+		return ImageITerm
+	}
+	return ""
+}
+
+// liquorColor returns the color the named tea is drawn in.
+func liquorColor(name string) string {
+	name = strings.ToLower(name)
+	for _, l := range liquorColors {
+		if strings.Contains(name, l.word) {
+			return l.color
+		}
+	}
+	return defaultLiquor
+}
+
+// teaImage returns a PNG of a cup of the named tea seen from above.
+func teaImage(name string) []byte {
+	liquor := liquorColor(name)
+	if data, ok := teaImages.Load(liquor); ok {
+		return data.([]byte)
+	}
+
+	r, g, b := hexRGB(liquor)
+	tea := color.NRGBA{uint8(r), uint8(g), uint8(b), 255}
+	cup := color.NRGBA{0xF4, 0xF1, 0xEA, 255}
+	img := image.NewNRGBA(image.Rect(0, 0, imageSize, imageSize))
+	const center, outer, inner = 10.5, 10.0, 7.5
+	for y := 0; y < imageSize; y++ {
+		for x := 0; x < imageSize; x++ {
+			dx, dy := float64(x)-center, float64(y)-center
+			switch d := dx*dx + dy*dy; {
+			case d <= inner*inner:
+				img.Set(x, y, tea)
+			case d <= outer*outer:
+				img.Set(x, y, cup)
+			case x >= 20 && y >= 9 && y <= 12:
+				img.Set(x, y, cup) // Handle
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img) // Writing to memory cannot fail
+	teaImages.Store(liquor, buf.Bytes())
+	return buf.Bytes()
+}
+
+// inlineImage returns the escape sequences that draw the picture of the
+// named tea over the next imageCells cells, followed by whatever keeps the
+// text after it aligned, or "" when protocol is not a supported one.
+func inlineImage(protocol, name string) string {
+	payload := base64.StdEncoding.EncodeToString(teaImage(name))
+	switch protocol {
+	case ImageKitty:
+		// C=1 leaves the cursor in place, so spaces reserve the cells the
+		// picture covers and keep the line's width right for centering
+		var b strings.Builder
+		b.WriteString(kittyClear)
+		for i := 0; i < len(payload); i += kittyChunk {
+			more := 0
+			if i+kittyChunk < len(payload) {
+				more = 1
+			}
+			chunk := payload[i:min(i+kittyChunk, len(payload))]
+			if i == 0 {
+				fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=1,C=1,q=2,m=%d;%s\x1b\\", imageCells, more, chunk)
+			} else {
+				fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+			}
+		}
+		return b.String() + strings.Repeat(" ", imageCells) + " "
+	case ImageITerm:
+		return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=1;preserveAspectRatio=1:%s\a ", imageCells, payload)
+	}
+	return ""
+}
+
+// presetImage returns the picture drawn before the preset info, or "" when
+// pictures are off or the terminal cannot show them; the text alone then
+// describes the tea.
+func (m model) presetImage(name string) string {
+	if !m.config.Images {
+		return ""
+	}
+	return inlineImage(m.config.ImageProtocol, name)
+}
+
+// clearImages returns what removes pictures that are no longer wanted. Only
+// kitty keeps pictures apart from the text that is written over them.
+func (m model) clearImages() string {
+	if m.config.Images && m.config.ImageProtocol == ImageKitty {
+		return kittyClear
+	}
+	return ""
+}
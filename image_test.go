@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, ImageKitty},
+		{map[string]string{"KITTY_WINDOW_ID": "1", "TERM": "screen"}, ImageKitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ImageITerm},
+		{map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, ""},
+		{map[string]string{}, ""},
+	}
+	for _, tt := range tests {
+		if got := detectImageProtocol(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("Expected %q for %v, got %q", tt.want, tt.env, got)
+		}
+	}
+}
+
+func TestTeaImage(t *testing.T) {
+	img, err := png.Decode(bytes.NewReader(teaImage("Green Tea")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, g, b, _ := img.At(10, 10).RGBA()
+	if wr, wg, wb := hexRGB(liquorColor("green tea")); int(r>>8) != wr || int(g>>8) != wg || int(b>>8) != wb {
+		t.Errorf("Expected the cup to hold green tea, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+	if liquorColor("Shou Pu-erh") == liquorColor("Green Tea") || liquorColor("Mystery") != defaultLiquor {
+		t.Error("Expected teas to get their own liquor colors and unknown ones the default")
+	}
+}
+
+func TestInlineImage(t *testing.T) {
+	kitty := inlineImage(ImageKitty, "Oolong")
+	if !strings.HasPrefix(kitty, kittyClear+"\x1b_Ga=T,f=100,") || !strings.HasSuffix(kitty, "\x1b\\   ") {
+		t.Errorf("Unexpected kitty sequence %q", kitty)
+	}
+	if iterm := inlineImage(ImageITerm, "Oolong"); !strings.HasPrefix(iterm, "\x1b]1337;File=inline=1;") {
+		t.Errorf("Unexpected iTerm2 sequence %q", iterm)
+	}
+	if got := inlineImage("", "Oolong"); got != "" {
+		t.Errorf("Expected no picture without a protocol, got %q", got)
+	}
+
+	// The preset line falls back to text when the terminal can't draw
+	m := initialModel(NewConfig())
+	m.config.Images = true
+	m.width, m.height = 100, 40
+	if view := m.View(); strings.Contains(view, "\x1b_G") || !strings.Contains(view, "🍵 Rooibos") {
+		t.Error("Expected the text preset line without an image protocol")
+	}
+	m.config.ImageProtocol = ImageKitty
+	m.cache = &viewCache{}
+	if view := m.View(); !strings.Contains(view, "\x1b_Ga=T") || strings.Contains(view, "🍵 Rooibos") {
+		t.Error("Expected a kitty picture in place of the cup emoji")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// steeps returns the infusion schedule of the next brew: the selected
+// preset's steeps, unless a custom duration, a -sequence or the stopwatch
+// decides the brew length instead.
+func (m model) steeps() []time.Duration {
+	if !m.presetsSelectable() || m.customBrew() {
+		return nil
+	}
+	return m.currentPreset().Steeps
+}
+
+// steepDuration returns the length of the current infusion. Infusions past
+// the end of the schedule repeat its last steep.
+func (m model) steepDuration() (time.Duration, bool) {
+	steeps := m.steeps()
+	if len(steeps) == 0 {
+		return 0, false
+	}
+	return steeps[min(m.infusion, len(steeps)-1)], true
+}
+
+// infusionLabel describes the current infusion, e.g. "Infusion 2/5 (30s)",
+// or returns "" for presets without a steep schedule.
+func (m model) infusionLabel() string {
+	d, ok := m.steepDuration()
+	if !ok {
+		return ""
+	}
+	if n := len(m.steeps()); m.infusion < n {
+		return fmt.Sprintf("Infusion %d/%d (%v)", m.infusion+1, n, d)
+	}
+	return fmt.Sprintf("Infusion %d (%v)", m.infusion+1, d)
+}
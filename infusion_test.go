@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestInfusions(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	config.Presets = []TeaPreset{{"Oolong", 25 * time.Second, "95°C", "", []time.Duration{25 * time.Second, 40 * time.Second}, PresetInfo{}}}
+	m := initialModel(config)
+	send := func(msg tea.Msg) tea.Cmd {
+		newModel, cmd := m.Update(msg)
+		m = newModel.(model)
+		return cmd
+	}
+	brew := func() {
+		send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+		m.timer = time.Second
+		cmdMsgs(send(tickLeft(m, 0)))
+	}
+
+	if m.brewDuration() != 25*time.Second || !contains(m.View(), "Infusion 1/2 (25s)") {
+		t.Fatalf("Expected the first infusion of 25s, got %v", m.brewDuration())
+	}
+	brew()
+	if m.brewDuration() != 40*time.Second {
+		t.Errorf("Expected the second infusion of 40s, got %v", m.brewDuration())
+	}
+	brew()
+	brew()
+	if m.brewDuration() != 40*time.Second || m.infusionLabel() != "Infusion 4 (40s)" {
+		t.Errorf("Expected the last steep to repeat, got %q", m.infusionLabel())
+	}
+
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 3 || records[0].Infusion != 1 || records[2].Infusion != 3 {
+		t.Fatalf("Expected numbered infusions in the history, got %+v, %v", records, err)
+	}
+
+	// Reset after a brew readies the next infusion; reset while idle starts over
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyReset)})
+	if m.infusion != 3 {
+		t.Errorf("Expected reset after a brew to keep the infusion, got %d", m.infusion)
+	}
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyReset)})
+	if m.infusion != 0 || m.timer != 25*time.Second {
+		t.Errorf("Expected reset while idle to start over, got infusion %d and %v", m.infusion, m.timer)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputKind identifies what the text input line is currently asking for.
+type inputKind int
+
+const (
+	// inputNone means no text input is open and keys drive the timer
+	inputNone inputKind = iota
+	// inputDuration asks for a custom duration for the next brew
+	inputDuration
+	// inputBrewName asks for the tea name of a custom-duration brew
+	inputBrewName
+	// inputNote asks for a free-text note on the current brew
+	inputNote
+	// inputPresetName asks for the name of a copy of the selected preset
+	inputPresetName
+	// inputPresetDuration asks for a new duration for the selected preset
+	inputPresetDuration
+	// inputHistoryFilter asks for a filter for the brews on the stats screen
+	inputHistoryFilter
+	// inputTelemetry asks on first run whether to share usage counts
+	inputTelemetry
+	// inputRating asks how the finished brew turned out
+	inputRating
+)
+
+// maxNoteLength is the longest note that can be attached to a brew.
+const maxNoteLength = 120
+
+// newTextInput creates the single-line text input used for prompts. The
+// cursor does not blink, so an open prompt does not cause periodic renders.
+func newTextInput() textinput.Model {
+	ti := textinput.New()
+	ti.CharLimit = 32
+	ti.Width = 20
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	return ti
+}
+
+// openInput shows the text input for the given purpose with a prompt and an
+// example value as placeholder.
+func (m model) openInput(kind inputKind, prompt, placeholder string) (model, tea.Cmd) {
+	m.inputKind = kind
+	m.input = newTextInput()
+	m.input.Prompt = prompt
+	m.input.Placeholder = placeholder
+	return m, m.input.Focus()
+}
+
+// closeInput hides the text input and returns keys to the timer.
+func (m model) closeInput() model {
+	m.inputKind = inputNone
+	m.input.Blur()
+	return m
+}
+
+// updateInput handles a key press while the text input is open: enter
+// submits, esc cancels and everything else edits the text.
+func (m model) updateInput(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.closeInput(), nil
+	case tea.KeyCtrlC:
+		// Quit as usual, including the confirmation for a running brew
+		newModel, cmd := m.closeInput().Update(msg)
+		return newModel.(model), cmd
+	case tea.KeyEnter:
+		return m.submitInput()
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// submitInput applies the text typed into the input. Invalid values keep the
+// input open and explain the problem in the status line.
+func (m model) submitInput() (model, tea.Cmd) {
+	switch m.inputKind {
+	case inputDuration:
+		// A clock time such as 14:45 sets an alarm instead of a duration
+		t := now()
+		if at, err := parseAlarmTime(m.input.Value(), t); err == nil {
+			m = m.closeInput()
+			m = m.saveUndo("alarm change")
+			m = m.setAlarm(at, t)
+			return m.showStatus("Alarm at " + m.alarmClock())
+		}
+		d, err := parseBrewDuration(m.input.Value())
+		if err != nil {
+			return m.showStatus(err.Error())
+		}
+		m = m.closeInput()
+		m = m.saveUndo("duration change")
+		m = m.clearCustom()
+		m.customDuration = d
+		if m.state == StateIdle && !m.stopwatch {
+			m.timer = m.brewDuration()
+		}
+		return m.showStatus(fmt.Sprintf("Next brew: %v", d))
+	case inputBrewName:
+		m = m.closeInput()
+		m.brewName = strings.TrimSpace(m.input.Value())
+		return m.start()
+	case inputNote:
+		m = m.closeInput()
+		m.note = strings.TrimSpace(m.input.Value())
+		return m, nil
+	case inputPresetName:
+		name := strings.TrimSpace(m.input.Value())
+		if err := m.checkPresetName(name); err != nil {
+			return m.showStatus(err.Error())
+		}
+		return m.closeInput().addPresetCopy(name)
+	case inputPresetDuration:
+		d, err := parsePresetDuration(m.input.Value())
+		if err != nil {
+			return m.showStatus(err.Error())
+		}
+		return m.closeInput().setPresetDuration(d)
+	case inputHistoryFilter:
+		return m.setHistoryFilter(m.input.Value())
+	case inputTelemetry:
+		return m.answerTelemetry(m.input.Value())
+	case inputRating:
+		return m.submitRating(m.input.Value())
+	}
+	return m.closeInput(), nil
+}
+
+// inputView renders the open text input, or "" when none is open.
+func (m model) inputView() string {
+	if m.inputKind == inputNone {
+		return ""
+	}
+	return m.input.View()
+}
+
+// inputHint describes the keys that submit or cancel the open input.
+func (m model) inputHint() string {
+	switch m.inputKind {
+	case inputBrewName:
+		return "enter to start brewing · esc to cancel"
+	case inputNote:
+		return "enter to save (empty removes the note) · esc to cancel"
+	case inputHistoryFilter:
+		return "words match tea and note · from:/to: YYYY-MM-DD · enter applies (empty clears)"
+	case inputTelemetry:
+		return "counts of brews and features only, never what you type · y to share · enter declines · esc asks next time"
+	}
+	return "enter to apply · esc to cancel"
+}
+
+// parseBrewDuration parses a duration typed in the TUI, such as "2m45s",
+// and checks it against the supported brew time range.
+func parseBrewDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration or time %q (try 3m, 2m45s or 14:45)", s)
+	}
+	if err := checkBrewTime(d); err != nil {
+		return 0, err
+	}
+	return d, nil
+}
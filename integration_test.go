@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+)
+
+// TestMain pins the renderer to a colorless profile so that golden files do
+// not depend on the terminal the tests happen to run in, fixes the clock so
+// "ready at" times are stable, shortens the tick interval so complete
+// brews finish in milliseconds, and keeps the brew log and its events of
+// tests that don't pick one out of the user's data directory.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "go-brew-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	os.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	lipgloss.SetColorProfile(termenv.Ascii)
+	tickInterval = time.Millisecond
+	alertBackoff = time.Millisecond
+	os.Setenv("LC_ALL", "C")
+	now = func() time.Time { return time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC) }
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// newTestConfig returns a configuration suitable for full-program tests:
+// a short custom brew, no sound or desktop notifications and no history log.
+func newTestConfig(brew time.Duration) *Config {
+	config := NewConfig()
+	config.BrewTime = brew
+	config.CustomDuration = true
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = ""
+	return config
+}
+
+// TestProgramBrewToFinish drives the whole program from start to finish and
+// compares the finished view against a golden file.
+func TestProgramBrewToFinish(t *testing.T) {
+	tm := teatest.NewTestModel(t, initialModel(newTestConfig(3*time.Second)),
+		teatest.WithInitialTermSize(60, 24))
+
+	// Custom brews ask for a tea name before they start
+	tm.Type("s")
+	tm.Type("Sencha")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Tea Ready!"))
+	}, teatest.WithDuration(5*time.Second))
+
+	tm.Type("q")
+	final, ok := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if !ok {
+		t.Fatal("Failed to cast to model")
+	}
+	if !final.isFinished() {
+		t.Errorf("Expected finished state, got %v", final.state)
+	}
+	if final.brewTea != "Sencha" {
+		t.Errorf("Expected brew named %q, got %q", "Sencha", final.brewTea)
+	}
+	golden.RequireEqual(t, []byte(final.View()))
+}
+
+// TestProgramIdleView verifies the initial view rendered at a fixed terminal size.
+func TestProgramIdleView(t *testing.T) {
+	tm := teatest.NewTestModel(t, initialModel(newTestConfig(2*time.Minute)),
+		teatest.WithInitialTermSize(60, 24))
+
+	tm.Type("q")
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	golden.RequireEqual(t, []byte(final.View()))
+}
+
+// TestProgramPauseStopsCountdown verifies that a paused brew keeps its
+// remaining time while ticks are still arriving.
+func TestProgramPauseStopsCountdown(t *testing.T) {
+	tm := teatest.NewTestModel(t, initialModel(newTestConfig(10*time.Minute)),
+		teatest.WithInitialTermSize(60, 24))
+
+	tm.Type("s")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Send(tea.KeyMsg{Type: tea.KeySpace})
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Paused"))
+	}, teatest.WithDuration(5*time.Second))
+	time.Sleep(20 * tickInterval)
+
+	// Quitting a brew in progress needs confirmation
+	tm.Type("q")
+	tm.Type("q")
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if !final.isPaused() {
+		t.Fatalf("Expected paused state, got %v", final.state)
+	}
+	if final.timer <= 0 || final.timer > 10*time.Minute {
+		t.Errorf("Expected remaining time to be preserved, got %v", final.timer)
+	}
+}
+
+// TestProgramExitOnFinish verifies that -exit-on-finish ends the program on
+// its own once the brew is done.
+func TestProgramExitOnFinish(t *testing.T) {
+	config := newTestConfig(2 * time.Second)
+	config.CustomDuration = false
+	config.BrewTime = DefaultBrewTime
+	config.Presets = []TeaPreset{{"Quick", 2 * time.Second, "", "", nil, PresetInfo{}}}
+	config.ExitOnFinish = 10 * time.Millisecond
+	tm := teatest.NewTestModel(t, initialModel(config), teatest.WithInitialTermSize(60, 24))
+
+	tm.Type("s")
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if !final.isFinished() {
+		t.Errorf("Expected program to exit after finishing, got state %v", final.state)
+	}
+}
+
+// TestProgramSequence runs a two-step sequence and checks that the second
+// step starts on its own when the first one finishes.
+func TestProgramSequence(t *testing.T) {
+	config := newTestConfig(2 * time.Minute)
+	config.Sequence = []sequenceStep{
+		{Label: "Bloom", Duration: 2 * time.Second},
+		{Label: "Pour", Duration: 3 * time.Second},
+	}
+	tm := teatest.NewTestModel(t, initialModel(config), teatest.WithInitialTermSize(60, 24))
+
+	tm.Type("s")
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Tea Ready!"))
+	}, teatest.WithDuration(5*time.Second))
+
+	tm.Type("q")
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if !final.isFinished() || final.step != 1 || final.brewTea != "Pour" {
+		t.Errorf("Expected last step finished, got step %d (%q) in state %v", final.step, final.brewTea, final.state)
+	}
+	if !bytes.Contains([]byte(final.View()), []byte("Step 2/2: Pour")) {
+		t.Errorf("Expected step label in view:\n%s", final.View())
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// reservedKeys are the keys the timer handles itself, with what they do.
+// No action can be bound to them.
+var reservedKeys = map[string]string{
+	KeyQuitAlt: "always quits",
+	KeySuspend: "suspends go-brew to the shell",
+	"esc":      "cancels prompts and closes screens",
+	"enter":    "submits prompts and starts behavior.enter_starts",
+}
+
+func init() {
+	for d := range 10 {
+		reservedKeys[strconv.Itoa(d)] = "picks presets by number and types durations"
+	}
+}
+
+// keyAction is one action's key and the setting that binds it.
+type keyAction struct {
+	setting string // Setting key, e.g. "keys.start"
+	key     string // Bound key
+}
+
+// actions lists the bound keys in the order of the [keys] settings.
+func (k KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"keys.start", k.Start},
+		{"keys.pause", k.Pause},
+		{"keys.reset", k.Reset},
+		{"keys.quit", k.Quit},
+		{"keys.up", k.Up},
+		{"keys.down", k.Down},
+		{"keys.duration", k.Duration},
+		{"keys.undo", k.Undo},
+		{"keys.stopwatch", k.Stopwatch},
+		{"keys.lap", k.Lap},
+		{"keys.stats", k.Stats},
+		{"keys.note", k.Note},
+		{"keys.copy", k.Copy},
+		{"keys.edit", k.Edit},
+		{"keys.longer", k.Longer},
+		{"keys.shorter", k.Shorter},
+		{"keys.catalog", k.Catalog},
+		{"keys.info", k.Info},
+		{"keys.cool", k.Cool},
+		{"keys.rate", k.Rate},
+		{"keys.suggest", k.Suggest},
+		{"keys.calc", k.Calc},
+		{"keys.vessel", k.Vessel},
+		{"keys.collect", k.Collect},
+	}
+}
+
+// keyConflicts returns a problem for each key bound to two actions or to a
+// reserved key. The bindings may come from different config layers, so
+// each problem says where the settings involved were made.
+func (c *Config) keyConflicts() []error {
+	var errs []error
+	bound := make(map[string]string) // Key to the first setting binding it
+	for _, a := range c.Keys.actions() {
+		if use, ok := reservedKeys[a.key]; ok {
+			errs = append(errs, fmt.Errorf("%s (%s) is %q, which is reserved because it %s; choose another key",
+				a.setting, c.source(a.setting), a.key, use))
+			continue
+		}
+		if first, ok := bound[a.key]; ok {
+			errs = append(errs, fmt.Errorf("%s (%s) and %s (%s) are both %q; bind one of them to another key",
+				first, c.source(first), a.setting, c.source(a.setting), a.key))
+			continue
+		}
+		bound[a.key] = a.setting
+	}
+	return errs
+}
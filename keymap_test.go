@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyConflicts(t *testing.T) {
+	config := NewConfig()
+	if errs := config.keyConflicts(); len(errs) != 0 {
+		t.Fatalf("Expected the default keys not to conflict, got %v", errs)
+	}
+
+	config.Keys.Reset = KeyStart
+	config.Sources["keys.reset"] = "user config.toml"
+	config.Keys.Quit = "esc"
+	config.Keys.Info = "5"
+	errs := config.keyConflicts()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 conflicts, got %v", errs)
+	}
+	if got := errs[0].Error(); !strings.Contains(got, "keys.start (default) and keys.reset (user config.toml) are both \"s\"") {
+		t.Errorf("Expected the shared key with both sources, got %q", got)
+	}
+	if got := errs[1].Error(); !strings.Contains(got, `keys.quit (default) is "esc", which is reserved because it cancels prompts`) {
+		t.Errorf("Expected esc to be reserved, got %q", got)
+	}
+	if got := errs[2].Error(); !strings.Contains(got, "picks presets by number") {
+		t.Errorf("Expected digits to be reserved, got %q", got)
+	}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected Validate to report key conflicts")
+	}
+}
+
+func TestValidateCommandKeyConflicts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+	if err := os.WriteFile(path, []byte("[keys]\nquit = \"ctrl+c\"\nnote = \"s\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runConfigCommand([]string{"validate", "-config", path}, &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected exit code 1, got %d (%s)", code, stdout.String())
+	}
+	for _, want := range []string{`keys.quit (user ` + path + `) is "ctrl+c"`, `keys.start (default) and keys.note (user ` + path + `) are both "s"`} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("Expected %q in\n%s", want, stderr.String())
+		}
+	}
+}
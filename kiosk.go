@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kioskPresets returns the presets named in kiosk.presets, in that order,
+// for a kiosk that offers only a few teas. With no names every preset is
+// offered. Names match regardless of case.
+func kioskPresets(names []string, presets []TeaPreset) ([]TeaPreset, error) {
+	if len(names) == 0 {
+		return presets, nil
+	}
+	var offered []TeaPreset
+	for _, name := range names {
+		i := -1
+		for j, p := range presets {
+			if strings.EqualFold(p.Name, name) {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("there is no preset called %q", name)
+		}
+		offered = append(offered, presets[i])
+	}
+	return offered, nil
+}
+
+// kioskLockedKeys returns the keys -kiosk ignores: quitting, suspending
+// and everything that edits presets or the brew time, which guests at a
+// shared display shouldn't be able to do. The timer is stopped from outside,
+// e.g. with systemctl stop or kill.
+func (c *Config) kioskLockedKeys() map[string]bool {
+	return map[string]bool{
+		c.Keys.Quit:     true,
+		KeyQuitAlt:      true,
+		KeySuspend:      true,
+		c.Keys.Copy:     true,
+		c.Keys.Edit:     true,
+		c.Keys.Catalog:  true,
+		c.Keys.Longer:   true,
+		c.Keys.Shorter:  true,
+		c.Keys.Duration: true,
+		c.Keys.Suggest:  true,
+	}
+}
+
+// kioskLocked reports whether key is ignored because of -kiosk.
+func (c *Config) kioskLocked(key string) bool {
+	return c.Kiosk && c.kioskLockedKeys()[key]
+}
+
+// kioskBindings leaves the locked keys out of the help entries, dropping
+// an entry such as "c/e" only when all of its keys are locked.
+func (c *Config) kioskBindings(bindings []KeyBinding) []KeyBinding {
+	var kept []KeyBinding
+	for _, b := range bindings {
+		keys := strings.Split(b.Key, "/")
+		locked := true
+		for _, k := range keys {
+			locked = locked && c.kioskLocked(k)
+		}
+		if !locked {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestKioskPresets(t *testing.T) {
+	presets, err := kioskPresets([]string{"oolong", "Green Tea"}, DefaultTeaPresets)
+	if err != nil || len(presets) != 2 || presets[0].Name != "Oolong" || presets[1].Name != "Green Tea" {
+		t.Errorf("Expected Oolong and Green Tea in that order, got %v, %v", presets, err)
+	}
+	if presets, _ := kioskPresets(nil, DefaultTeaPresets); len(presets) != len(DefaultTeaPresets) {
+		t.Errorf("Expected every preset without kiosk.presets, got %d", len(presets))
+	}
+	if _, err := kioskPresets([]string{"Sencha"}, DefaultTeaPresets); err == nil {
+		t.Error("Expected an error for an unknown preset")
+	}
+}
+
+func TestBigText(t *testing.T) {
+	big, ok := bigText("02:30")
+	lines := strings.Split(big, "\n")
+	if !ok || len(lines) != 5 {
+		t.Fatalf("Expected five rows, got %q, %v", big, ok)
+	}
+	for _, line := range lines {
+		if lipgloss.Width(line) != lipgloss.Width(lines[0]) {
+			t.Errorf("Expected rows of equal width, got %q", big)
+		}
+	}
+	if _, ok := bigText("2m 30s"); ok {
+		t.Error("Expected no big text for letters")
+	}
+}
+
+func TestKioskMode(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.Kiosk = true
+	config.KeyBindings = config.bindings()
+	m := initialModel(config)
+	m.width, m.height = 80, 40
+
+	view := m.View()
+	if !strings.Contains(view, "██") {
+		t.Error("Expected the kiosk to show the time in large digits")
+	}
+	if strings.Contains(view, "Quit") || strings.Contains(view, "Duplicate/edit preset") {
+		t.Errorf("Expected the locked keys to be left out of the controls:\n%s", view)
+	}
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune(KeyQuit)},
+		{Type: tea.KeyCtrlC},
+		{Type: tea.KeyRunes, Runes: []rune(KeyEdit)},
+		{Type: tea.KeyRunes, Runes: []rune(KeyDuration)},
+		{Type: tea.KeyRunes, Runes: []rune(KeyLonger)},
+	} {
+		newModel, cmd := m.Update(key)
+		if got := newModel.(model); got.inputKind != inputNone || got.customDuration != 0 || cmd != nil {
+			t.Errorf("Expected %s to do nothing in kiosk mode", keyName(key))
+		}
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	if !newModel.(model).isBrewing() {
+		t.Error("Expected guests to be able to start a brew")
+	}
+}
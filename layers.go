@@ -0,0 +1,576 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// projectConfigName is the name of the per-project config file, looked up in
+// the working directory and its parents.
+const projectConfigName = ".gobrew.toml"
+
+// settingKeys lists every single-valued setting in config file notation, in
+// the order "go-brew config sources" prints them. Each can also be set from
+// the environment as GOBREW_<KEY>, e.g. GOBREW_ALERTS_SOUND.
+var settingKeys = []string{
+	"duration",
+	"alerts.sound",
+	"alerts.desktop",
+	"alerts.notification_sound",
+	"alerts.urgency",
+	"alerts.quiet_hours",
+	"alerts.expire",
+	"alerts.remind",
+	"alerts.cold_after",
+	"alerts.notify_start",
+	"alerts.notify_pause",
+	"alerts.notify_resume",
+	"alerts.speaker",
+	"alerts.gpio_pin",
+	"alerts.light.type",
+	"alerts.light.host",
+	"alerts.light.token",
+	"alerts.light.id",
+	"alerts.light.color",
+	"behavior.quick_start",
+	"behavior.confirm_quit",
+	"behavior.confirm_duration",
+	"behavior.pause_on_suspend",
+	"behavior.digit_entry",
+	"behavior.enter_starts",
+	"behavior.vessel",
+	"behavior.vessel_timing",
+	"cues.halfway",
+	"cues.final",
+	"cues.sound",
+	"cooling.boil",
+	"cooling.room",
+	"cooling.half_life",
+	"history.file",
+	"history.max_entries",
+	"history.max_days",
+	"history.encrypt",
+	"history.keyfile",
+	"scale.device",
+	"hooks.url",
+	"overlay.file",
+	"display.time_format",
+	"display.locale",
+	"display.images",
+	"display.strength",
+	"display.emoji",
+	"display.screensaver",
+	"display.taskbar_progress",
+	"display.tick_interval",
+	"colors.theme",
+	"colors.ready",
+	"colors.brewing",
+	"colors.paused",
+	"colors.idle",
+	"colors.halfway",
+	"colors.final",
+	"keys.start",
+	"keys.pause",
+	"keys.reset",
+	"keys.quit",
+	"keys.up",
+	"keys.down",
+	"keys.duration",
+	"keys.undo",
+	"keys.stopwatch",
+	"keys.lap",
+	"keys.stats",
+	"keys.note",
+	"keys.copy",
+	"keys.edit",
+	"keys.longer",
+	"keys.shorter",
+	"keys.catalog",
+	"keys.info",
+	"keys.cool",
+	"keys.rate",
+	"keys.suggest",
+	"keys.calc",
+	"keys.vessel",
+	"keys.collect",
+	"sync.type",
+	"sync.remote",
+	"sync.user",
+	"telemetry.endpoint",
+}
+
+// boolSettings are the setting keys that take true/false values.
+var boolSettings = map[string]bool{
+	"alerts.sound":              true,
+	"alerts.desktop":            true,
+	"alerts.notification_sound": true,
+	"alerts.notify_start":       true,
+	"alerts.notify_pause":       true,
+	"alerts.notify_resume":      true,
+	"behavior.quick_start":      true,
+	"behavior.confirm_quit":     true,
+	"behavior.confirm_duration": true,
+	"behavior.pause_on_suspend": true,
+	"behavior.digit_entry":      true,
+	"behavior.vessel_timing":    true,
+	"display.images":            true,
+	"display.strength":          true,
+	"display.emoji":             true,
+	"display.taskbar_progress":  true,
+	"history.encrypt":           true,
+	"cues.sound":                true,
+}
+
+// intSettings are the setting keys that take whole numbers.
+var intSettings = map[string]bool{
+	"alerts.gpio_pin":     true,
+	"alerts.light.id":     true,
+	"cues.halfway":        true,
+	"cooling.boil":        true,
+	"cooling.room":        true,
+	"history.max_entries": true,
+	"history.max_days":    true,
+}
+
+// configLayer is one file in the configuration precedence chain.
+type configLayer struct {
+	name     string // Layer name shown by "go-brew config sources"
+	path     string // File to read
+	required bool   // Whether a missing file is an error
+	migrate  bool   // Whether the file may be rewritten to the current schema
+	project  bool   // Whether only the timer and its looks may be set, see restrictProject
+}
+
+// source describes the layer as the origin of a setting.
+func (l configLayer) source() string {
+	return l.name + " " + l.path
+}
+
+// layers returns the config files to read, lowest precedence first: the
+// system-wide file, the user's file (or the one named with -config) and the
+// nearest project-local .gobrew.toml. Only the user's own file is migrated in
+// place; the others may not be writable and are upgraded in memory.
+func (c *Config) layers() []configLayer {
+	layers := []configLayer{
+		{name: "system", path: dirs().SystemConfig},
+		{name: "user", path: c.ConfigPath, required: c.flagSet("config"), migrate: true},
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if path := findProjectConfig(wd); path != "" {
+			layers = append(layers, configLayer{name: "project", path: path, project: true})
+		}
+	}
+	return layers
+}
+
+// findProjectConfig returns the path of the .gobrew.toml in dir or its
+// closest parent, or "" if there is none.
+func findProjectConfig(dir string) string {
+	for {
+		path := filepath.Join(dir, projectConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// restrictProject clears the sections a project config may not set and
+// returns their names. A .gobrew.toml comes with whatever repository was
+// cloned, so it only gets the timer and how it looks: presets, vessels, cues,
+// behavior, display, colors and keys. Files, URLs, hosts and devices are
+// left to the user's own config.
+func (fc *fileConfig) restrictProject() []string {
+	var dropped []string
+	for _, section := range []struct {
+		name  string
+		value any
+	}{
+		{"alerts", &fc.Alerts},
+		{"history", &fc.History},
+		{"scale", &fc.Scale},
+		{"hooks", &fc.Hooks},
+		{"overlay", &fc.Overlay},
+		{"sync", &fc.Sync},
+		{"telemetry", &fc.Telemetry},
+	} {
+		if v := reflect.ValueOf(section.value).Elem(); !v.IsZero() {
+			dropped = append(dropped, section.name)
+			v.SetZero()
+		}
+	}
+	return dropped
+}
+
+// Load builds the effective configuration by applying, in increasing order of
+// precedence: the built-in defaults, the system config, the user config, the
+// project-local .gobrew.toml, GOBREW_* environment variables and finally the
+// command line flags. Sources records where each setting came from.
+func (c *Config) Load() error {
+	for _, layer := range c.layers() {
+		if err := c.loadLayer(layer); err != nil {
+			return err
+		}
+	}
+	return c.loadEnv(os.Getenv)
+}
+
+// loadLayer reads one config file and merges it into c.
+func (c *Config) loadLayer(layer configLayer) error {
+	if layer.migrate {
+		backup, err := migrateConfigFile(layer.path)
+		if err != nil {
+			return err
+		}
+		if backup != "" {
+			log.Printf("Migrated %s to config schema version %d (previous version saved as %s)", layer.path, configSchemaVersion, backup)
+		}
+	}
+
+	fc, meta, err := loadConfigFile(layer.path)
+	if err != nil {
+		return err
+	}
+	if fc == nil {
+		if layer.required {
+			return fmt.Errorf("config file %s not found", layer.path)
+		}
+		return nil
+	}
+	if layer.project {
+		for _, name := range fc.restrictProject() {
+			log.Printf("%s: ignoring [%s], which only the user's config can set", layer.path, name)
+		}
+	}
+	if errs := fc.validate(); len(errs) > 0 {
+		return fmt.Errorf("%s: %w", layer.path, errors.Join(errs...))
+	}
+	for _, key := range meta.Unknown {
+		log.Printf("%s: ignoring unknown setting %q", layer.path, key)
+	}
+
+	c.applyFile(fc, layer.source())
+	if len(fc.Presets) > 0 {
+		for _, w := range lintPresets(c.Presets) {
+			log.Printf("%s: %s", layer.path, w)
+		}
+	}
+	return nil
+}
+
+// envName returns the environment variable that overrides a setting key.
+func envName(key string) string {
+	return "GOBREW_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// loadEnv applies the GOBREW_* environment variables. Each value is checked
+// exactly like the same setting in a config file.
+func (c *Config) loadEnv(getenv func(string) string) error {
+	var errs []error
+	for _, key := range settingKeys {
+		name := envName(key)
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		fc, err := parseEnvSetting(key, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if ferrs := fc.validate(); len(ferrs) > 0 {
+			errs = append(errs, fmt.Errorf("%s: %w", name, errors.Join(ferrs...)))
+			continue
+		}
+		c.applyFile(fc, "env "+name)
+	}
+	return errors.Join(errs...)
+}
+
+// parseEnvSetting decodes a single environment value for a setting key by
+// reading it as the equivalent config file line, so the same validation and
+// error messages apply.
+func parseEnvSetting(key, value string) (*fileConfig, error) {
+	literal := strconv.Quote(value)
+	if boolSettings[key] {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", value)
+		}
+		literal = strconv.FormatBool(b)
+	}
+	if intSettings[key] {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", value)
+		}
+		literal = strconv.Itoa(n)
+	}
+
+	var fc fileConfig
+	if _, err := toml.Decode(key+" = "+literal, &fc); err != nil {
+		// Strip the line information, which is meaningless here
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, errors.New(perr.Message)
+		}
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// source returns where the effective value of a setting key came from.
+func (c *Config) source(key string) string {
+	if s, ok := c.Sources[key]; ok {
+		return s
+	}
+	return "default"
+}
+
+// settingValue formats the effective value of a setting key for display.
+func (c *Config) settingValue(key string) string {
+	switch key {
+	case "duration":
+		if !c.CustomDuration {
+			return "(preset)"
+		}
+		return c.BrewTime.String()
+	case "alerts.sound":
+		return strconv.FormatBool(c.SoundEnabled)
+	case "alerts.desktop":
+		return strconv.FormatBool(c.NotifyEnabled)
+	case "alerts.notification_sound":
+		return strconv.FormatBool(c.NotificationSound)
+	case "alerts.quiet_hours":
+		if c.QuietHours == "" {
+			return "off"
+		}
+		return string(c.QuietHours)
+	case "alerts.urgency":
+		return c.NotifyUrgency
+	case "alerts.remind":
+		if c.Remind == 0 {
+			return "off"
+		}
+		return c.Remind.String()
+	case "alerts.cold_after":
+		if c.ColdAfter == 0 {
+			return "off"
+		}
+		return c.ColdAfter.String()
+	case "alerts.expire":
+		if c.NotifyExpire == 0 {
+			return "default"
+		}
+		return c.NotifyExpire.String()
+	case "alerts.notify_start":
+		return strconv.FormatBool(c.NotifyStart)
+	case "alerts.notify_pause":
+		return strconv.FormatBool(c.NotifyPause)
+	case "alerts.notify_resume":
+		return strconv.FormatBool(c.NotifyResume)
+	case "alerts.speaker":
+		return c.Speaker
+	case "alerts.gpio_pin":
+		if c.GPIOPin < 0 {
+			return "off"
+		}
+		return strconv.Itoa(c.GPIOPin)
+	case "alerts.light.type":
+		if c.Light.Kind == "" {
+			return "off"
+		}
+		return c.Light.Kind
+	case "alerts.light.host":
+		return c.Light.Host
+	case "alerts.light.token":
+		// Keep the bridge credential out of shared terminal output
+		if c.Light.Token != "" {
+			return "(set)"
+		}
+		return ""
+	case "alerts.light.id":
+		return c.Light.ID
+	case "alerts.light.color":
+		return c.Light.Color
+	case "sync.type":
+		if c.Sync.Kind == "" {
+			return "off"
+		}
+		return c.Sync.Kind
+	case "sync.remote":
+		return c.Sync.Remote
+	case "sync.user":
+		return c.Sync.User
+	case "telemetry.endpoint":
+		if c.TelemetryEndpoint == "" {
+			return "off"
+		}
+		return c.TelemetryEndpoint
+	case "behavior.quick_start":
+		return strconv.FormatBool(c.QuickStart)
+	case "behavior.confirm_quit":
+		return strconv.FormatBool(c.ConfirmQuit)
+	case "behavior.confirm_duration":
+		return strconv.FormatBool(c.ConfirmDuration)
+	case "behavior.pause_on_suspend":
+		return strconv.FormatBool(c.PauseOnSuspend)
+	case "behavior.digit_entry":
+		return strconv.FormatBool(c.DigitEntry)
+	case "behavior.enter_starts":
+		if c.EnterStarts == "" {
+			return "off"
+		}
+		return c.EnterStarts
+	case "behavior.vessel":
+		if c.Vessel == "" {
+			return "none"
+		}
+		return c.Vessel
+	case "behavior.vessel_timing":
+		return strconv.FormatBool(c.VesselTiming)
+	case "cues.halfway":
+		return strconv.Itoa(c.HalfwayCue) + "%"
+	case "cues.final":
+		return c.FinalCue.String()
+	case "cues.sound":
+		return strconv.FormatBool(c.CueSound)
+	case "cooling.boil":
+		return strconv.Itoa(c.BoilTemp) + "°C"
+	case "cooling.room":
+		return strconv.Itoa(c.RoomTemp) + "°C"
+	case "cooling.half_life":
+		return c.CoolHalfLife.String()
+	case "history.max_entries":
+		if c.HistoryLimits.MaxEntries == 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(c.HistoryLimits.MaxEntries)
+	case "history.max_days":
+		if c.HistoryLimits.MaxDays == 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(c.HistoryLimits.MaxDays)
+	case "history.file":
+		return c.HistoryFile
+	case "history.encrypt":
+		return strconv.FormatBool(c.HistoryEncrypt)
+	case "history.keyfile":
+		if c.HistoryKeyfile == "" {
+			return "(passphrase from " + historyPassphraseEnv + ")"
+		}
+		return c.HistoryKeyfile
+	case "scale.device":
+		if c.ScaleDevice == "" {
+			return "off"
+		}
+		return c.ScaleDevice
+	case "overlay.file":
+		if c.OverlayFile == "" {
+			return "off"
+		}
+		return c.OverlayFile
+	case "hooks.url":
+		if c.HookURL == "" {
+			return "off"
+		}
+		return c.HookURL
+	case "display.time_format":
+		return string(c.TimeFormat)
+	case "display.locale":
+		if c.Locale == "" {
+			return "(from environment)"
+		}
+		return c.Locale
+	case "display.images":
+		return strconv.FormatBool(c.Images)
+	case "display.strength":
+		return strconv.FormatBool(c.ShowStrength)
+	case "display.emoji":
+		return strconv.FormatBool(c.Emoji)
+	case "display.taskbar_progress":
+		return strconv.FormatBool(c.TaskbarProgress)
+	case "display.tick_interval":
+		return c.TickInterval.String()
+	case "display.screensaver":
+		if c.Screensaver <= 0 {
+			return "off"
+		}
+		return c.Screensaver.String()
+	case "colors.theme":
+		return string(c.Theme)
+	case "colors.ready":
+		return c.Colors.Ready
+	case "colors.brewing":
+		return c.Colors.Brewing
+	case "colors.paused":
+		return c.Colors.Paused
+	case "colors.idle":
+		return c.Colors.Idle
+	case "colors.halfway":
+		return c.Colors.Halfway
+	case "colors.final":
+		return c.Colors.Final
+	case "keys.start":
+		return c.Keys.Start
+	case "keys.pause":
+		return c.Keys.Pause
+	case "keys.reset":
+		return c.Keys.Reset
+	case "keys.quit":
+		return c.Keys.Quit
+	case "keys.up":
+		return c.Keys.Up
+	case "keys.down":
+		return c.Keys.Down
+	case "keys.duration":
+		return c.Keys.Duration
+	case "keys.undo":
+		return c.Keys.Undo
+	case "keys.stopwatch":
+		return c.Keys.Stopwatch
+	case "keys.lap":
+		return c.Keys.Lap
+	case "keys.stats":
+		return c.Keys.Stats
+	case "keys.note":
+		return c.Keys.Note
+	case "keys.copy":
+		return c.Keys.Copy
+	case "keys.edit":
+		return c.Keys.Edit
+	case "keys.longer":
+		return c.Keys.Longer
+	case "keys.shorter":
+		return c.Keys.Shorter
+	case "keys.catalog":
+		return c.Keys.Catalog
+	case "keys.info":
+		return c.Keys.Info
+	case "keys.cool":
+		return c.Keys.Cool
+	case "keys.rate":
+		return c.Keys.Rate
+	case "keys.suggest":
+		return c.Keys.Suggest
+	case "keys.calc":
+		return c.Keys.Calc
+	case "keys.vessel":
+		return c.Keys.Vessel
+	case "keys.collect":
+		return c.Keys.Collect
+	}
+	return ""
+}
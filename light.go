@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Smart light kinds supported by LightConfig.
+const (
+	LightHue  = "hue"  // Philips Hue light behind a bridge
+	LightWLED = "wled" // WLED LED strip controller
+)
+
+// DefaultLightColor is the color a light blinks when none is configured.
+const DefaultLightColor = ColorReady
+
+// lightBlink is how long a light blinks before its previous state is
+// restored. It matches the Hue bridge's own long "lselect" alert.
+var lightBlink = 15 * time.Second
+
+// lightClient makes the requests to Hue bridges and WLED controllers, which
+// sit on the local network and answer quickly or not at all.
+var lightClient = &http.Client{Timeout: 5 * time.Second}
+
+// LightConfig describes a smart light that blinks when tea is ready.
+type LightConfig struct {
+	Kind  string // LightHue or LightWLED, empty to disable
+	Host  string // Address of the Hue bridge or WLED controller, e.g. 192.168.1.20
+	Token string // Hue bridge API username
+	ID    string // Number of the Hue light
+	Color string // Color to blink as "#RRGGBB"
+}
+
+// lightCmd blinks the configured light when a brew finishes. Failures are
+// retried a few times and then reported in the status line; cancellation cuts the blinking short and
+// restores the light straight away.
+func lightCmd(ctx context.Context, light LightConfig) tea.Cmd {
+	return func() tea.Msg {
+		err := retryAlert(ctx, light.Kind+" light alert", func() error {
+			switch light.Kind {
+			case LightHue:
+				return blinkHue(ctx, light)
+			case LightWLED:
+				return blinkWLED(ctx, light)
+			}
+			return fmt.Errorf("unknown light type %q", light.Kind)
+		})
+		if err != nil {
+			return errMsg{fmt.Errorf("%s light: %w", light.Kind, err)}
+		}
+		return nil
+	}
+}
+
+// blinkHue sets the Hue light to the alert color, runs the bridge's blink
+// effect and then restores the light's previous color and brightness.
+func blinkHue(ctx context.Context, light LightConfig) error {
+	url := fmt.Sprintf("http://%s/api/%s/lights/%s", light.Host, light.Token, light.ID)
+	var saved struct {
+		State map[string]any `json:"state"`
+	}
+	if err := lightRequest(ctx, http.MethodGet, url, nil, &saved); err != nil {
+		return err
+	}
+
+	r, g, b := hexRGB(light.Color)
+	x, y := rgbToXY(r, g, b)
+	alert := map[string]any{"on": true, "bri": 254, "xy": []float64{x, y}, "alert": "lselect"}
+	if err := lightRequest(ctx, http.MethodPut, url+"/state", alert, nil); err != nil {
+		return err
+	}
+	waitBlink(ctx)
+
+	// Put back only what the alert changed, in the light's own color mode
+	restore := map[string]any{"alert": "none"}
+	for _, key := range []string{"on", "bri"} {
+		if v, ok := saved.State[key]; ok {
+			restore[key] = v
+		}
+	}
+	switch saved.State["colormode"] {
+	case "ct":
+		restore["ct"] = saved.State["ct"]
+	case "hs":
+		restore["hue"], restore["sat"] = saved.State["hue"], saved.State["sat"]
+	case "xy":
+		restore["xy"] = saved.State["xy"]
+	}
+	return lightRequest(context.Background(), http.MethodPut, url+"/state", restore, nil)
+}
+
+// blinkWLED runs WLED's blink effect in the alert color and then sends back
+// the state read before, which WLED accepts as it is.
+func blinkWLED(ctx context.Context, light LightConfig) error {
+	url := fmt.Sprintf("http://%s/json/state", light.Host)
+	var saved json.RawMessage
+	if err := lightRequest(ctx, http.MethodGet, url, nil, &saved); err != nil {
+		return err
+	}
+
+	r, g, b := hexRGB(light.Color)
+	alert := map[string]any{
+		"on":  true,
+		"bri": 255,
+		"seg": []map[string]any{{"col": [][]int{{r, g, b}}, "fx": 1}}, // Effect 1 is "Blink"
+	}
+	if err := lightRequest(ctx, http.MethodPost, url, alert, nil); err != nil {
+		return err
+	}
+	waitBlink(ctx)
+	return lightRequest(context.Background(), http.MethodPost, url, saved, nil)
+}
+
+// waitBlink waits for lightBlink or until ctx is cancelled.
+func waitBlink(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(lightBlink):
+	}
+}
+
+// lightRequest sends body as JSON to url and decodes the response into out,
+// if given. The Hue bridge answers errors with status 200 and a list of
+// error objects, which are turned into an error here.
+func lightRequest(ctx context.Context, method, url string, body, out any) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := lightClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	var hueErrors []struct {
+		Error *struct {
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(data, &hueErrors) == nil {
+		for _, e := range hueErrors {
+			if e.Error != nil {
+				return fmt.Errorf("bridge: %s", e.Error.Description)
+			}
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// hexRGB splits a "#RRGGBB" or "#RGB" color into its components. Invalid
+// colors, which the config file rejects, come out black.
+func hexRGB(color string) (r, g, b int) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return 0, 0, 0
+	}
+	return int(v >> 16 & 0xFF), int(v >> 8 & 0xFF), int(v & 0xFF)
+}
+
+// rgbToXY converts an sRGB color to the CIE xy coordinates Hue lights are
+// set with, following Philips' wide gamut conversion.
+func rgbToXY(r, g, b int) (x, y float64) {
+	linear := func(c int) float64 {
+		v := float64(c) / 255
+		if v > 0.04045 {
+			return math.Pow((v+0.055)/1.055, 2.4)
+		}
+		return v / 12.92
+	}
+	rl, gl, bl := linear(r), linear(g), linear(b)
+	X := rl*0.664511 + gl*0.154324 + bl*0.162028
+	Y := rl*0.283881 + gl*0.668433 + bl*0.047685
+	Z := rl*0.000088 + gl*0.072310 + bl*0.986039
+	if sum := X + Y + Z; sum > 0 {
+		return math.Round(X/sum*10000) / 10000, math.Round(Y/sum*10000) / 10000
+	}
+	return 0, 0
+}
+
+// validate checks that an enabled light has everything needed to reach it.
+// The settings may come from different config layers, so this runs on the
+// merged configuration.
+func (l LightConfig) validate() error {
+	switch {
+	case l.Kind == "":
+		return nil
+	case l.Host == "":
+		return fmt.Errorf("alerts.light.host is required for a %s light", l.Kind)
+	case l.Kind == LightHue && (l.Token == "" || l.ID == ""):
+		return fmt.Errorf("alerts.light.token and alerts.light.id are required for a hue light")
+	}
+	return nil
+}
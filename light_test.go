@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLight records the requests made to a fake Hue bridge or WLED
+// controller and answers GETs with state.
+type fakeLight struct {
+	mu       sync.Mutex
+	state    string
+	requests []string // "METHOD path body"
+}
+
+func (f *fakeLight) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	json.NewDecoder(r.Body).Decode(&body)
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path+" "+string(body))
+	f.mu.Unlock()
+	if r.Method == http.MethodGet {
+		w.Write([]byte(f.state))
+		return
+	}
+	w.Write([]byte(`[{"success":{}}]`))
+}
+
+func TestBlinkHue(t *testing.T) {
+	defer func(d time.Duration) { lightBlink = d }(lightBlink)
+	lightBlink = time.Millisecond
+
+	bridge := &fakeLight{state: `{"state":{"on":false,"bri":100,"colormode":"ct","ct":366}}`}
+	srv := httptest.NewServer(bridge)
+	defer srv.Close()
+
+	light := LightConfig{Kind: LightHue, Host: strings.TrimPrefix(srv.URL, "http://"), Token: "abc", ID: "3", Color: "#FF0000"}
+	if msg := lightCmd(context.Background(), light)(); msg != nil {
+		t.Fatalf("Expected the light to blink, got %v", msg)
+	}
+	if len(bridge.requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %v", bridge.requests)
+	}
+	if got := bridge.requests[1]; !strings.HasPrefix(got, "PUT /api/abc/lights/3/state") || !strings.Contains(got, `"alert":"lselect"`) || !strings.Contains(got, `"xy":[0.7006,0.2993]`) {
+		t.Errorf("Expected red lselect alert, got %s", got)
+	}
+	if got := bridge.requests[2]; !strings.Contains(got, `"ct":366`) || !strings.Contains(got, `"on":false`) {
+		t.Errorf("Expected previous state to be restored, got %s", got)
+	}
+}
+
+func TestBlinkWLED(t *testing.T) {
+	defer func(d time.Duration) { lightBlink = d }(lightBlink)
+	lightBlink = time.Millisecond
+
+	strip := &fakeLight{state: `{"on":true,"bri":80}`}
+	srv := httptest.NewServer(strip)
+	defer srv.Close()
+
+	light := LightConfig{Kind: LightWLED, Host: strings.TrimPrefix(srv.URL, "http://"), Color: "#0F0"}
+	if msg := lightCmd(context.Background(), light)(); msg != nil {
+		t.Fatalf("Expected the light to blink, got %v", msg)
+	}
+	if len(strip.requests) != 3 || !strings.Contains(strip.requests[1], `"col":[[0,255,0]]`) {
+		t.Fatalf("Expected a green blink, got %v", strip.requests)
+	}
+	if got := strip.requests[2]; got != `POST /json/state {"on":true,"bri":80}` {
+		t.Errorf("Expected previous state to be restored, got %s", got)
+	}
+}
+
+func TestHueBridgeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"error":{"type":1,"description":"unauthorized user"}}]`))
+	}))
+	defer srv.Close()
+
+	light := LightConfig{Kind: LightHue, Host: strings.TrimPrefix(srv.URL, "http://"), Token: "bad", ID: "1"}
+	msg, ok := lightCmd(context.Background(), light)().(errMsg)
+	if !ok || !strings.Contains(msg.err.Error(), "unauthorized user") {
+		t.Errorf("Expected the bridge error to be reported, got %v", msg)
+	}
+}
+
+func TestLightConfigValidate(t *testing.T) {
+	tests := []struct {
+		light LightConfig
+		ok    bool
+	}{
+		{LightConfig{}, true},
+		{LightConfig{Kind: LightWLED, Host: "wled.local"}, true},
+		{LightConfig{Kind: LightWLED}, false},
+		{LightConfig{Kind: LightHue, Host: "10.0.0.2", ID: "1"}, false},
+		{LightConfig{Kind: LightHue, Host: "10.0.0.2", Token: "abc", ID: "1"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.light.validate(); (err == nil) != tt.ok {
+			t.Errorf("validate(%+v) = %v, want ok %v", tt.light, err, tt.ok)
+		}
+	}
+}
+
+func TestConfigShowHidesLightToken(t *testing.T) {
+	path := writeConfig(t, "[alerts.light]\ntype = \"hue\"\nhost = \"192.168.1.2\"\ntoken = \"s3cr3t-bridge-user\"\nid = 3\n")
+	var stdout, stderr strings.Builder
+	if code := runConfigCommand([]string{"show", "-config", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected config show to succeed, got %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); strings.Contains(out, "s3cr3t-bridge-user") || !strings.Contains(out, `token = "(set)"`) {
+		t.Errorf("Expected the token masked, got:\n%s", out)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localeFormats holds how clock times, dates and temperatures are written
+// for one locale. Month and weekday names stay English, so locales that
+// would spell them differently get all-numeric dates instead.
+type localeFormats struct {
+	Name       string // Locale the formats were chosen for, such as "de_DE"
+	Clock      string // Layout of wall-clock times
+	Date       string // Layout of full dates
+	DayMonth   string // Layout of dates within the year
+	Fahrenheit bool   // Write temperatures in °F rather than °C
+}
+
+// defaultLocale is used for the C locale and for locales go-brew doesn't
+// know. It writes times and dates the way go-brew always has.
+var defaultLocale = localeFormats{Clock: "15:04", Date: "Jan 2, 2006", DayMonth: "Jan 2"}
+
+// locales maps a language, or a language and region, to its formats. A
+// language_REGION entry wins over the language alone.
+var locales = map[string]localeFormats{
+	"en_US": {Clock: "3:04 PM", Date: "Jan 2, 2006", DayMonth: "Jan 2"},
+	"en_CA": {Clock: "3:04 PM", Date: "Jan 2, 2006", DayMonth: "Jan 2"},
+	"en_GB": {Clock: "15:04", Date: "2 Jan 2006", DayMonth: "2 Jan"},
+	"en_IE": {Clock: "15:04", Date: "2 Jan 2006", DayMonth: "2 Jan"},
+	"en_AU": {Clock: "3:04 pm", Date: "2 Jan 2006", DayMonth: "2 Jan"},
+	"en_NZ": {Clock: "3:04 pm", Date: "2 Jan 2006", DayMonth: "2 Jan"},
+	"de":    {Clock: "15:04", Date: "2.1.2006", DayMonth: "2.1."},
+	"da":    {Clock: "15:04", Date: "2.1.2006", DayMonth: "2.1."},
+	"nb":    {Clock: "15:04", Date: "2.1.2006", DayMonth: "2.1."},
+	"fi":    {Clock: "15:04", Date: "2.1.2006", DayMonth: "2.1."},
+	"pl":    {Clock: "15:04", Date: "02.01.2006", DayMonth: "02.01"},
+	"ru":    {Clock: "15:04", Date: "02.01.2006", DayMonth: "02.01"},
+	"fr":    {Clock: "15:04", Date: "02/01/2006", DayMonth: "02/01"},
+	"es":    {Clock: "15:04", Date: "02/01/2006", DayMonth: "02/01"},
+	"it":    {Clock: "15:04", Date: "02/01/2006", DayMonth: "02/01"},
+	"pt":    {Clock: "15:04", Date: "02/01/2006", DayMonth: "02/01"},
+	"nl":    {Clock: "15:04", Date: "2-1-2006", DayMonth: "2-1"},
+	"sv":    {Clock: "15:04", Date: "2006-01-02", DayMonth: "2/1"},
+	"ja":    {Clock: "15:04", Date: "2006/01/02", DayMonth: "01/02"},
+	"zh":    {Clock: "15:04", Date: "2006/01/02", DayMonth: "01/02"},
+	"ko":    {Clock: "15:04", Date: "2006. 1. 2.", DayMonth: "1. 2."},
+}
+
+// fahrenheitRegions are the countries that give temperatures in °F.
+var fahrenheitRegions = map[string]bool{"US": true, "LR": true, "BS": true, "BZ": true, "KY": true, "PW": true}
+
+// envLocale returns the locale the environment asks times to be written in:
+// LC_ALL, then LC_TIME, then LANG, as setlocale would pick it.
+func envLocale(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// lookupLocale returns the formats for a locale name such as "de_DE.UTF-8",
+// "en-GB" or "C". Unknown locales fall back to their language, then to
+// defaultLocale.
+func lookupLocale(name string) localeFormats {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	lang, region, _ := strings.Cut(strings.ReplaceAll(name, "-", "_"), "_")
+	lang, region = strings.ToLower(lang), strings.ToUpper(region)
+	if lang == "" || lang == "c" || lang == "posix" {
+		return defaultLocale
+	}
+
+	tag := lang
+	if region != "" {
+		tag += "_" + region
+	}
+	f, ok := locales[tag]
+	if !ok {
+		if f, ok = locales[lang]; !ok {
+			f = defaultLocale
+		}
+	}
+	f.Name = tag
+	f.Fahrenheit = fahrenheitRegions[region]
+	return f
+}
+
+// locale returns the formats for the display.locale setting, or for the
+// environment's locale when the setting is empty.
+func (c *Config) locale(getenv func(string) string) localeFormats {
+	if c.Locale != "" {
+		return lookupLocale(c.Locale)
+	}
+	return lookupLocale(envLocale(getenv))
+}
+
+// clock formats t as a wall-clock time, such as "14:05" or "2:05 PM".
+func (f localeFormats) clock(t time.Time) string {
+	return t.Format(f.Clock)
+}
+
+// date formats t as a full date, such as "Mar 1, 2024" or "1.3.2024".
+func (f localeFormats) date(t time.Time) string {
+	return t.Format(f.Date)
+}
+
+// dayMonth formats t as a date within the year, such as "Mar 1" or "1.3.".
+func (f localeFormats) dayMonth(t time.Time) string {
+	return t.Format(f.DayMonth)
+}
+
+// temp formats a temperature given in °C, rounded to a whole degree.
+func (f localeFormats) temp(celsius float64) string {
+	if f.Fahrenheit {
+		return fmt.Sprintf("%.0f°F", celsius*9/5+32)
+	}
+	return fmt.Sprintf("%.0f°C", celsius)
+}
+
+// celsiusPattern matches a temperature or range given in °C within a
+// preset's Temp, such as "80°C" or "70-80°C".
+var celsiusPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)(?:(\s*[–-]\s*)(\d+(?:\.\d+)?))?\s*°\s*C`)
+
+// presetTemp rewrites the °C temperatures in a preset's free-form Temp in
+// °F where the locale wants them. Other text is left as written.
+func (f localeFormats) presetTemp(temp string) string {
+	if !f.Fahrenheit {
+		return temp
+	}
+	toF := func(s string) string {
+		c, _ := strconv.ParseFloat(s, 64)
+		return strconv.Itoa(int(math.Round(c*9/5 + 32)))
+	}
+	return celsiusPattern.ReplaceAllStringFunc(temp, func(s string) string {
+		match := celsiusPattern.FindStringSubmatch(s)
+		if match[3] == "" {
+			return toF(match[1]) + "°F"
+		}
+		return toF(match[1]) + match[2] + toF(match[3]) + "°F"
+	})
+}
+
+// commandLocale returns the formats for a subcommand's -locale flag or,
+// when that is empty, for display.locale and the environment as the timer
+// would use them. A broken config file is left for the timer to report.
+func commandLocale(name string) localeFormats {
+	if name != "" {
+		return lookupLocale(name)
+	}
+	config := NewConfig()
+	_ = config.Load()
+	return config.locale(os.Getenv)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	at := time.Date(2024, 3, 1, 14, 5, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name, clock, date string
+		fahrenheit        bool
+	}{
+		{"", "14:05", "Mar 1, 2024", false},
+		{"C.UTF-8", "14:05", "Mar 1, 2024", false},
+		{"en_US.UTF-8", "2:05 PM", "Mar 1, 2024", true},
+		{"en-GB", "14:05", "1 Mar 2024", false},
+		{"de_AT.UTF-8@euro", "14:05", "1.3.2024", false},
+		{"fr_CA", "14:05", "01/03/2024", false},
+		{"xx_YY", "14:05", "Mar 1, 2024", false},
+	} {
+		f := lookupLocale(tc.name)
+		if got := f.clock(at); got != tc.clock {
+			t.Errorf("Expected %q to write the time as %q, got %q", tc.name, tc.clock, got)
+		}
+		if got := f.date(at); got != tc.date {
+			t.Errorf("Expected %q to write the date as %q, got %q", tc.name, tc.date, got)
+		}
+		if f.Fahrenheit != tc.fahrenheit {
+			t.Errorf("Expected %q to use Fahrenheit=%v", tc.name, tc.fahrenheit)
+		}
+	}
+}
+
+func TestConfigLocale(t *testing.T) {
+	env := map[string]string{"LANG": "de_DE.UTF-8", "LC_TIME": "en_GB.UTF-8"}
+	getenv := func(name string) string { return env[name] }
+
+	config := NewConfig()
+	if got := config.locale(getenv).Name; got != "en_GB" {
+		t.Errorf("Expected LC_TIME to win over LANG, got %q", got)
+	}
+	env["LC_ALL"] = "ja_JP.UTF-8"
+	if got := config.locale(getenv).Name; got != "ja_JP" {
+		t.Errorf("Expected LC_ALL to win over LC_TIME, got %q", got)
+	}
+	config.Locale = "en_US"
+	if got := config.locale(getenv).Name; got != "en_US" {
+		t.Errorf("Expected display.locale to win over the environment, got %q", got)
+	}
+}
+
+func TestLocaleTemperatures(t *testing.T) {
+	us := lookupLocale("en_US")
+	if got := us.temp(80); got != "176°F" {
+		t.Errorf("Expected 80°C as 176°F, got %q", got)
+	}
+	if got := us.presetTemp("70-80°C, off the boil"); got != "158-176°F, off the boil" {
+		t.Errorf("Expected the range converted to °F, got %q", got)
+	}
+	if got := us.presetTemp("175°F"); got != "175°F" {
+		t.Errorf("Expected a °F temperature to stay as written, got %q", got)
+	}
+	if got := lookupLocale("de_DE").presetTemp("95°C"); got != "95°C" {
+		t.Errorf("Expected °C to stay in Germany, got %q", got)
+	}
+}
+
+func TestLocaleInView(t *testing.T) {
+	config := NewConfig()
+	config.Formats = lookupLocale("en_US")
+	m := initialModel(config)
+	if label := m.readyAtLabel(); label != "ready at 2:04 PM" {
+		t.Errorf("Expected a 12-hour ready time, got %q", label)
+	}
+	if view := m.View(); !strings.Contains(view, "Rooibos (203°F)") {
+		t.Errorf("Expected the preset temperature in °F, got:\n%s", view)
+	}
+}
+
+func TestReportLocale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	if err := appendHistory(path, nil, brewRecord{Time: now(), Tea: "Oolong", Duration: Duration{3 * time.Minute}}); err != nil {
+		t.Fatalf("Failed to append history: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runReportCommand([]string{"-markdown", "-locale", "de_DE", "-history", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "| Fri 1.3. | 1 |") {
+		t.Errorf("Expected German day labels, got:\n%s", stdout.String())
+	}
+}
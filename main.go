@@ -13,8 +13,31 @@
 //   - Responsive design that adapts to terminal size
 //
 // Usage:
-//   go run .                    # Run with default settings
-//   go run . -duration 2m       # Run with 2-minute timer
+//   go run .                     # Run with default settings
+//   go run . -duration 2m        # Run with 2-minute timer
+//   go run . -cpuprofile cpu.out # Profile a brewing session
+//   go run . -exit-on-finish 10s # Quit 10 seconds after the tea is ready
+//   go run . -sequence steps.txt # Run a list of timers one after another
+//   go run . -recipe matcha      # Whisk matcha with step-by-step instructions
+//   go run . -stopwatch          # Count up with laps instead of down
+//   go run . -at 14:45           # Count down to a wall-clock time
+//   go run . -blind Herbal,Oolong # Brew one of two teas blind, revealed once rated
+//   go run . config validate     # Check the config file for errors
+//   go run . config show         # Print the effective configuration
+//   go run . config sources      # Show where each setting came from
+//   go run . presets lint        # Warn about suspicious preset definitions
+//   go run . report -week        # Summarise the last 7 days of brews
+//   go run . stats export --svg  # Draw the brew stats as a shareable graphic
+//   go run . stats export --ics  # Export the brews as calendar events
+//   go run . history prune -dry-run # Show which old brews the retention limits drop
+//   go run . history encrypt     # Encrypt the brew log with history.keyfile or a passphrase
+//   go run . history import log.csv # Add brews from a CSV or Steepster-style export
+//   go run . telemetry status    # Show what opt-in usage counts collect
+//   go run . sync                # Share presets and history via Git or WebDAV
+//   go run . -record bug.jsonl   # Record a session to reproduce a display bug
+//   go run . replay bug.jsonl    # Play a recorded session back
+//   go run . -plain              # Print the countdown as plain lines for logs and CI
+//   go run . -kiosk              # Large timer for a shared kitchen display, no quitting
 //
 // Key controls:
 //   s, space     - Start/pause timer
@@ -24,8 +47,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -33,10 +59,22 @@ import (
 // Simple version information
 const version = "1.0.0"
 
-// Init initializes the Bubbletea program with no initial commands.
+// Init initializes the Bubbletea program. It starts the minute clock that
+// keeps the "ready at" time current and, for an alarm given with -at, starts
+// counting down right away.
 // This is called once when the program starts and sets up the initial state.
 func (m model) Init() tea.Cmd {
-	return nil
+	// The brew log is read up front for the duration suggestions
+	var history tea.Cmd
+	if m.config.HistoryFile != "" {
+		history = loadHistoryCmd(m.config.HistoryFile, m.config.HistoryKey)
+	}
+	// Brew events left undelivered last time get another try
+	hooks := m.flushHooksCmd()
+	if !m.alarmAt.IsZero() {
+		return tea.Batch(clockTick(), history, hooks, func() tea.Msg { return startMsg{} })
+	}
+	return tea.Batch(clockTick(), history, hooks)
 }
 
 // printVersion prints version information and exits
@@ -44,10 +82,50 @@ func printVersion() {
 	fmt.Printf("go-brew %s\n", version)
 }
 
+// openLogFile directs the standard logger to go-brew.log in the platform's
+// log directory.
+func openLogFile() (*os.File, error) {
+	dir := dirs().Log
+	if err := ensureDir(dir); err != nil {
+		return nil, err
+	}
+	return tea.LogToFile(filepath.Join(dir, "go-brew.log"), "")
+}
+
 // main is the entry point of the Go Brew CLI application.
 // It sets up the configuration, validates it, and starts the Bubbletea TUI program.
 // The program runs in alternate screen mode for a full terminal experience.
 func main() {
+	// Subcommands are handled before the timer's own flags are parsed
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		os.Exit(runReportCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		os.Exit(runStatsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		os.Exit(runHistoryCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "presets" {
+		os.Exit(runPresetsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "telemetry" {
+		os.Exit(runTelemetryCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	// Measures rendering for performance work; left out of -help on purpose
+	if len(os.Args) > 1 && os.Args[1] == "--bench-render" {
+		os.Exit(runRenderBenchmark(os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		os.Exit(runSyncCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplayCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	config := NewConfig()
 	config.ParseFlags()
 
@@ -57,13 +135,154 @@ func main() {
 		return
 	}
 
+	// Merge config files and environment below any command line flags
+	if err := config.Load(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	p := tea.NewProgram(initialModel(config), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	// Resolve the alarm time now so typos are reported before the TUI starts
+	if config.AlarmTime != "" {
+		at, err := parseAlarmTime(config.AlarmTime, now())
+		if err != nil {
+			log.Fatalf("Invalid -at: %v", err)
+		}
+		config.AlarmAt = at
+	}
+
+	// Times, dates and temperatures follow display.locale or the environment
+	config.Formats = config.locale(os.Getenv)
+
+	// An encrypted brew log needs its key before the first brew is saved
+	key, err := config.historyKey(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	config.HistoryKey = key
+
+	// Taskbar progress needs a terminal that won't mistake it for a notification
+	config.TaskbarTerminal = detectTaskbarProgress(os.Getenv)
+
+	// Tea pictures need a terminal that speaks an image protocol
+	if config.Images {
+		config.ImageProtocol = detectImageProtocol(os.Getenv)
+	}
+
+	// Usage counts are strictly opt-in: ask once, and count nothing until then
+	config.TelemetryPath = defaultTelemetryPath()
+	if state, err := loadTelemetry(config.TelemetryPath); err != nil {
+		log.Printf("Usage counts disabled: %v", err)
+		config.TelemetryPath = ""
+	} else {
+		config.Telemetry = state.Enabled
+		config.AskTelemetry = !state.Asked && !config.Plain
+	}
+
+	// Brew events the webhook missed are kept for another try
+	config.HookQueuePath = defaultHookQueuePath()
+
+	// Plain output has no one at the keyboard to quit once the tea is ready
+	if config.Plain && config.ExitOnFinish == 0 {
+		config.ExitOnFinish = plainExitDelay
+	}
+
+	// Load the timer sequence once the presets it may refer to are known
+	if config.SequenceFile != "" {
+		steps, err := loadSequence(config.SequenceFile, os.Stdin, config.Presets)
+		if err != nil {
+			log.Fatalf("Invalid sequence: %v", err)
+		}
+		config.Sequence = steps
+	}
+	if config.Recipe != "" {
+		steps, err := recipeSteps(config.Recipe)
+		if err != nil {
+			log.Fatalf("Invalid -recipe: %v", err)
+		}
+		config.Sequence = steps
+	}
+	if config.Kiosk {
+		presets, err := kioskPresets(config.KioskPresets, config.Presets)
+		if err != nil {
+			log.Fatalf("Invalid kiosk.presets: %v", err)
+		}
+		config.Presets = presets
+		config.KeyBindings = config.bindings()
+	}
+	if config.BlindTest != "" {
+		blind, err := parseBlindTest(config.BlindTest, config.Presets)
+		if err != nil {
+			log.Fatalf("Invalid -blind: %v", err)
+		}
+		config.Blind = blind
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithReportFocus()}
+	var root tea.Model = initialModel(config)
+	if config.Plain {
+		// Print lines instead of drawing; keys can't be read from a log.
+		// Validate has already refused -record here, as there is no UI to
+		// replay.
+		root = newPlainPrinter(initialModel(config), os.Stdout)
+		opts = []tea.ProgramOption{tea.WithoutRenderer(), tea.WithInput(nil)}
+	} else if config.SequenceFile == "-" {
+		// Stdin held the sequence, so read keys from the terminal instead
+		opts = append(opts, tea.WithInputTTY())
+	}
+
+	// A recording logs every message, for reproducing display bugs on
+	// terminals the maintainers don't have
+	if config.RecordFile != "" {
+		f, err := os.Create(config.RecordFile)
+		if err != nil {
+			log.Fatalf("Failed to start recording: %v", err)
+		}
+		defer f.Close()
+		if root, err = newRecorder(initialModel(config), f, os.Args[1:], os.Getenv); err != nil {
+			log.Fatalf("Failed to start recording: %v", err)
+		}
+	}
+
+	stopProfiling, err := startProfiling(config)
+	if err != nil {
+		log.Fatalf("Failed to start profiling: %v", err)
+	}
+	defer stopProfiling()
+
+	// Log to a file while the TUI owns the terminal, so messages from
+	// failed alerts don't scribble over the interface. Startup errors above
+	// still reach stderr.
+	if logFile, err := openLogFile(); err != nil {
+		log.Printf("Logging to stderr: %v", err)
+	} else {
+		defer logFile.Close()
+	}
+	p := tea.NewProgram(root, opts...)
+	watchContinue(p)
+	if config.ScaleDevice != "" && !config.Plain {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go watchScale(ctx, config.ScaleDevice, p.Send)
+	}
+	if config.acknowledging() && config.NotifyEnabled && !config.Plain {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go watchNotifyActions(ctx, p.Send)
+	}
+	final, err := p.Run()
+	if err != nil {
 		log.Printf("Error running program: %v", err)
 	}
+	if err := abandonOnExit(final); err != nil {
+		log.Print(err)
+	}
+	clearOverlay(config)
+	// Quitting mid-brew would leave the taskbar showing its progress
+	if config.TaskbarProgress && config.TaskbarTerminal && !config.Plain {
+		fmt.Print(taskbarSequence(taskbarClear, 0))
+	}
 }
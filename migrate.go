@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configSchemaVersion is the version of the config file layout written and
+// understood by this release. Files without a version key are version 1.
+const configSchemaVersion = 2
+
+// migration upgrades a decoded config file by one schema version in place and
+// reports whether it had anything to change.
+type migration func(raw map[string]any) (bool, error)
+
+// migrations maps each schema version to the step that upgrades it to the
+// next version. Every version below configSchemaVersion needs an entry.
+var migrations = map[int]migration{
+	1: migrateV1toV2,
+}
+
+// migrateV1toV2 moves the top-level "sound" and "notify" switches into the
+// [alerts] table, where the other alert settings live.
+func migrateV1toV2(raw map[string]any) (bool, error) {
+	sound, hasSound := raw["sound"]
+	notify, hasNotify := raw["notify"]
+	if !hasSound && !hasNotify {
+		return false, nil
+	}
+
+	alerts, ok := raw["alerts"].(map[string]any)
+	if !ok {
+		if _, exists := raw["alerts"]; exists {
+			return false, fmt.Errorf("alerts must be a table")
+		}
+		alerts = map[string]any{}
+		raw["alerts"] = alerts
+	}
+	if hasSound {
+		alerts["sound"] = sound
+		delete(raw, "sound")
+	}
+	if hasNotify {
+		alerts["desktop"] = notify
+		delete(raw, "notify")
+	}
+	return true, nil
+}
+
+// configVersion returns the schema version recorded in a decoded config file.
+func configVersion(raw map[string]any) (int, error) {
+	v, ok := raw["version"]
+	if !ok {
+		return 1, nil
+	}
+	n, ok := v.(int64)
+	if !ok || n < 1 {
+		return 0, fmt.Errorf("version must be a positive integer, got %v", v)
+	}
+	return int(n), nil
+}
+
+// upgradeConfig brings config file contents up to configSchemaVersion. It
+// returns the original data unchanged when no migration is needed, along with
+// the version the data was written in. A file without a version key that uses
+// none of the old settings is already current and is reported as such, so
+// hand-written files keep their line numbers in error messages. Files from a
+// newer release are rejected rather than guessed at, so settings are never
+// silently dropped.
+func upgradeConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]any
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, 0, err
+	}
+	from, err := configVersion(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from == configSchemaVersion {
+		return data, from, nil
+	}
+	if from > configSchemaVersion {
+		return nil, from, fmt.Errorf("config schema version %d is newer than this go-brew supports (%d); please upgrade", from, configSchemaVersion)
+	}
+
+	changed := false
+	for v := from; v < configSchemaVersion; v++ {
+		c, err := migrations[v](raw)
+		if err != nil {
+			return nil, from, fmt.Errorf("migrate config from version %d: %w", v, err)
+		}
+		changed = changed || c
+	}
+	if !changed {
+		return data, configSchemaVersion, nil
+	}
+	raw["version"] = configSchemaVersion
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(raw); err != nil {
+		return nil, from, err
+	}
+	return buf.Bytes(), from, nil
+}
+
+// migrateConfigFile rewrites the config file at path in the current schema
+// if it was written by an older release. The original is kept next to it as
+// <path>.v<N>.bak so nothing is lost if the migration misses a customization.
+// It returns the backup path, or "" when the file was already current or
+// does not exist.
+func migrateConfigFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	upgraded, from, err := upgradeConfig(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if from == configSchemaVersion {
+		return "", nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if _, err := os.Stat(backup); err == nil {
+		// Never overwrite an earlier backup of the same version
+		backup = fmt.Sprintf("%s.v%d.%d.bak", path, from, os.Getpid())
+	}
+	if err := os.WriteFile(backup, data, 0o600); err != nil {
+		return "", fmt.Errorf("back up config before migration: %w", err)
+	}
+
+	// Write through a temporary file so a crash cannot leave half a config
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, upgraded, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return backup, nil
+}
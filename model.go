@@ -1,33 +1,121 @@
 package main
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
 
 // tickMsg is a Bubbletea message type that represents timer tick events.
-// It wraps time.Time to provide timing information for timer updates.
-type tickMsg time.Time
+// Each tick carries the ID of the tick chain that scheduled it so that ticks
+// left over from a paused or reset brew can be recognised and dropped.
+type tickMsg struct {
+	id   int       // Tick chain the message belongs to
+	time time.Time // Time at which the tick fired
+}
 
 // model represents the complete application state for the Go Brew CLI.
 // It contains all data needed to render the UI and handle user interactions,
 // following the Model-View-Update architecture pattern.
 type model struct {
-	config    *Config      // Application configuration and settings
-	timer     time.Duration // Current remaining time on the timer
-	state     TimerState   // Current state of the timer (idle, brewing, paused, finished)
-	presetIdx int          // Index of the currently selected tea preset
-	width     int          // Terminal width for responsive UI layout
-	height    int          // Terminal height for responsive UI layout
+	config       *Config            // Application configuration and settings
+	timer        time.Duration      // Current remaining time on the timer
+	state        TimerState         // Current state of the timer (idle, brewing, paused, finished)
+	presetIdx    int                // Index of the currently selected tea preset
+	tickID       int                // ID of the active tick chain; stale ticks are ignored
+	cache        *viewCache         // Last rendered frame, shared between model copies
+	stopAlert    context.CancelFunc // Stops the in-flight alert sound, if any
+	notifyFailed bool               // Whether the last desktop notification failed
+	soundFailed  bool               // Whether the last alert sound failed
+	status       string             // Transient status line, empty when hidden
+	statusID     int                // ID of the current status line's timeout
+	width        int                // Terminal width for responsive UI layout
+	height       int                // Terminal height for responsive UI layout
+
+	customDuration time.Duration   // Duration typed in the TUI for the next brew, 0 if none
+	inputKind      inputKind       // What the open text input asks for, inputNone if closed
+	input          textinput.Model // Text input for prompts such as the custom duration
+	brewName       string          // Name given to the last custom-duration brew
+	brewTea        string          // Tea recorded in the history for the running brew
+	brewUUID       string          // ID of the running or last brew, for its history record and events
+	hookRetrying   bool            // Whether another delivery of the webhook queue is scheduled
+	readySince     time.Time       // When the finished brew became ready, for its reminders and going cold
+	collected      bool            // Whether the finished brew was acknowledged as collected
+	cold           bool            // Whether the finished brew went cold before it was collected
+	note           string          // Free-text note on the current brew, saved to the history
+	undo           []undoEntry     // Timer states saved before undoable actions, newest last
+	quitArmed      bool            // Whether the next quit key quits despite a running brew
+	startArmed     bool            // Whether the next start key brews despite an unusual duration
+	suspendedAt    time.Time       // When go-brew was last suspended with ctrl+z
+	suspendPaused  bool            // Whether the brew was paused for the suspension and resumes with it
+	unfocused      bool            // Whether the terminal window has lost focus
+	step           int             // Index of the current step of a -sequence
+	stopwatch      bool            // Whether the timer counts up as a stopwatch
+	laps           []time.Duration // Elapsed times recorded as stopwatch laps
+	alarmAt        time.Time       // Wall-clock time the next brew counts down to, zero if none
+	deadline       time.Time       // Wall-clock time the running brew finishes, or the stopwatch read zero
+	clock          time.Time       // Wall-clock time as of the last clock update
+	title          string          // Terminal window title last set
+	overlay        string          // Content last written to the overlay file
+	overlayFailed  bool            // Whether writing the overlay file failed, which stops further writes
+	adjustKey      string          // Adjust key pressed last, to detect it being held
+	adjustAt       time.Time       // When the adjust key was last pressed
+	adjustRepeats  int             // Repeats of the held adjust key so far
+	digits         string          // Digits typed for a direct duration entry
+	infusion       int             // Index of the next infusion of a preset with steeps
+	screen         screen          // Whether the timer or the stats screen is shown
+	history        []brewRecord    // Brew log as last read for the stats screen
+	historyQuery   string          // Filter applied to the brews on the stats screen
+	summary        *historyStats   // Stats worked out by a store that can, nil if it can't
+	search         textinput.Model // Search typed on the catalog screen
+	catalogIdx     int             // Highlighted match on the catalog screen
+	calcPreset     int             // Preset the calculator screen works out leaf for
+	calcMl         string          // Cup size typed on the calculator screen, in ml
+	vessel         string          // Name of the vessel selected for the next brew, "" for none
+	showInfo       bool            // Whether the idle view shows the preset's details
+	coolStart      time.Time       // When the water boiled for a cool-down, zero if none
+	coolNow        time.Time       // Time as of the last cool-down refresh
+	coolID         int             // Identifies the current cool-down's refresh chain
+	lastActive     time.Time       // Last key press or finished brew, for the screensaver
+	finishedAt     time.Time       // When the brew last saved to the history finished, zero if none
+	blindTea       string          // Tea of a blind taste test's brew, hidden until it is rated
+	leafGrams      float64         // Weight on the Bluetooth scale as it last reported
 }
 
 // initialModel creates a new model instance with the given configuration.
 // It initializes the timer to the selected preset duration and sets the
 // initial state to idle, ready for user interaction.
 func initialModel(config *Config) model {
-	return model{
+	m := model{
 		config:    config,
 		timer:     config.BrewTime,
 		state:     StateIdle,
 		presetIdx: 0,
+		cache:     &viewCache{},
+		clock:     now(),
+		title:     "go-brew", // The terminal title is left alone until a timer runs
+	}
+	m.lastActive = m.clock
+	if config.Vessel != "" {
+		m.vessel = config.Vessel
+		m.timer = m.brewDuration()
+	}
+	if config.AskTelemetry {
+		m = m.askTelemetry()
+	}
+	// A sequence starts with its first step and a stopwatch at zero
+	if len(config.Sequence) > 0 {
+		m.timer = config.Sequence[0].Duration
 	}
+	if !config.AlarmAt.IsZero() {
+		m = m.setAlarm(config.AlarmAt, m.clock)
+	}
+	if config.Stopwatch {
+		m.stopwatch = true
+		m.timer = 0
+	}
+	return m
 }
 
 // currentPreset returns the currently selected tea preset from the configuration.
@@ -40,6 +128,58 @@ func (m model) currentPreset() TeaPreset {
 	return m.config.Presets[0]
 }
 
+// brewDuration returns the length of the next brew: the current step of a
+// -sequence, a duration typed in the TUI, then a custom duration given with
+// -duration or the config file, otherwise the selected preset's current
+// infusion or duration.
+func (m model) brewDuration() time.Duration {
+	if step, ok := m.currentStep(); ok {
+		return step.Duration
+	}
+	if m.customDuration > 0 {
+		return m.customDuration
+	}
+	if m.config.CustomDuration {
+		return m.config.BrewTime
+	}
+	return m.vesselTime(m.presetTime())
+}
+
+// customBrew reports whether the next brew uses a custom duration rather
+// than a preset's.
+func (m model) customBrew() bool {
+	if step, ok := m.currentStep(); ok {
+		return !step.Preset
+	}
+	return m.customDuration > 0 || m.config.CustomDuration
+}
+
+// currentStep returns the step of the -sequence that runs next, or false
+// when no sequence was loaded.
+func (m model) currentStep() (sequenceStep, bool) {
+	if m.step < len(m.config.Sequence) {
+		return m.config.Sequence[m.step], true
+	}
+	return sequenceStep{}, false
+}
+
+// presetsSelectable reports whether presets can be chosen, which is not the
+// case while a -sequence or the stopwatch decides what runs.
+func (m model) presetsSelectable() bool {
+	return len(m.config.Sequence) == 0 && !m.stopwatch && !m.blindTest()
+}
+
+// silence stops an alert that is still playing, if there is one. It is
+// called whenever the user moves on from a finished brew so the audio device
+// is released immediately instead of after the sound runs out.
+func (m model) silence() model {
+	if m.stopAlert != nil {
+		m.stopAlert()
+		m.stopAlert = nil
+	}
+	return m
+}
+
 // isBrewing returns true if the timer is currently active and counting down.
 // This is a convenience method that checks if the state is StateBrewing.
 func (m model) isBrewing() bool {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -241,8 +242,448 @@ func TestPresetNavigationWithoutCustomDuration(t *testing.T) {
 	}
 }
 
+// TestStaleTicksIgnored verifies that a tick scheduled before a pause/resume
+// cycle does not count down alongside the new tick chain.
+func TestStaleTicksIgnored(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+
+	newModel, _ := mdl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m := newModel.(model)
+	staleID := m.tickID
+
+	// Pause and resume before the first tick arrives
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(model)
+	if hasTick(cmd) {
+		t.Error("Expected no tick to be scheduled while paused")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(model)
+	remaining := m.timer
+
+	// The tick from the first chain must be dropped
+	newModel, cmd = m.Update(tickMsg{id: staleID})
+	m = newModel.(model)
+	if m.timer != remaining {
+		t.Errorf("Expected stale tick to be ignored, timer went from %v to %v", remaining, m.timer)
+	}
+	if cmd != nil {
+		t.Error("Expected stale tick not to schedule another tick")
+	}
+
+	// The tick from the active chain counts down and keeps ticking
+	newModel, cmd = m.Update(tickLeft(m, remaining-time.Second))
+	m = newModel.(model)
+	if m.timer != remaining-time.Second {
+		t.Errorf("Expected timer %v, got %v", remaining-time.Second, m.timer)
+	}
+	if cmd == nil {
+		t.Error("Expected active tick to schedule the next tick")
+	}
+}
+
+// TestViewCache verifies that View reuses the previous frame until a visible
+// part of the state changes.
+func TestViewCache(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	mdl.width = 80
+	mdl.height = 24
+	mdl.clock = mdl.clock.Add(30 * time.Second) // Away from a minute boundary for "ready at"
+
+	first := mdl.View()
+	key := mdl.cache.key
+	if again := mdl.View(); again != first {
+		t.Error("Expected identical frame for unchanged state")
+	}
+
+	// A sub-second change does not alter the displayed time, which rounds
+	// partial seconds up
+	mdl.timer -= 500 * time.Millisecond
+	if mdl.viewKey() != key {
+		t.Error("Expected sub-second change to keep the same render key")
+	}
+
+	// Selecting another preset must re-render
+	newModel, _ := mdl.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m := newModel.(model)
+	if m.View() == first {
+		t.Error("Expected new frame after preset change")
+	}
+}
+
+// TestAlertFailuresShown verifies that failed alert commands are reported in
+// the finished view and cleared when the next brew starts.
+func TestAlertFailuresShown(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	mdl.state = StateFinished
+	mdl.timer = 0
+
+	newModel, _ := mdl.Update(soundResultMsg{err: errors.New("no audio device")})
+	m := newModel.(model)
+	if !contains(m.View(), "sound failed") {
+		t.Error("Expected sound failure in finished view")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = newModel.(model)
+	if m.soundFailed {
+		t.Error("Expected sound failure to be cleared on a new brew")
+	}
+}
+
+// TestStatusLine verifies that error messages appear in the status line and
+// that only the most recent status line's timeout clears it.
+func TestStatusLine(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+
+	newModel, cmd := mdl.Update(errMsg{err: errors.New("webhook timed out")})
+	m := newModel.(model)
+	if cmd == nil {
+		t.Error("Expected a command to clear the status line")
+	}
+	if !contains(m.View(), "webhook timed out") {
+		t.Error("Expected error in status line")
+	}
+	firstID := m.statusID
+
+	newModel, _ = m.Update(statusMsg("config reloaded"))
+	m = newModel.(model)
+
+	// The first line's timeout must not clear its replacement
+	newModel, _ = m.Update(statusClearMsg{id: firstID})
+	m = newModel.(model)
+	if m.status != "config reloaded" {
+		t.Errorf("Expected status %q, got %q", "config reloaded", m.status)
+	}
+
+	newModel, _ = m.Update(statusClearMsg{id: m.statusID})
+	m = newModel.(model)
+	if m.status != "" {
+		t.Errorf("Expected status to be cleared, got %q", m.status)
+	}
+}
+
+// TestNumberKeySelectsPreset verifies that number keys pick a preset directly
+// and only start it when quick start is enabled.
+func TestNumberKeySelectsPreset(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+
+	newModel, cmd := mdl.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	m := newModel.(model)
+	if m.presetIdx != 2 || m.state != StateIdle || cmd != nil {
+		t.Errorf("Expected preset 3 selected while idle, got index %d in state %v", m.presetIdx, m.state)
+	}
+	if m.timer != config.Presets[2].Duration {
+		t.Errorf("Expected timer %v, got %v", config.Presets[2].Duration, m.timer)
+	}
+
+	// Numbers beyond the preset list are ignored
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9")})
+	if newModel.(model).presetIdx != 2 {
+		t.Error("Expected out-of-range number to be ignored")
+	}
+
+	config.QuickStart = true
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = newModel.(model)
+	if m.presetIdx != 1 || !m.isBrewing() || cmd == nil {
+		t.Errorf("Expected preset 2 to start brewing, got index %d in state %v", m.presetIdx, m.state)
+	}
+}
+
+func TestTypeCustomDuration(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+
+	// typeText sends each rune as its own key press, like a user typing
+	typeText := func(m model, text string) model {
+		for _, r := range text {
+			newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			m = newModel.(model)
+		}
+		return m
+	}
+	press := func(m model, t tea.KeyType) model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: t})
+		return newModel.(model)
+	}
+
+	m := typeText(mdl, "d")
+	if m.inputKind != inputDuration {
+		t.Fatal("Expected 'd' to open the duration input")
+	}
+
+	// Keys go to the input, not the timer: 's' must not start brewing
+	m = typeText(m, "5s")
+	if m.isBrewing() || m.input.Value() != "5s" {
+		t.Errorf("Expected input to hold %q while idle, got %q in state %v", "5s", m.input.Value(), m.state)
+	}
+
+	// Out-of-range values keep the input open and explain why
+	m = press(m, tea.KeyEnter)
+	if m.inputKind != inputDuration || m.status == "" {
+		t.Error("Expected invalid duration to keep the input open with a status message")
+	}
+
+	m.input.SetValue("2m45s")
+	m = press(m, tea.KeyEnter)
+	if m.inputKind != inputNone {
+		t.Error("Expected valid duration to close the input")
+	}
+	if m.timer != 2*time.Minute+45*time.Second || m.brewDuration() != m.timer {
+		t.Errorf("Expected next brew of 2m45s, got timer %v", m.timer)
+	}
+
+	// Picking a preset replaces the typed duration
+	m = typeText(m, "2")
+	if m.customDuration != 0 || m.timer != config.Presets[1].Duration {
+		t.Errorf("Expected preset duration %v after selecting a preset, got %v", config.Presets[1].Duration, m.timer)
+	}
+
+	// Esc cancels without changing anything
+	m = typeText(m, "d")
+	m = typeText(m, "10m")
+	m = press(m, tea.KeyEsc)
+	if m.inputKind != inputNone || m.timer != config.Presets[1].Duration {
+		t.Error("Expected esc to close the input and keep the timer")
+	}
+
+	// A running brew keeps its length
+	m = typeText(m, "s")
+	if m = typeText(m, "d"); m.inputKind != inputNone {
+		t.Error("Expected no duration input while brewing")
+	}
+}
+
+// cmdMsgs runs cmd, and every command of a batch, and returns the messages
+// they produce. It must only be used with commands that return promptly.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, cmdMsgs(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+// tickLeft returns a tick of m's chain arriving when left of the brew is to
+// go by its deadline.
+func tickLeft(m model, left time.Duration) tickMsg {
+	return tickMsg{id: m.tickID, time: m.deadline.Add(-left)}
+}
+
+// hasTick reports whether cmd schedules a timer tick.
+func hasTick(cmd tea.Cmd) bool {
+	for _, msg := range cmdMsgs(cmd) {
+		if _, ok := msg.(tickMsg); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // contains is a helper function that checks if a substring exists within a string.
 // It uses a recursive approach for substring searching without relying on strings.Contains.
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s[:len(substr)] == substr || contains(s[1:], substr))
 }
+
+func TestUndo(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	press := func(m model, key string) (model, tea.Cmd) {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(model), cmd
+	}
+
+	// An accidental reset in the final seconds can be undone
+	m, _ := press(mdl, "s")
+	m.timer = 8 * time.Second
+	m, _ = press(m, "r")
+	if m.state != StateIdle {
+		t.Fatalf("Expected reset to idle, got %v", m.state)
+	}
+	m, cmd := press(m, "u")
+	if !m.isBrewing() || m.timer != 8*time.Second || cmd == nil {
+		t.Errorf("Expected brew to resume at 8s, got %v in state %v", m.timer, m.state)
+	}
+
+	// Undo does nothing while brewing
+	m, _ = press(m, "r")
+	m, _ = press(m, "s")
+	m, _ = press(m, "u")
+	if !m.isBrewing() || m.timer != config.Presets[0].Duration {
+		t.Error("Expected undo to be ignored while brewing")
+	}
+
+	// Preset changes are undone one at a time
+	m, _ = press(m, "r")
+	m, _ = press(m, "3")
+	m, _ = press(m, "5")
+	m, _ = press(m, "u")
+	if m.presetIdx != 2 || m.timer != config.Presets[2].Duration {
+		t.Errorf("Expected preset 3 after one undo, got index %d", m.presetIdx)
+	}
+	m, _ = press(m, "u")
+	if m.presetIdx != 0 || m.isBrewing() {
+		t.Errorf("Expected first preset after second undo, got index %d", m.presetIdx)
+	}
+	// The reset before the preset changes is next
+	m, _ = press(m, "u")
+	if !m.isBrewing() {
+		t.Errorf("Expected reset brew restored, got state %v", m.state)
+	}
+
+	// Starting a brew clears the history
+	m, _ = press(m, "r")
+	m, _ = press(m, "s")
+	if len(m.undo) != 0 {
+		t.Errorf("Expected no undo entries after starting a brew, got %d", len(m.undo))
+	}
+	if m, _ = press(mdl, "u"); m.status != "Nothing to undo" {
+		t.Errorf("Expected status for empty undo history, got %q", m.status)
+	}
+	m, _ = press(m, "r")
+
+	// Only the most recent actions are kept
+	for i := 0; i < maxUndo+5; i++ {
+		m, _ = press(m, "2")
+	}
+	if len(m.undo) != maxUndo {
+		t.Errorf("Expected %d undo entries, got %d", maxUndo, len(m.undo))
+	}
+}
+
+func TestConfirmQuitWhileBrewing(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	press := func(m model, msg tea.KeyMsg) (model, tea.Cmd) {
+		newModel, cmd := m.Update(msg)
+		return newModel.(model), cmd
+	}
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}
+	// isQuit runs cmd, so it must only be given commands that return at once
+	isQuit := func(cmd tea.Cmd) bool {
+		if cmd == nil {
+			return false
+		}
+		_, ok := cmd().(tea.QuitMsg)
+		return ok
+	}
+
+	// Idle quits right away
+	if _, cmd := press(mdl, q); !isQuit(cmd) {
+		t.Error("Expected q to quit immediately when idle")
+	}
+
+	m, _ := press(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m, _ = press(m, q)
+	if !m.quitArmed || m.status == "" {
+		t.Fatal("Expected first q during a brew to ask for confirmation")
+	}
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("Expected second quit key to quit")
+	}
+
+	// Another key, or the prompt timing out, cancels the confirmation
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeySpace})
+	if m, _ = press(m, q); !m.quitArmed {
+		t.Error("Expected confirmation to be asked again after another key")
+	}
+	newModel, _ := m.Update(statusClearMsg{id: m.statusID})
+	if newModel.(model).quitArmed {
+		t.Error("Expected confirmation to expire with the status line")
+	}
+
+	config.ConfirmQuit = false
+	m.quitArmed = false
+	if _, cmd := press(m, q); !isQuit(cmd) {
+		t.Error("Expected q to quit immediately with confirm_quit disabled")
+	}
+}
+
+func TestStopwatch(t *testing.T) {
+	config := NewConfig()
+	mdl := initialModel(config)
+	press := func(m model, key string) model {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(model)
+	}
+	tickOnce := func(m model) model {
+		newModel, _ := m.Update(tickLeft(m, -m.timer-time.Second))
+		return newModel.(model)
+	}
+
+	m := press(mdl, "w")
+	if !m.stopwatch || m.timer != 0 {
+		t.Fatalf("Expected stopwatch at zero, got %v", m.timer)
+	}
+
+	// Counts up and records laps with their splits
+	m = press(m, "s")
+	m = tickOnce(tickOnce(m))
+	m = press(m, "l")
+	m = tickOnce(m)
+	m = press(m, "l")
+	if m.timer != 3*time.Second || len(m.laps) != 2 {
+		t.Fatalf("Expected 3s with 2 laps, got %v with %d laps", m.timer, len(m.laps))
+	}
+	if !m.isBrewing() {
+		t.Error("Expected the stopwatch to keep running")
+	}
+	view := m.View()
+	if !contains(view, "Stopwatch") || !contains(view, "Lap 2") || !contains(view, "(+00:01)") {
+		t.Errorf("Expected stopwatch and laps in view:\n%s", view)
+	}
+
+	// Mode cannot be switched while running; reset clears the laps
+	if m = press(m, "w"); !m.stopwatch {
+		t.Error("Expected mode switch to be ignored while running")
+	}
+	m = press(m, "r")
+	if m.timer != 0 || len(m.laps) != 0 || m.state != StateIdle {
+		t.Errorf("Expected reset stopwatch, got %v with %d laps", m.timer, len(m.laps))
+	}
+
+	// Presets cannot change the stopwatch, and switching back restores the timer
+	if m = press(m, "3"); m.presetIdx != 0 || m.timer != 0 {
+		t.Error("Expected number keys to be ignored in stopwatch mode")
+	}
+	m = press(m, "w")
+	if m.stopwatch || m.timer != config.Presets[0].Duration {
+		t.Errorf("Expected timer mode with %v, got %v", config.Presets[0].Duration, m.timer)
+	}
+}
+
+// TestPresetInfoPanel verifies that the info key shows the selected preset's
+// details in the idle view and hides them again.
+func TestPresetInfoPanel(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.width, m.height = 100, 50
+	if contains(m.View(), "South Africa") {
+		t.Error("Expected the info panel to be hidden by default")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyInfo)})
+	m = newModel.(model)
+	view := m.View()
+	if !contains(view, "South Africa") || !contains(view, "3g per 250ml") {
+		t.Errorf("Expected Rooibos details in the view, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyInfo)})
+	if contains(newModel.View(), "South Africa") {
+		t.Error("Expected the info key to hide the panel again")
+	}
+}
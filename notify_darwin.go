@@ -0,0 +1,54 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gen2brain/beeep"
+)
+
+// notifyGroup groups go-brew's notifications so each brew's replaces the
+// last one rather than piling up in the Notification Center.
+const notifyGroup = "go-brew"
+
+// notify shows a notification through terminal-notifier, which posts it
+// with the native UserNotifications framework, falling back to beeep's
+// AppleScript notification when it is not installed or fails.
+func notify(title, body string, opts notifyOptions) error {
+	path, err := exec.LookPath("terminal-notifier")
+	if err != nil {
+		return beeep.Notify(title, body, notifyIcon())
+	}
+	out, err := exec.Command(path, terminalNotifierArgs(title, body, notifyIcon(), opts.Sound, os.Getenv("__CFBundleIdentifier"))...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	log.Printf("terminal-notifier failed, falling back to AppleScript: %v: %s", err, strings.TrimSpace(string(out)))
+	if fallbackErr := beeep.Notify(title, body, notifyIcon()); fallbackErr != nil {
+		return fmt.Errorf("terminal-notifier: %v; AppleScript: %w", err, fallbackErr)
+	}
+	return nil
+}
+
+// terminalNotifierArgs returns the arguments for a notification showing
+// icon, if any. Clicking it activates the app with bundle ID terminal, the
+// terminal go-brew runs in as macOS reports it, so the finished timer is one
+// click away.
+func terminalNotifierArgs(title, body, icon string, sound bool, terminal string) []string {
+	args := []string{"-title", title, "-message", body, "-group", notifyGroup}
+	if icon != "" {
+		args = append(args, "-contentImage", icon)
+	}
+	if sound {
+		args = append(args, "-sound", "default")
+	}
+	if terminal != "" {
+		args = append(args, "-activate", terminal)
+	}
+	return args
+}
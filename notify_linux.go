@@ -0,0 +1,138 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gen2brain/beeep"
+	"github.com/godbus/dbus/v5"
+)
+
+// The freedesktop.org notification service on the session bus.
+const (
+	notifyDest   = "org.freedesktop.Notifications"
+	notifyPath   = "/org/freedesktop/Notifications"
+	notifyMethod = notifyDest + ".Notify"
+)
+
+// notifyUrgencies maps the urgency settings to the levels of the
+// notification spec.
+var notifyUrgencies = map[string]byte{
+	UrgencyLow:      0,
+	UrgencyNormal:   1,
+	UrgencyCritical: 2,
+}
+
+// lastNotify is the ID the notification service gave go-brew's last
+// notification, which the next one replaces so repeated alerts update one
+// bubble instead of stacking up.
+var lastNotify struct {
+	sync.Mutex
+	id uint32
+}
+
+// notify sends a notification to the desktop's notification service over
+// D-Bus, replacing the previous one. Without a session bus it falls back to
+// beeep, which also tries notify-send and kdialog.
+func notify(title, body string, opts notifyOptions) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return beeep.Notify(title, body, notifyIcon())
+	}
+	lastNotify.Lock()
+	defer lastNotify.Unlock()
+	var id uint32
+	err = conn.Object(notifyDest, notifyPath).Call(notifyMethod, 0,
+		appName, lastNotify.id, notifyIcon(), title, body, notifyActions(opts),
+		notifyHints(opts), notifyTimeout(opts.Expire)).Store(&id)
+	if err != nil {
+		return fmt.Errorf("D-Bus notification: %w", err)
+	}
+	lastNotify.id = id
+	return nil
+}
+
+// notifyHints returns the hints for a notification with opts. The alert
+// sound is go-brew's own, so the desktop's sound is suppressed unless
+// opts.Sound asks for it.
+func notifyHints(opts notifyOptions) map[string]dbus.Variant {
+	urgency, ok := notifyUrgencies[opts.Urgency]
+	if !ok {
+		urgency = notifyUrgencies[UrgencyNormal]
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(urgency),
+		"desktop-entry": dbus.MakeVariant(appName),
+	}
+	if opts.Sound {
+		hints["sound-name"] = dbus.MakeVariant("complete")
+	} else {
+		hints["suppress-sound"] = dbus.MakeVariant(true)
+	}
+	return hints
+}
+
+// notifyActions returns the buttons of a notification with opts, as pairs of
+// action key and label.
+func notifyActions(opts notifyOptions) []string {
+	if opts.Collect {
+		return []string{collectAction, "Collect"}
+	}
+	return []string{}
+}
+
+// watchNotifyActions sends a collectMsg whenever the Collect button of
+// go-brew's latest notification is pressed, until ctx is cancelled. Without
+// a session bus there are no buttons to watch.
+func watchNotifyActions(ctx context.Context, send func(tea.Msg)) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(notifyDest), dbus.WithMatchMember("ActionInvoked")); err != nil {
+		log.Printf("Watching notification buttons failed: %v", err)
+		return
+	}
+	signals := make(chan *dbus.Signal, 8)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-signals:
+			lastNotify.Lock()
+			id := lastNotify.id
+			lastNotify.Unlock()
+			if isCollectAction(sig, id) {
+				send(collectMsg{})
+			}
+		}
+	}
+}
+
+// isCollectAction reports whether sig is the Collect button being pressed on
+// the notification with id.
+func isCollectAction(sig *dbus.Signal, id uint32) bool {
+	if sig.Name != notifyDest+".ActionInvoked" || len(sig.Body) != 2 {
+		return false
+	}
+	notification, _ := sig.Body[0].(uint32)
+	action, _ := sig.Body[1].(string)
+	return id != 0 && notification == id && action == collectAction
+}
+
+// notifyTimeout converts an expiry to the spec's timeout in milliseconds,
+// where -1 leaves it to the notification service.
+func notifyTimeout(expire time.Duration) int32 {
+	if expire <= 0 {
+		return -1
+	}
+	return int32(min(expire.Milliseconds(), 1<<31-1))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestNotifyHints(t *testing.T) {
+	hints := notifyHints(notifyOptions{Urgency: UrgencyCritical})
+	if got := hints["urgency"].Value(); got != byte(2) {
+		t.Errorf("Expected critical urgency 2, got %v", got)
+	}
+	if got := hints["suppress-sound"].Value(); got != true {
+		t.Errorf("Expected the desktop's sound to be suppressed, got %v", got)
+	}
+
+	hints = notifyHints(notifyOptions{Sound: true})
+	if got := hints["urgency"].Value(); got != byte(1) {
+		t.Errorf("Expected normal urgency by default, got %v", got)
+	}
+	if got := hints["sound-name"].Value(); got != "complete" {
+		t.Errorf("Expected the completion sound, got %v", got)
+	}
+	if _, ok := hints["suppress-sound"]; ok {
+		t.Error("Expected the sound not to be suppressed")
+	}
+}
+
+func TestNotifyTimeout(t *testing.T) {
+	if got := notifyTimeout(0); got != -1 {
+		t.Errorf("Expected -1 for the default expiry, got %d", got)
+	}
+	if got := notifyTimeout(30 * time.Second); got != 30000 {
+		t.Errorf("Expected 30000ms, got %d", got)
+	}
+}
+
+func TestCollectAction(t *testing.T) {
+	if got := notifyActions(notifyOptions{Collect: true}); len(got) != 2 || got[0] != collectAction {
+		t.Errorf("Expected a Collect button, got %v", got)
+	}
+	if got := notifyActions(notifyOptions{}); len(got) != 0 {
+		t.Errorf("Expected no buttons, got %v", got)
+	}
+
+	signal := func(id uint32, action string) *dbus.Signal {
+		return &dbus.Signal{Name: notifyDest + ".ActionInvoked", Body: []any{id, action}}
+	}
+	if !isCollectAction(signal(7, collectAction), 7) {
+		t.Error("Expected the Collect button of the latest notification to collect the brew")
+	}
+	if isCollectAction(signal(6, collectAction), 7) || isCollectAction(signal(7, "default"), 7) {
+		t.Error("Expected other notifications and actions to be ignored")
+	}
+}
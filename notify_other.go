@@ -0,0 +1,11 @@
+//go:build !windows && !darwin && !linux
+
+package main
+
+import "github.com/gen2brain/beeep"
+
+// notify shows a desktop notification through beeep. The alert sound is
+// played by go-brew itself, so the notification stays silent here.
+func notify(title, body string, opts notifyOptions) error {
+	return beeep.Notify(title, body, notifyIcon())
+}
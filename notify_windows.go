@@ -0,0 +1,48 @@
+//go:build windows
+
+package main
+
+import (
+	"log"
+	"sync"
+
+	"git.sr.ht/~jackmordaunt/go-toast"
+)
+
+// toastAppID is the name go-brew's toasts are shown and grouped under in the
+// Action Center.
+const toastAppID = "Go Brew"
+
+// toastGUID identifies go-brew to the Windows Runtime. It must never change,
+// or Windows treats go-brew as a new app and loses its notification settings.
+const toastGUID = "{6F1C5E2A-3B7D-4E8A-9C21-7D4B0A9E5F13}"
+
+// registerToast registers go-brew's AppID and icon with Windows once per
+// run and returns the icon's path. Toasts from an unregistered AppID only
+// flash by and never reach the Action Center, so a failure is logged but
+// the toast is still sent.
+var registerToast = sync.OnceValue(func() string {
+	icon := notifyIcon()
+	if err := toast.SetAppData(toast.AppData{AppID: toastAppID, GUID: toastGUID, IconPath: icon}); err != nil {
+		log.Printf("Registering for notifications failed: %v", err)
+	}
+	return icon
+})
+
+// notify shows a toast notification that stays in the Action Center until
+// dismissed. It plays the toast chime only if opts.Sound is set, since
+// go-brew normally plays its own alert.
+func notify(title, body string, opts notifyOptions) error {
+	n := toast.Notification{
+		AppID:    toastAppID,
+		Title:    title,
+		Body:     body,
+		Icon:     registerToast(),
+		Audio:    toast.Silent,
+		Duration: toast.Long,
+	}
+	if opts.Sound {
+		n.Audio = toast.Reminder
+	}
+	return n.Push()
+}
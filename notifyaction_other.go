@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchNotifyActions does nothing off Linux, where go-brew's notifications
+// have no buttons.
+func watchNotifyActions(ctx context.Context, send func(tea.Msg)) {}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overlayPage is the HTML overlay for OBS browser sources: the tea and its
+// countdown in large type over a transparent background, with a progress
+// bar. It reloads itself every second, as OBS doesn't watch local files.
+const overlayPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="1">
+<title>go-brew</title>
+<style>
+body { margin: 0; background: transparent; color: #fff; font: bold 48px sans-serif; text-shadow: 0 2px 6px #000; }
+.bar { height: 8px; margin-top: 8px; background: rgba(255, 255, 255, 0.25); }
+.bar div { height: 100%%; background: %s; }
+</style>
+</head>
+<body>
+%s
+</body>
+</html>
+`
+
+// overlayMu serialises writes to the overlay file. Each write is numbered
+// when the model asks for it, and one overtaken by a newer write is skipped,
+// so a late write can't bring back an older countdown or undo clearOverlay.
+var (
+	overlayMu      sync.Mutex
+	overlaySeq     uint64 // Number of the newest write asked for
+	overlayWritten uint64 // Number of the newest write done
+)
+
+// nextOverlayWrite numbers a new write to the overlay file.
+func nextOverlayWrite() uint64 {
+	overlayMu.Lock()
+	defer overlayMu.Unlock()
+	overlaySeq++
+	return overlaySeq
+}
+
+// writeOverlay writes content to the overlay file at path as write seq,
+// unless a newer write has already been done.
+func writeOverlay(path, content string, seq uint64) error {
+	overlayMu.Lock()
+	defer overlayMu.Unlock()
+	if seq < overlayWritten {
+		return nil
+	}
+	overlayWritten = seq
+	return writeFileAtomic(path, []byte(content))
+}
+
+// overlayErrMsg reports that the overlay file could not be written.
+type overlayErrMsg struct {
+	err error
+}
+
+// isHTMLOverlay reports whether the overlay file at path is an HTML page
+// rather than plain text, going by its extension.
+func isHTMLOverlay(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".html" || ext == ".htm"
+}
+
+// overlayText describes the timer in one short line for a stream overlay,
+// or returns "" while it is idle so the overlay disappears.
+func (m model) overlayText() string {
+	shown := m.config.TimeFormat.Format(m.shownTimer())
+	switch {
+	case m.isFinished():
+		return m.brewTea + " is ready"
+	case m.stopwatch && (m.isBrewing() || m.isPaused()):
+		return "Stopwatch " + shown
+	case m.isBrewing():
+		return m.brewTea + " " + shown
+	case m.isPaused():
+		return m.brewTea + " " + shown + " (paused)"
+	}
+	return ""
+}
+
+// overlayContent returns what the overlay file at path should hold: the
+// overlayText line, or an HTML page showing it with the brew's progress.
+func (m model) overlayContent(path string) string {
+	text := m.overlayText()
+	if !isHTMLOverlay(path) {
+		return text + "\n"
+	}
+	color := m.config.Colors.Brewing
+	if m.isFinished() {
+		color = m.config.Colors.Ready
+	}
+	body := html.EscapeString(text)
+	if text != "" && !m.stopwatch {
+		if total := m.brewDuration(); total > 0 {
+			percent := min(max(100*(total-m.timer)/total, 0), 100)
+			body += fmt.Sprintf("\n<div class=\"bar\"><div style=\"width: %d%%\"></div></div>", percent)
+		}
+	}
+	return fmt.Sprintf(overlayPage, html.EscapeString(color), body)
+}
+
+// syncOverlay adds a command writing the overlay file to cmd when its
+// content has changed since it was last written. The file is replaced in
+// one go, so OBS never reads half of it.
+func (m model) syncOverlay(cmd tea.Cmd) (model, tea.Cmd) {
+	path := m.config.OverlayFile
+	if path == "" || m.overlayFailed {
+		return m, cmd
+	}
+	content := m.overlayContent(path)
+	if content == m.overlay {
+		return m, cmd
+	}
+	m.overlay = content
+	seq := nextOverlayWrite()
+	return m, tea.Batch(cmd, func() tea.Msg {
+		if err := writeOverlay(path, content, seq); err != nil {
+			return overlayErrMsg{err}
+		}
+		return nil
+	})
+}
+
+// clearOverlay empties the overlay file on exit, so a stream doesn't keep
+// showing the last countdown. A write still in flight from the program is
+// skipped once it has been cleared.
+func clearOverlay(config *Config) {
+	path := config.OverlayFile
+	if path == "" {
+		return
+	}
+	m := model{config: config, state: StateIdle}
+	if err := writeOverlay(path, m.overlayContent(path), nextOverlayWrite()); err != nil {
+		log.Printf("Clearing the overlay failed: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOverlayText(t *testing.T) {
+	m := initialModel(NewConfig())
+	if got := m.overlayText(); got != "" {
+		t.Errorf("Expected no overlay while idle, got %q", got)
+	}
+	m, _ = m.start()
+	m.timer, m.deadline = 90*time.Second, now().Add(90*time.Second)
+	if got := m.overlayText(); got != "Rooibos 01:30" {
+		t.Errorf("Expected the tea and its countdown, got %q", got)
+	}
+	m = m.pause()
+	if got := m.overlayText(); got != "Rooibos 01:30 (paused)" {
+		t.Errorf("Expected the pause shown, got %q", got)
+	}
+	m.state = StateFinished
+	if got := m.overlayText(); got != "Rooibos is ready" {
+		t.Errorf("Expected the tea ready, got %q", got)
+	}
+}
+
+func TestOverlayFile(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled = false, false
+	config.HistoryFile = ""
+	config.OverlayFile = filepath.Join(t.TempDir(), "tea.html")
+	m := initialModel(config)
+	update := func(msg tea.Msg) {
+		newModel, cmd := m.Update(msg)
+		m = newModel.(model)
+		for _, msg := range cmdMsgs(cmd) {
+			if msg, ok := msg.(overlayErrMsg); ok {
+				t.Fatal(msg.err)
+			}
+		}
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	page, err := os.ReadFile(config.OverlayFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`http-equiv="refresh"`, "Rooibos 04:00", `width: 0%`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Expected %q in the overlay page, got:\n%s", want, page)
+		}
+	}
+
+	// The file is only written again when it changes
+	os.Remove(config.OverlayFile)
+	update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if _, err := os.Stat(config.OverlayFile); !os.IsNotExist(err) {
+		t.Error("Expected an unchanged overlay not to be rewritten")
+	}
+
+	// A countdown still being written when the program exits is dropped
+	m.timer = time.Minute
+	_, late := m.syncOverlay(nil)
+	clearOverlay(config)
+	cmdMsgs(late)
+	page, _ = os.ReadFile(config.OverlayFile)
+	if strings.Contains(string(page), "Rooibos") || !strings.Contains(string(page), "<body>\n\n</body>") {
+		t.Errorf("Expected the overlay emptied on exit, got:\n%s", page)
+	}
+}
+
+func TestOverlayWriteFailure(t *testing.T) {
+	config := NewConfig()
+	config.OverlayFile = filepath.Join(t.TempDir(), "missing", "tea.txt")
+	m := initialModel(config)
+	newModel, _ := m.Update(overlayErrMsg{os.ErrNotExist})
+	m = newModel.(model)
+	if !m.overlayFailed || !strings.Contains(m.status, "writing the overlay") {
+		t.Errorf("Expected the failure shown once, got %q", m.status)
+	}
+	if _, cmd := m.syncOverlay(nil); cmd != nil {
+		t.Error("Expected no more writes after a failure")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appName is the directory name used below each platform's base directories.
+const appName = "go-brew"
+
+// appPaths holds the directories go-brew reads and writes, resolved for the
+// current platform. All of them end in an application-specific directory that
+// may not exist yet; callers create what they need with ensureDir.
+type appPaths struct {
+	Config string // User settings: config.toml
+	Data   string // Long-lived user data such as the brew history
+	Cache  string // Regenerable files that can be deleted at any time
+	State  string // Session state that should survive restarts
+	Log    string // Log files
+
+	SystemConfig string // System-wide config file shared by all users
+}
+
+// resolvePaths computes the application directories for the given platform.
+// Linux and other Unix systems follow the XDG Base Directory specification,
+// macOS uses ~/Library and Windows uses %APPDATA% and %LOCALAPPDATA%.
+// getenv and home are passed in so every platform can be tested anywhere.
+func resolvePaths(goos string, getenv func(string) string, home string) appPaths {
+	// env returns the environment variable if it is an absolute path, as the
+	// XDG specification requires, and the fallback otherwise
+	env := func(name, fallback string) string {
+		if v := getenv(name); v != "" && filepath.IsAbs(v) {
+			return v
+		}
+		return fallback
+	}
+
+	switch goos {
+	case "windows":
+		roaming := env("APPDATA", filepath.Join(home, "AppData", "Roaming"))
+		local := env("LOCALAPPDATA", filepath.Join(home, "AppData", "Local"))
+		return appPaths{
+			Config: filepath.Join(roaming, appName),
+			Data:   filepath.Join(roaming, appName, "data"),
+			Cache:  filepath.Join(local, appName, "cache"),
+			State:  filepath.Join(local, appName, "state"),
+			Log:    filepath.Join(local, appName, "logs"),
+
+			SystemConfig: filepath.Join(env("ProgramData", `C:\ProgramData`), appName, configFileName),
+		}
+	case "darwin":
+		support := filepath.Join(home, "Library", "Application Support", appName)
+		return appPaths{
+			Config: support,
+			Data:   support,
+			Cache:  filepath.Join(home, "Library", "Caches", appName),
+			State:  filepath.Join(support, "state"),
+			Log:    filepath.Join(home, "Library", "Logs", appName),
+
+			SystemConfig: filepath.Join("/Library", "Application Support", appName, configFileName),
+		}
+	default:
+		state := filepath.Join(env("XDG_STATE_HOME", filepath.Join(home, ".local", "state")), appName)
+		return appPaths{
+			Config: filepath.Join(env("XDG_CONFIG_HOME", filepath.Join(home, ".config")), appName),
+			Data:   filepath.Join(env("XDG_DATA_HOME", filepath.Join(home, ".local", "share")), appName),
+			Cache:  filepath.Join(env("XDG_CACHE_HOME", filepath.Join(home, ".cache")), appName),
+			State:  state,
+			Log:    state,
+
+			SystemConfig: filepath.Join("/etc", appName, configFileName),
+		}
+	}
+}
+
+// dirs returns the application directories for the running platform. When
+// the home directory cannot be determined, paths are relative to the working
+// directory so go-brew still runs.
+func dirs() appPaths {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return resolvePaths(runtime.GOOS, os.Getenv, home)
+}
+
+// ensureDir creates dir, and any missing parents, readable only by the user.
+func ensureDir(dir string) error {
+	return os.MkdirAll(dir, 0o700)
+}
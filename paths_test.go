@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestResolvePaths verifies the per-platform directory layout, including
+// XDG overrides and the rejection of relative XDG paths.
+func TestResolvePaths(t *testing.T) {
+	home := filepath.FromSlash("/home/tea")
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want appPaths
+	}{
+		{
+			name: "linux defaults",
+			goos: "linux",
+			want: appPaths{
+				Config: "/home/tea/.config/go-brew",
+				Data:   "/home/tea/.local/share/go-brew",
+				Cache:  "/home/tea/.cache/go-brew",
+				State:  "/home/tea/.local/state/go-brew",
+				Log:    "/home/tea/.local/state/go-brew",
+
+				SystemConfig: "/etc/go-brew/config.toml",
+			},
+		},
+		{
+			name: "linux XDG overrides",
+			goos: "freebsd",
+			env: map[string]string{
+				"XDG_CONFIG_HOME": "/cfg",
+				"XDG_DATA_HOME":   "relative/ignored",
+				"XDG_STATE_HOME":  "/state",
+			},
+			want: appPaths{
+				Config: "/cfg/go-brew",
+				Data:   "/home/tea/.local/share/go-brew",
+				Cache:  "/home/tea/.cache/go-brew",
+				State:  "/state/go-brew",
+				Log:    "/state/go-brew",
+
+				SystemConfig: "/etc/go-brew/config.toml",
+			},
+		},
+		{
+			name: "macOS",
+			goos: "darwin",
+			want: appPaths{
+				Config: "/home/tea/Library/Application Support/go-brew",
+				Data:   "/home/tea/Library/Application Support/go-brew",
+				Cache:  "/home/tea/Library/Caches/go-brew",
+				State:  "/home/tea/Library/Application Support/go-brew/state",
+				Log:    "/home/tea/Library/Logs/go-brew",
+
+				SystemConfig: "/Library/Application Support/go-brew/config.toml",
+			},
+		},
+		{
+			name: "windows",
+			goos: "windows",
+			env:  map[string]string{"ProgramData": "/programdata"},
+			want: appPaths{
+				Config: "/home/tea/AppData/Roaming/go-brew",
+				Data:   "/home/tea/AppData/Roaming/go-brew/data",
+				Cache:  "/home/tea/AppData/Local/go-brew/cache",
+				State:  "/home/tea/AppData/Local/go-brew/state",
+				Log:    "/home/tea/AppData/Local/go-brew/logs",
+
+				SystemConfig: "/programdata/go-brew/config.toml",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			got := resolvePaths(tt.goos, getenv, home)
+			want := appPaths{
+				Config: filepath.FromSlash(tt.want.Config),
+				Data:   filepath.FromSlash(tt.want.Data),
+				Cache:  filepath.FromSlash(tt.want.Cache),
+				State:  filepath.FromSlash(tt.want.State),
+				Log:    filepath.FromSlash(tt.want.Log),
+
+				SystemConfig: filepath.FromSlash(tt.want.SystemConfig),
+			}
+			if got != want {
+				t.Errorf("resolvePaths() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
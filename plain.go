@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// plainExitDelay is how long -plain waits after a finished brew before
+// quitting when -exit-on-finish is not given, so the alerts can go out.
+const plainExitDelay = 2 * time.Second
+
+// plainPrinter wraps the timer model for -plain: it starts the brew right
+// away and, in place of the terminal UI, prints a line of plain text each
+// time the shown time or state changes, about once a second. Output captured
+// by watch, a log file or CI stays readable, as it has no control codes.
+type plainPrinter struct {
+	model  model
+	w      io.Writer
+	line   string // Last state line printed
+	status string // Last status message printed
+}
+
+// newPlainPrinter returns a plainPrinter for m writing to w.
+func newPlainPrinter(m model, w io.Writer) plainPrinter {
+	return plainPrinter{model: m, w: w}
+}
+
+// Init starts the timer as well as the brew, since there are no keys to
+// press.
+func (p plainPrinter) Init() tea.Cmd {
+	return tea.Batch(p.model.Init(), func() tea.Msg { return startMsg{} })
+}
+
+// Update passes msg to the timer and prints what changed.
+func (p plainPrinter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := p.model.Update(msg)
+	p.model = m.(model)
+	if line := p.model.plainLine(); line != "" && line != p.line {
+		fmt.Fprintln(p.w, line)
+		p.line = line
+	}
+	if status := p.model.status; status != "" && status != p.status {
+		fmt.Fprintln(p.w, status)
+	}
+	p.status = p.model.status
+	return p, cmd
+}
+
+// View draws nothing: the program runs without a renderer and Update does
+// the printing.
+func (p plainPrinter) View() string {
+	return ""
+}
+
+// plainLine describes the timer in one line of text for -plain, or returns
+// "" while it is idle.
+func (m model) plainLine() string {
+	shown := m.config.TimeFormat.Format(m.shownTimer())
+	switch {
+	case m.isFinished():
+		return fmt.Sprintf("%s ready after %s", m.brewTea, m.config.TimeFormat.Format(m.brewDuration()))
+	case m.stopwatch && m.isBrewing():
+		return "Stopwatch " + shown
+	case m.stopwatch && m.isPaused():
+		return "Stopwatch paused at " + shown
+	case m.isBrewing() || m.isPaused():
+		total := m.brewDuration()
+		verb := "Brewing"
+		if m.isPaused() {
+			verb = "Paused"
+		}
+		return fmt.Sprintf("%s %s: %s left, %d%%", verb, m.brewTea, shown, int(100*(total-m.timer)/total))
+	}
+	return ""
+}
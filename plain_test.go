@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPlainPrinter(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled, config.HistoryFile = false, false, ""
+	config.BrewTime, config.CustomDuration = 2*time.Second, true
+	var out bytes.Buffer
+	var p tea.Model = newPlainPrinter(initialModel(config), &out)
+
+	send := func(msg tea.Msg) {
+		p, _ = p.Update(msg)
+	}
+	send(startMsg{})
+	send(tickLeft(p.(plainPrinter).model, time.Second))
+	send(clockMsg(now())) // Nothing changes, so nothing is printed
+	send(tickLeft(p.(plainPrinter).model, 0))
+
+	want := "Brewing Custom: 00:02 left, 0%\nBrewing Custom: 00:01 left, 50%\nCustom ready after 00:02\n"
+	if out.String() != want {
+		t.Errorf("Expected one line per change:\n%s\ngot:\n%s", want, out.String())
+	}
+	if strings.Contains(out.String(), "\x1b") {
+		t.Errorf("Expected no control codes, got %q", out.String())
+	}
+	if p.View() != "" {
+		t.Error("Expected -plain to draw nothing")
+	}
+}
+
+func TestPlainRejectsRecord(t *testing.T) {
+	config := NewConfig()
+	config.Plain, config.RecordFile = true, "session.jsonl"
+	if err := config.Validate(); err == nil {
+		t.Error("Expected -plain with -record to be rejected")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// duplicatePreset asks for the name of a copy of the highlighted preset,
+// such as "Green Tea (strong)".
+func (m model) duplicatePreset() (model, tea.Cmd) {
+	name := m.currentPreset().Name
+	m, cmd := m.openInput(inputPresetName, "Copy as: ", name+" (strong)")
+	m.input.SetValue(name + " ")
+	m.input.CursorEnd()
+	return m, cmd
+}
+
+// editPresetDuration asks for a new duration for the highlighted preset.
+func (m model) editPresetDuration() (model, tea.Cmd) {
+	p := m.currentPreset()
+	m, cmd := m.openInput(inputPresetDuration, p.Name+": ", p.Duration.String())
+	m.input.SetValue(p.Duration.String())
+	m.input.CursorEnd()
+	return m, cmd
+}
+
+// checkPresetName reports whether name can be used for a new preset.
+func (m model) checkPresetName(name string) error {
+	if name == "" {
+		return fmt.Errorf("preset name must not be empty")
+	}
+	for _, p := range m.config.Presets {
+		if strings.EqualFold(p.Name, name) {
+			return fmt.Errorf("there is already a preset called %q", p.Name)
+		}
+	}
+	return nil
+}
+
+// addPresetCopy inserts a copy of the highlighted preset named name right
+// after it and selects it. The preset list is replaced rather than changed
+// in place, since it may be the shared DefaultTeaPresets.
+func (m model) addPresetCopy(name string) (model, tea.Cmd) {
+	p := m.currentPreset()
+	p.Name = name
+	m = m.saveUndo("preset change")
+	m.config.Presets = slices.Insert(slices.Clone(m.config.Presets), m.presetIdx+1, p)
+	m.presetIdx++
+	m.infusion = 0
+	m = m.clearCustom()
+	m.timer = m.brewDuration()
+	return m.showStatus("Added " + name + " for this session")
+}
+
+// setPresetDuration changes the highlighted preset's duration to d.
+func (m model) setPresetDuration(d time.Duration) (model, tea.Cmd) {
+	presets := slices.Clone(m.config.Presets)
+	presets[m.presetIdx].Duration = d
+	m.config.Presets = presets
+	m = m.clearCustom()
+	m.timer = m.brewDuration()
+	return m.showStatus(fmt.Sprintf("%s now brews for %v this session", presets[m.presetIdx].Name, d))
+}
+
+// parsePresetDuration parses a preset duration typed in the TUI and checks
+// it against the supported brew time range.
+func parsePresetDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (try 3m or 2m45s)", s)
+	}
+	return d, checkBrewTime(d)
+}
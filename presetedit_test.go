@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDuplicateAndEditPreset(t *testing.T) {
+	m := initialModel(NewConfig())
+	send := func(msg tea.KeyMsg) {
+		newModel, _ := m.Update(msg)
+		m = newModel.(model)
+	}
+	submit := func(value string) {
+		m.input.SetValue(value)
+		send(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	// Duplicate Green Tea as a stronger variant, selected right after it
+	send(tea.KeyMsg{Type: tea.KeyDown})
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyCopy)})
+	if m.inputKind != inputPresetName || m.input.Value() != "Green Tea " {
+		t.Fatalf("Expected a name prompt prefilled with the preset name, got %q", m.input.Value())
+	}
+	submit("green tea")
+	if m.inputKind != inputPresetName {
+		t.Fatal("Expected a duplicate name to keep the prompt open")
+	}
+	submit("Green Tea (strong)")
+	if len(m.config.Presets) != len(DefaultTeaPresets)+1 || m.presetIdx != 2 || m.currentPreset().Name != "Green Tea (strong)" {
+		t.Fatalf("Expected the copy to follow Green Tea and be selected, got %+v at %d", m.config.Presets, m.presetIdx)
+	}
+
+	// Give the copy its own duration without touching the original
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyEdit)})
+	if m.inputKind != inputPresetDuration {
+		t.Fatal("Expected the edit key to ask for a duration")
+	}
+	submit("10s")
+	if m.inputKind != inputPresetDuration {
+		t.Fatal("Expected a too short duration to keep the prompt open")
+	}
+	submit("3m")
+	if m.currentPreset().Duration != 3*time.Minute || m.timer != 3*time.Minute {
+		t.Errorf("Expected the copy to brew for 3m, got %v", m.currentPreset().Duration)
+	}
+	if m.config.Presets[1].Duration != 2*time.Minute || DefaultTeaPresets[1].Duration != 2*time.Minute {
+		t.Error("Expected Green Tea and the built-in presets to be unchanged")
+	}
+}
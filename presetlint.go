@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Water temperatures outside this range in °C are probably a typo, such as
+// a Fahrenheit value marked °C.
+const (
+	minLintTemp = 50
+	maxLintTemp = 100
+)
+
+// presetWarning is a suspicious preset definition. Unlike the errors found
+// by validation it doesn't stop go-brew from starting.
+type presetWarning struct {
+	Index   int    // Position of the preset in the list
+	Name    string // Name of the preset
+	Message string // What looks wrong
+}
+
+// String describes the warning the way config errors name presets.
+func (w presetWarning) String() string {
+	return fmt.Sprintf("presets[%d] %q: %s", w.Index, w.Name, w.Message)
+}
+
+// lintPresets flags presets that are valid but probably not what was meant:
+// no brew time, a water temperature outside minLintTemp-maxLintTemp °C or
+// none at all, and names used twice, which leave the later preset
+// unreachable wherever presets are picked by name.
+func lintPresets(presets []TeaPreset) []presetWarning {
+	var warnings []presetWarning
+	warn := func(i int, format string, args ...any) {
+		warnings = append(warnings, presetWarning{i, presets[i].Name, fmt.Sprintf(format, args...)})
+	}
+	first := make(map[string]int)
+	for i, p := range presets {
+		if p.Duration <= 0 {
+			warn(i, "has no brew time")
+		}
+		for j, steep := range p.Steeps {
+			if steep <= 0 {
+				warn(i, "steep %d has no brew time", j+1)
+			}
+		}
+		if p.Temp != "" {
+			if low, high, ok := parseTempRange(p.Temp); !ok {
+				warn(i, "temp %q names no temperature in °C or °F", p.Temp)
+			} else if low < minLintTemp || high > maxLintTemp {
+				warn(i, "temp %q is outside %d-%d°C", p.Temp, minLintTemp, maxLintTemp)
+			}
+		}
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		if j, ok := first[name]; ok {
+			warn(i, "has the same name as presets[%d]", j)
+		} else {
+			first[name] = i
+		}
+	}
+	return warnings
+}
+
+// runPresetsCommand implements "go-brew presets" and returns the process
+// exit code:
+//
+//	go-brew presets lint [-config file]   warn about suspicious preset definitions
+//
+// lint exits with 1 when it finds anything, so it can guard a shared config
+// in CI.
+func runPresetsCommand(args []string, stdout, stderr io.Writer) int {
+	const usage = "usage: go-brew presets lint [-config file]"
+	if len(args) == 0 || args[0] != "lint" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	fs := flag.NewFlagSet("presets lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to read")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\n%s\n", fs.Arg(0), usage)
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+
+	// Loading logs the same warnings; here they are printed below instead
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	err := config.Load()
+	log.SetOutput(out)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	warnings := lintPresets(config.Presets)
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "warning: %s: %s\n", config.source("presets"), w)
+	}
+	if len(warnings) > 0 {
+		return 1
+	}
+	fmt.Fprintf(stdout, "%d %s fine\n", len(config.Presets), plural(len(config.Presets), "preset looks", "presets look"))
+	return 0
+}
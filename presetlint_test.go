@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTempRange(t *testing.T) {
+	tests := []struct {
+		temp      string
+		low, high float64
+		ok        bool
+	}{
+		{"80°C", 80, 80, true},
+		{"70-80°C", 70, 80, true},
+		{"212°F", 100, 100, true},
+		{"about 85 C", 85, 85, true},
+		{"boiling", 0, 0, false},
+	}
+	for _, tt := range tests {
+		low, high, ok := parseTempRange(tt.temp)
+		if ok != tt.ok || low != tt.low || high != tt.high {
+			t.Errorf("parseTempRange(%q): expected %v-%v %v, got %v-%v %v", tt.temp, tt.low, tt.high, tt.ok, low, high, ok)
+		}
+	}
+}
+
+func TestLintPresets(t *testing.T) {
+	if warnings := lintPresets(DefaultTeaPresets); len(warnings) > 0 {
+		t.Errorf("Expected the built-in presets to lint clean, got %v", warnings)
+	}
+
+	presets := []TeaPreset{
+		{Name: "Sencha", Duration: time.Minute, Temp: "75°C"},
+		{Name: "Gyokuro", Duration: 2 * time.Minute, Temp: "40-60°C"},
+		{Name: "Puerh", Duration: 0, Temp: "95°C", Steeps: []time.Duration{10 * time.Second, 0}},
+		{Name: "Masala Chai", Duration: 5 * time.Minute, Temp: "205°C"},
+		{Name: "Mint", Duration: 5 * time.Minute, Temp: "hot"},
+		{Name: "sencha ", Duration: time.Minute},
+	}
+	want := []string{
+		`presets[1] "Gyokuro": temp "40-60°C" is outside 50-100°C`,
+		`presets[2] "Puerh": has no brew time`,
+		`presets[2] "Puerh": steep 2 has no brew time`,
+		`presets[3] "Masala Chai": temp "205°C" is outside 50-100°C`,
+		`presets[4] "Mint": temp "hot" names no temperature in °C or °F`,
+		`presets[5] "sencha ": has the same name as presets[0]`,
+	}
+	warnings := lintPresets(presets)
+	if len(warnings) != len(want) {
+		t.Fatalf("Expected %d warnings, got %v", len(want), warnings)
+	}
+	for i, w := range warnings {
+		if w.String() != want[i] {
+			t.Errorf("Expected warning %q, got %q", want[i], w)
+		}
+	}
+}
+
+func TestPresetsLintCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	clean := writeConfig(t, "[[presets]]\nname = \"Sencha\"\nduration = \"1m\"\ntemp = \"75°C\"\n")
+	if code := runPresetsCommand([]string{"lint", "-config", clean}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stdout.String() != "1 preset looks fine\n" {
+		t.Errorf("Expected the preset to look fine, got %q", stdout.String())
+	}
+
+	suspicious := writeConfig(t, "[[presets]]\nname = \"Sencha\"\nduration = \"1m\"\ntemp = \"175°C\"\n")
+	if code := runPresetsCommand([]string{"lint", "-config", suspicious}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for a suspicious preset, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `warning: user `+suspicious+`: presets[0] "Sencha": temp "175°C" is outside 50-100°C`) {
+		t.Errorf("Expected a warning naming the file and preset, got %q", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := runConfigCommand([]string{"validate", "-config", suspicious}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected a suspicious preset to pass validation, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "warning: "+suspicious+`: presets[0] "Sencha"`) {
+		t.Errorf("Expected config validate to warn about the preset, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	_ "net/http/pprof" // Registers the /debug/pprof handlers on the default mux
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// startProfiling enables the profilers requested on the command line and
+// returns a function that flushes and closes them. The returned function must
+// be called before the program exits, otherwise CPU profiles and traces are
+// left truncated. When no profiling flag is set it is a no-op.
+func startProfiling(c *Config) (func(), error) {
+	var stops []func()
+	stopAll := func() {
+		// Stop in reverse order so later profilers see the earlier ones finish
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if c.CPUProfile != "" {
+		f, err := os.Create(c.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("start CPU profile: %w", err)
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		})
+	}
+
+	if c.TraceFile != "" {
+		f, err := os.Create(c.TraceFile)
+		if err != nil {
+			stopAll()
+			return nil, fmt.Errorf("create trace file: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stopAll()
+			return nil, fmt.Errorf("start trace: %w", err)
+		}
+		stops = append(stops, func() {
+			trace.Stop()
+			f.Close()
+		})
+	}
+
+	if c.MemProfile != "" {
+		// The heap profile is a snapshot, so it is written on the way out
+		path := c.MemProfile
+		stops = append(stops, func() {
+			if err := writeHeapProfile(path); err != nil {
+				log.Printf("Failed to write memory profile: %v", err)
+			}
+		})
+	}
+
+	if c.PprofAddr != "" {
+		// Serve the standard pprof handlers for live inspection while brewing
+		go func() {
+			if err := http.ListenAndServe(c.PprofAddr, nil); err != nil {
+				log.Printf("pprof server stopped: %v", err)
+			}
+		}()
+	}
+
+	return stopAll, nil
+}
+
+// writeHeapProfile writes an up-to-date heap profile to path.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Run a GC first so the profile reflects live objects only
+	runtime.GC()
+	return pprof.WriteHeapProfile(f)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily span of wall-clock time, written like "22:00-07:00",
+// during which finished brews and cues make no sound: desktop notifications
+// arrive silently and lights still blink. A span that ends earlier than it
+// starts runs past midnight. The empty value means no quiet hours.
+type QuietHours string
+
+// UnmarshalText validates and stores a quiet hours span.
+func (q *QuietHours) UnmarshalText(text []byte) error {
+	if _, _, err := QuietHours(text).span(); err != nil {
+		return err
+	}
+	*q = QuietHours(text)
+	return nil
+}
+
+// span returns the time of day quiet hours start and end at, as time since
+// midnight.
+func (q QuietHours) span() (from, to time.Duration, err error) {
+	start, end, ok := strings.Cut(string(q), "-")
+	if ok {
+		from, err = parseTimeOfDay(start)
+		if err == nil {
+			to, err = parseTimeOfDay(end)
+		}
+	}
+	if !ok || err != nil || from == to {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q (use a span such as \"22:00-07:00\")", string(q))
+	}
+	return from, to, nil
+}
+
+// parseTimeOfDay parses a time such as "07:00" as time since midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains reports whether t falls within the quiet hours.
+func (q QuietHours) contains(t time.Time) bool {
+	if q == "" {
+		return false
+	}
+	from, to, err := q.span()
+	if err != nil {
+		return false
+	}
+	at := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if from < to {
+		return at >= from && at < to
+	}
+	return at >= from || at < to
+}
+
+// quiet reports whether alerts should be silent now.
+func (c *Config) quiet() bool {
+	return c.QuietHours.contains(now())
+}
+
+// quietFinish reports whether the finished brew's alert sound is being held
+// back for quiet hours, so the view can say why the tea arrived silently.
+func (m model) quietFinish() bool {
+	return m.isFinished() && m.config.SoundEnabled && m.config.QuietHours.contains(m.clock)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2024, 3, 1, hour, minute, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		quiet QuietHours
+		at    time.Time
+		want  bool
+	}{
+		{"", at(23, 0), false},
+		{"22:00-07:00", at(23, 30), true},
+		{"22:00-07:00", at(3, 0), true},
+		{"22:00-07:00", at(7, 0), false},
+		{"22:00-07:00", at(21, 59), false},
+		{"13:00-14:30", at(14, 0), true},
+		{"13:00-14:30", at(14, 30), false},
+	} {
+		if got := tc.quiet.contains(tc.at); got != tc.want {
+			t.Errorf("Expected %q to contain %s: %v, got %v", tc.quiet, tc.at.Format("15:04"), tc.want, got)
+		}
+	}
+}
+
+func TestQuietHoursUnmarshal(t *testing.T) {
+	var q QuietHours
+	if err := q.UnmarshalText([]byte("22:30-6:45")); err != nil || q != "22:30-6:45" {
+		t.Errorf("Expected a valid span to be stored, got %q (%v)", q, err)
+	}
+	for _, bad := range []string{"22:00", "22:00-25:00", "late-early", "07:00-07:00"} {
+		if err := q.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestQuietHoursSilenceAlerts(t *testing.T) {
+	var opts []notifyOptions
+	defer func(f func(title, body string, opts notifyOptions) error) { sendNotification = f }(sendNotification)
+	sendNotification = func(_, _ string, o notifyOptions) error {
+		opts = append(opts, o)
+		return nil
+	}
+
+	config := NewConfig()
+	config.NotificationSound = true
+	config.GPIOPin = -1
+	config.QuietHours = "13:00-15:00" // Tests run at 14:00
+	for _, msg := range cmdMsgs(alertCmd(context.Background(), config, "Tea is ready")) {
+		if _, ok := msg.(soundResultMsg); ok {
+			t.Error("Expected no alert sound during quiet hours")
+		}
+	}
+	if len(opts) != 1 || opts[0].Sound {
+		t.Errorf("Expected one silent notification, got %+v", opts)
+	}
+
+	m := initialModel(config)
+	m.state = StateFinished
+	if !m.quietFinish() {
+		t.Error("Expected the finished view to explain the missing sound")
+	}
+}
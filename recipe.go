@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// recipes are the guided preparations available with -recipe. Each runs as
+// a sequence whose steps carry an instruction. Only steps naming a Tea are
+// recorded in the brew history; the others just prepare the cup.
+var recipes = map[string][]sequenceStep{
+	"matcha": {
+		{Label: "Sift", Duration: 30 * time.Second, Hint: "Sift 2g (1 tsp) of matcha into a warmed bowl to break up any clumps"},
+		{Label: "Add water", Duration: 15 * time.Second, Hint: "Pour 70ml of water at 80°C, not boiling, over the powder"},
+		{Label: "Whisk", Duration: 15 * time.Second, Hint: "Whisk briskly from the wrist in a W motion until a fine froth forms", Tea: "Matcha"},
+	},
+}
+
+// recipeNames returns the names of the built-in recipes, sorted.
+func recipeNames() []string {
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recipeSteps returns the steps of the named recipe, ignoring case.
+func recipeSteps(name string) ([]sequenceStep, error) {
+	steps, ok := recipes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown recipe %q (available: %s)", name, strings.Join(recipeNames(), ", "))
+	}
+	return steps, nil
+}
+
+// prepStep reports whether the running step of a recipe only prepares the
+// cup, so it has no strength to show and is left out of the history.
+func (m model) prepStep() bool {
+	step, ok := m.currentStep()
+	return ok && m.config.Recipe != "" && step.Tea == ""
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecipeSteps(t *testing.T) {
+	steps, err := recipeSteps("Matcha")
+	if err != nil || len(steps) != 3 {
+		t.Fatalf("Expected the 3 matcha steps, got %v, %v", steps, err)
+	}
+	if steps[2].Label != "Whisk" || steps[2].Duration != 15*time.Second || !strings.Contains(steps[2].Hint, "W motion") {
+		t.Errorf("Expected a 15s whisk in a W motion last, got %+v", steps[2])
+	}
+	if _, err := recipeSteps("gyokuro"); err == nil || !strings.Contains(err.Error(), "available: matcha") {
+		t.Errorf("Expected an unknown recipe to list the available ones, got %v", err)
+	}
+}
+
+func TestRecipeValidate(t *testing.T) {
+	config := NewConfig()
+	config.Recipe = "matcha"
+	config.Stopwatch = true
+	if err := config.Validate(); err == nil {
+		t.Error("Expected -recipe with -stopwatch to be rejected")
+	}
+}
+
+// TestMatchaRecipe whisks through the matcha recipe and checks that each
+// step shows its instruction and only the finished cup is recorded.
+func TestMatchaRecipe(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	config.Recipe = "matcha"
+	config.Sequence, _ = recipeSteps(config.Recipe)
+	m := initialModel(config)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = newModel.(model)
+	if view := m.View(); !contains(view, "Step 1/3: Sift") || !contains(view, "Sift 2g") || contains(view, "Strength") {
+		t.Fatalf("Expected the sift instruction without a strength estimate, got\n%s", view)
+	}
+
+	for step := 1; step <= 3; step++ {
+		m.timer = time.Second
+		newModel, cmd := m.Update(tickLeft(m, 0))
+		m = newModel.(model)
+		cmdMsgs(cmd) // Writes the history
+		if step == 2 && !contains(m.View(), "Whisk briskly") {
+			t.Errorf("Expected the whisk instruction after adding water, got\n%s", m.View())
+		}
+	}
+	if !m.isFinished() || m.brewTea != "Matcha" {
+		t.Errorf("Expected the matcha to be ready, got %q in state %v", m.brewTea, m.state)
+	}
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || records[0].Tea != "Matcha" {
+		t.Errorf("Expected only the finished matcha in the history, got %+v, %v", records, err)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// recordTextLimit caps the text logged for messages that are not replayed,
+// which are only there to read.
+const recordTextLimit = 200
+
+// recordHeader is the first line of a session recording: what the timer
+// ran with and the terminal it ran in, for reproducing a bug somewhere else.
+type recordHeader struct {
+	Version     string          `json:"version"`      // go-brew version
+	Started     time.Time       `json:"started"`      // Wall-clock time the session started
+	Args        []string        `json:"args"`         // Command line arguments
+	Term        string          `json:"term"`         // $TERM
+	TermProgram string          `json:"term_program"` // $TERM_PROGRAM, if set
+	ColorTerm   string          `json:"colorterm"`    // $COLORTERM, if set
+	Profile     termenv.Profile `json:"profile"`      // Color profile detected for the terminal
+	DarkBG      bool            `json:"dark_bg"`      // Whether the background was detected as dark
+	Config      string          `json:"config"`       // Effective configuration in config file format
+}
+
+// recordEvent is one message received by the timer. Input from the terminal
+// is stored in full so it can be replayed; the messages the timer sends
+// itself, such as ticks, are logged by type and text as they will happen
+// again on their own.
+type recordEvent struct {
+	At    Duration     `json:"at"`              // Time since the session started
+	Type  string       `json:"type"`            // Go type of the message
+	Key   *recordedKey `json:"key,omitempty"`   // Key press or paste
+	Size  []int        `json:"size,omitempty"`  // Terminal width and height
+	Focus *bool        `json:"focus,omitempty"` // Window focus gained or lost
+	Text  string       `json:"text,omitempty"`  // Any other message, as text
+}
+
+// recordedKey is a tea.KeyMsg in a stable form.
+type recordedKey struct {
+	Type  tea.KeyType `json:"type"`
+	Runes string      `json:"runes,omitempty"`
+	Alt   bool        `json:"alt,omitempty"`
+	Paste bool        `json:"paste,omitempty"`
+}
+
+// recorder wraps the timer model and logs every message it receives to a
+// session recording before handing it on.
+type recorder struct {
+	model
+	log *recordLog
+}
+
+// recordLog is the open recording, shared by every copy of the recorder.
+type recordLog struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	start time.Time
+	err   error // First write error; later events are dropped
+}
+
+// newRecorder starts a recording of a session of m written to w.
+func newRecorder(m model, w io.Writer, args []string, getenv func(string) string) (recorder, error) {
+	var config bytes.Buffer
+	if err := toml.NewEncoder(&config).Encode(m.config.toFile()); err != nil {
+		return recorder{}, err
+	}
+	bw := bufio.NewWriter(w)
+	l := &recordLog{w: bw, enc: json.NewEncoder(bw), start: now()}
+	header := recordHeader{
+		Version:     version,
+		Started:     l.start,
+		Args:        args,
+		Term:        getenv("TERM"),
+		TermProgram: getenv("TERM_PROGRAM"),
+		ColorTerm:   getenv("COLORTERM"),
+		Profile:     lipgloss.ColorProfile(),
+		DarkBG:      lipgloss.HasDarkBackground(),
+		Config:      config.String(),
+	}
+	if err := l.enc.Encode(header); err != nil {
+		return recorder{}, err
+	}
+	return recorder{model: m, log: l}, nil
+}
+
+// Update records msg and passes it to the timer.
+func (r recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	r.log.write(msg)
+	m, cmd := r.model.Update(msg)
+	r.model = m.(model)
+	return r, cmd
+}
+
+// write appends msg to the recording and flushes it, so a crash loses
+// nothing.
+func (l *recordLog) write(msg tea.Msg) {
+	if l.err != nil {
+		return
+	}
+	if l.err = l.enc.Encode(eventOf(msg, now().Sub(l.start))); l.err == nil {
+		l.err = l.w.Flush()
+	}
+}
+
+// eventOf describes msg, received at offset at into the session.
+func eventOf(msg tea.Msg, at time.Duration) recordEvent {
+	e := recordEvent{At: Duration{at}, Type: fmt.Sprintf("%T", msg)}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		e.Key = &recordedKey{Type: msg.Type, Runes: string(msg.Runes), Alt: msg.Alt, Paste: msg.Paste}
+	case tea.WindowSizeMsg:
+		e.Size = []int{msg.Width, msg.Height}
+	case tea.FocusMsg:
+		e.Focus = new(bool)
+		*e.Focus = true
+	case tea.BlurMsg:
+		e.Focus = new(bool)
+	default:
+		e.Text = fmt.Sprintf("%+v", msg)
+		if len(e.Text) > recordTextLimit {
+			e.Text = e.Text[:recordTextLimit] + "…"
+		}
+	}
+	return e
+}
+
+// msg returns the terminal input the event recorded, or false for the
+// messages the timer sends itself.
+func (e recordEvent) msg() (tea.Msg, bool) {
+	switch {
+	case e.Key != nil:
+		return tea.KeyMsg{Type: e.Key.Type, Runes: []rune(e.Key.Runes), Alt: e.Key.Alt, Paste: e.Key.Paste}, true
+	case len(e.Size) == 2:
+		return tea.WindowSizeMsg{Width: e.Size[0], Height: e.Size[1]}, true
+	case e.Focus != nil && *e.Focus:
+		return tea.FocusMsg{}, true
+	case e.Focus != nil:
+		return tea.BlurMsg{}, true
+	}
+	return nil, false
+}
+
+// loadRecording reads a session recording.
+func loadRecording(r io.Reader) (recordHeader, []recordEvent, error) {
+	var header recordHeader
+	var events []recordEvent
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&header); err != nil {
+		return header, nil, fmt.Errorf("reading the recording header: %w", err)
+	}
+	for {
+		var e recordEvent
+		if err := dec.Decode(&e); err == io.EOF {
+			return header, events, nil
+		} else if err != nil {
+			return header, events, fmt.Errorf("reading event %d: %w", len(events)+1, err)
+		}
+		events = append(events, e)
+	}
+}
+
+// replayConfig rebuilds the configuration a recording was made with. The
+// replay makes no sound, sends no alerts and records no brews.
+func replayConfig(header recordHeader) (*Config, error) {
+	fc, err := decodeConfig([]byte(header.Config))
+	if err != nil {
+		return nil, fmt.Errorf("recorded configuration: %w", err)
+	}
+	config := NewConfig()
+	config.applyFile(fc, "recording")
+	config.SoundEnabled, config.NotifyEnabled, config.CueSound = false, false, false
+	config.GPIOPin, config.Light = -1, LightConfig{}
+	config.HistoryFile, config.TelemetryPath = "", ""
+	config.Formats = config.locale(os.Getenv)
+	if config.Images {
+		config.ImageProtocol = detectImageProtocol(os.Getenv)
+	}
+	return config, nil
+}
+
+// runReplayCommand implements "go-brew replay" and returns the process exit
+// code:
+//
+//	go-brew replay [-speed n] file
+//
+// It runs the timer with the recorded configuration and color profile and
+// feeds it the recorded key presses, window sizes and focus changes at their
+// original pace, or n times as fast.
+func runReplayCommand(args []string, stdout, stderr io.Writer) int {
+	const usage = "usage: go-brew replay [-speed n] file"
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	speed := fs.Float64("speed", 1, "play back `n` times as fast")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || *speed <= 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	header, events, err := loadRecording(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	config, err := replayConfig(header)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Replaying go-brew %s session from %s: %s (TERM=%s TERM_PROGRAM=%s COLORTERM=%s)\n",
+		header.Version, header.Started.Format(time.DateTime), strings.Join(append([]string{"go-brew"}, header.Args...), " "),
+		header.Term, header.TermProgram, header.ColorTerm)
+
+	lipgloss.SetColorProfile(header.Profile)
+	lipgloss.SetHasDarkBackground(header.DarkBG)
+	// Keys typed now would mix with the recorded ones; ctrl+c still stops
+	p := tea.NewProgram(initialModel(config), tea.WithAltScreen(), tea.WithInput(nil))
+	go func() {
+		start := time.Now()
+		for _, e := range events {
+			msg, ok := e.msg()
+			if !ok {
+				continue
+			}
+			time.Sleep(time.Until(start.Add(time.Duration(float64(e.At.Duration) / *speed))))
+			p.Send(msg)
+		}
+		// Leave the last frame up briefly if the session didn't quit
+		time.Sleep(2 * time.Second)
+		p.Quit()
+	}()
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	config := NewConfig()
+	config.HalfwayCue = 40
+	env := map[string]string{"TERM": "xterm-kitty", "TERM_PROGRAM": "kitty"}
+	var buf bytes.Buffer
+	r, err := newRecorder(initialModel(config), &buf, []string{"-duration", "3m"}, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []tea.Msg{
+		tea.WindowSizeMsg{Width: 132, Height: 43},
+		tea.KeyMsg{Type: tea.KeyDown},
+		tea.BlurMsg{},
+		tea.FocusMsg{},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")},
+		tickMsg{id: 1, time: now()},
+	}
+	var m tea.Model = r
+	for _, msg := range inputs {
+		m, _ = m.Update(msg)
+	}
+	if got := m.(recorder).model; got.width != 132 || got.presetIdx != 1 || !got.isBrewing() {
+		t.Errorf("Expected the messages to reach the timer, got width %d, preset %d, %v", got.width, got.presetIdx, got.state)
+	}
+
+	header, events, err := loadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Term != "xterm-kitty" || header.TermProgram != "kitty" || strings.Join(header.Args, " ") != "-duration 3m" {
+		t.Errorf("Expected the terminal and arguments in the header, got %+v", header)
+	}
+	if len(events) != len(inputs) {
+		t.Fatalf("Expected %d events, got %d", len(inputs), len(events))
+	}
+	for i, want := range inputs[:5] {
+		if got, ok := events[i].msg(); !ok || !equalMsg(got, want) {
+			t.Errorf("Expected event %d to replay as %#v, got %#v", i, want, got)
+		}
+	}
+	if _, ok := events[5].msg(); ok || events[5].Type != "main.tickMsg" || events[5].Text == "" {
+		t.Errorf("Expected the tick to be logged but not replayed, got %+v", events[5])
+	}
+
+	replay, err := replayConfig(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.HalfwayCue != 40 {
+		t.Errorf("Expected the recorded configuration, got halfway %d", replay.HalfwayCue)
+	}
+	if replay.SoundEnabled || replay.NotifyEnabled || replay.HistoryFile != "" {
+		t.Error("Expected a replay to make no sound, send no alerts and record no brews")
+	}
+}
+
+// equalMsg compares messages that may hold slices.
+func equalMsg(a, b tea.Msg) bool {
+	if ka, ok := a.(tea.KeyMsg); ok {
+		kb, ok := b.(tea.KeyMsg)
+		return ok && ka.String() == kb.String() && ka.Paste == kb.Paste
+	}
+	return a == b
+}
+
+func TestLoadRecordingErrors(t *testing.T) {
+	if _, _, err := loadRecording(strings.NewReader("")); err == nil {
+		t.Error("Expected an empty recording to be rejected")
+	}
+	_, _, err := loadRecording(strings.NewReader(`{"version":"1.0.0"}` + "\n" + `{"at":"1s"` + "\n"))
+	if err == nil || !strings.Contains(err.Error(), "event 1") {
+		t.Errorf("Expected the broken event to be named, got %v", err)
+	}
+}
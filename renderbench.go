@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// benchSizes are the terminal sizes rendering is measured at, from a small
+// split pane to a large kitchen display.
+var benchSizes = []struct{ width, height int }{
+	{40, 12},
+	{80, 24},
+	{120, 40},
+	{200, 60},
+}
+
+// benchScene is a model in a state worth measuring the render of.
+type benchScene struct {
+	name  string
+	model model
+}
+
+// benchScenes returns the states measured at each size: the idle preset
+// list, a brew in progress and the stats screen with a month of history.
+func benchScenes(width, height int) []benchScene {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = ""
+
+	idle := initialModel(config)
+	idle.width, idle.height = width, height
+
+	brewing := idle
+	brewing.state = StateBrewing
+	brewing.brewTea = brewing.currentPreset().Name
+	brewing.timer = brewing.brewDuration() / 3
+
+	stats := idle
+	stats.screen = screenStats
+	for i := range 60 {
+		stats.history = append(stats.history, brewRecord{
+			Time: now().Add(-time.Duration(i) * 12 * time.Hour),
+			Tea:  config.Presets[i%len(config.Presets)].Name,
+		})
+	}
+	return []benchScene{{"idle", idle}, {"brewing", brewing}, {"stats", stats}}
+}
+
+// runRenderBenchmark implements the hidden --bench-render mode: it renders
+// each scene at each size without the view cache and prints the time and
+// allocations per frame, for spotting regressions as the view grows.
+func runRenderBenchmark(stdout io.Writer) int {
+	fmt.Fprintf(stdout, "%-9s %-8s %12s %12s %10s\n", "size", "scene", "ns/frame", "B/frame", "allocs")
+	for _, size := range benchSizes {
+		for _, scene := range benchScenes(size.width, size.height) {
+			m := scene.model
+			result := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					m.render()
+				}
+			})
+			fmt.Fprintf(stdout, "%-9s %-8s %12d %12d %10d\n", fmt.Sprintf("%dx%d", size.width, size.height), scene.name,
+				result.NsPerOp(), result.AllocedBytesPerOp(), result.AllocsPerOp())
+		}
+	}
+	return 0
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// reportDays is the number of days covered by "go-brew report -week".
+const reportDays = 7
+
+// caffeineMg is a rough estimate of the caffeine in one cup of each built-in
+// tea, in milligrams. Teas not listed here are left out of the total.
+var caffeineMg = map[string]int{
+	"black tea": 50,
+	"oolong":    40,
+	"green tea": 30,
+	"white tea": 25,
+	"rooibos":   0,
+	"herbal":    0,
+}
+
+// weekReport summarises the brew history of the last reportDays days.
+type weekReport struct {
+	From, To        time.Time // First and last day covered
+	PerDay          []int     // Brews on each day, oldest first
+	Cups            int       // Brews in the period
+	Favorite        string    // Most brewed tea, empty if there were no brews
+	FavoriteCups    int       // Brews of the favorite tea
+	CaffeineMg      int       // Estimated caffeine of the teas with a known amount
+	UnknownCaffeine int       // Brews of teas without a caffeine estimate
+	LongestStreak   int       // Most consecutive days with at least one brew
+
+	Formats localeFormats // How the days are written
+}
+
+// runReportCommand implements "go-brew report" and returns the process exit
+// code:
+//
+//	go-brew report -week [-markdown] [-history file] [-locale name]
+//
+// The weekly summary is currently the only report, so -week is the default.
+func runReportCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Bool("week", true, "summarise the last 7 days")
+	markdown := fs.Bool("markdown", false, "print the report as Markdown")
+	path := fs.String("history", defaultHistoryPath(), "brew history `file` to read")
+	locale := fs.String("locale", "", "write dates for `locale`, such as en_GB (default from display.locale or the environment)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\nusage: go-brew report -week [-markdown] [-history file] [-locale name]\n", fs.Arg(0))
+		return 2
+	}
+
+	key, err := commandHistoryKey()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	records, err := loadHistory(*path, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	report := buildWeekReport(records, now())
+	report.Formats = commandLocale(*locale)
+	if *markdown {
+		report.writeMarkdown(stdout)
+	} else {
+		report.writeText(stdout)
+	}
+	return 0
+}
+
+// buildWeekReport summarises the records from the reportDays days up to and
+// including the day of today.
+func buildWeekReport(records []brewRecord, today time.Time) weekReport {
+	r := weekReport{
+		From:    today.AddDate(0, 0, 1-reportDays),
+		To:      today,
+		PerDay:  brewsPerDay(records, reportDays, today),
+		Formats: defaultLocale,
+	}
+
+	from := r.From.Format(time.DateOnly)
+	var week []brewRecord
+	for _, rec := range records {
+		// Dates in YYYY-MM-DD form compare in calendar order
+		if day := rec.Time.In(today.Location()).Format(time.DateOnly); day >= from && day <= today.Format(time.DateOnly) {
+			week = append(week, rec)
+		}
+	}
+	r.Cups = len(week)
+
+	if teas, counts := brewsPerTea(week); len(teas) > 0 {
+		r.Favorite, r.FavoriteCups = teas[0], counts[0]
+	}
+	for _, rec := range week {
+		if mg, ok := caffeineMg[strings.ToLower(rec.Tea)]; ok {
+			r.CaffeineMg += mg
+		} else {
+			r.UnknownCaffeine++
+		}
+	}
+
+	streak := 0
+	for _, n := range r.PerDay {
+		if n == 0 {
+			streak = 0
+			continue
+		}
+		streak++
+		r.LongestStreak = max(r.LongestStreak, streak)
+	}
+	return r
+}
+
+// caffeineLabel formats the caffeine estimate, noting brews it leaves out.
+func (r weekReport) caffeineLabel() string {
+	label := fmt.Sprintf("~%d mg", r.CaffeineMg)
+	if r.UnknownCaffeine > 0 {
+		label += fmt.Sprintf(" (%d %s of unknown teas not counted)", r.UnknownCaffeine, plural(r.UnknownCaffeine, "cup", "cups"))
+	}
+	return label
+}
+
+// favoriteLabel formats the most brewed tea with its number of cups.
+func (r weekReport) favoriteLabel() string {
+	if r.Favorite == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%s (%d %s)", r.Favorite, r.FavoriteCups, plural(r.FavoriteCups, "cup", "cups"))
+}
+
+// day formats a day of the report with its weekday, such as "Mon Mar 4".
+func (r weekReport) day(t time.Time) string {
+	return t.Format("Mon") + " " + r.Formats.dayMonth(t)
+}
+
+// writeText prints the report for the terminal.
+func (r weekReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "Weekly brew report, %s – %s\n\n", r.day(r.From), r.day(r.To))
+	fmt.Fprintf(w, "  Cups            %d\n", r.Cups)
+	fmt.Fprintf(w, "  Favorite tea    %s\n", r.favoriteLabel())
+	fmt.Fprintf(w, "  Caffeine        %s\n", r.caffeineLabel())
+	fmt.Fprintf(w, "  Longest streak  %d %s\n\n", r.LongestStreak, plural(r.LongestStreak, "day", "days"))
+	for i, n := range r.PerDay {
+		fmt.Fprintf(w, "  %s  %s %d\n", r.From.AddDate(0, 0, i).Format("Mon"), strings.Repeat("█", n), n)
+	}
+}
+
+// writeMarkdown prints the report as Markdown, for pasting into notes.
+func (r weekReport) writeMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "## Weekly brew report, %s – %s\n\n", r.day(r.From), r.day(r.To))
+	fmt.Fprintf(w, "- **Cups:** %d\n", r.Cups)
+	fmt.Fprintf(w, "- **Favorite tea:** %s\n", r.favoriteLabel())
+	fmt.Fprintf(w, "- **Caffeine:** %s\n", r.caffeineLabel())
+	fmt.Fprintf(w, "- **Longest streak:** %d %s\n\n", r.LongestStreak, plural(r.LongestStreak, "day", "days"))
+	fmt.Fprintln(w, "| Day | Cups |")
+	fmt.Fprintln(w, "|-----|------|")
+	for i, n := range r.PerDay {
+		fmt.Fprintf(w, "| %s | %d |\n", r.day(r.From.AddDate(0, 0, i)), n)
+	}
+}
+
+// plural returns one if n is 1 and many otherwise.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
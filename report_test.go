@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildWeekReport(t *testing.T) {
+	today := now()
+	records := []brewRecord{
+		{Time: today.AddDate(0, 0, -10), Tea: "Black Tea"}, // Before the week
+		{Time: today.AddDate(0, 0, -6), Tea: "Black Tea"},
+		{Time: today.AddDate(0, 0, -2), Tea: "Green Tea"},
+		{Time: today.AddDate(0, 0, -1), Tea: "Green Tea"},
+		{Time: today.Add(-time.Hour), Tea: "Genmaicha"},
+	}
+	r := buildWeekReport(records, today)
+	if r.Cups != 4 {
+		t.Errorf("Expected 4 cups, got %d", r.Cups)
+	}
+	if r.Favorite != "Green Tea" || r.FavoriteCups != 2 {
+		t.Errorf("Expected favorite Green Tea with 2 cups, got %s with %d", r.Favorite, r.FavoriteCups)
+	}
+	if r.CaffeineMg != 110 || r.UnknownCaffeine != 1 {
+		t.Errorf("Expected 110 mg with 1 unknown cup, got %d mg with %d", r.CaffeineMg, r.UnknownCaffeine)
+	}
+	if r.LongestStreak != 3 {
+		t.Errorf("Expected longest streak of 3 days, got %d", r.LongestStreak)
+	}
+}
+
+func TestReportCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	if err := appendHistory(path, nil, brewRecord{Time: now(), Tea: "Oolong", Duration: Duration{3 * time.Minute}}); err != nil {
+		t.Fatalf("Failed to append history: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runReportCommand([]string{"-week", "-history", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	for _, want := range []string{"Cups            1", "Oolong (1 cup)", "~40 mg", "1 day"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := runReportCommand([]string{"-week", "-markdown", "-history", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "## Weekly brew report") || !strings.Contains(stdout.String(), "| Fri Mar 1 | 1 |") {
+		t.Errorf("Expected Markdown report, got:\n%s", stdout.String())
+	}
+
+	if code := runReportCommand([]string{"monthly"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an unknown argument, got %d", code)
+	}
+}
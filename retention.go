@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// HistoryLimits caps how much of the brew log is kept, so it doesn't
+// grow without bound over the years. Zero values mean no limit.
+type HistoryLimits struct {
+	MaxEntries int // Most recent brews to keep, 0 for no limit
+	MaxDays    int // Days of brews to keep, 0 for no limit
+}
+
+// limited reports whether any retention limit is set.
+func (r HistoryLimits) limited() bool {
+	return r.MaxEntries > 0 || r.MaxDays > 0
+}
+
+// String describes the limits, e.g. "the last 500 brews from 365 days".
+func (r HistoryLimits) String() string {
+	switch {
+	case r.MaxEntries > 0 && r.MaxDays > 0:
+		return fmt.Sprintf("the last %d %s from %d %s", r.MaxEntries, plural(r.MaxEntries, "brew", "brews"), r.MaxDays, plural(r.MaxDays, "day", "days"))
+	case r.MaxEntries > 0:
+		return fmt.Sprintf("the last %d %s", r.MaxEntries, plural(r.MaxEntries, "brew", "brews"))
+	case r.MaxDays > 0:
+		return fmt.Sprintf("brews from the last %d %s", r.MaxDays, plural(r.MaxDays, "day", "days"))
+	}
+	return "every brew"
+}
+
+// prune splits records, oldest first, into those kept under the limits at
+// time at and those dropped. Brews older than MaxDays go first, then the
+// oldest of the rest until at most MaxEntries remain.
+func (r HistoryLimits) prune(records []brewRecord, at time.Time) (kept, dropped []brewRecord) {
+	start := 0
+	if r.MaxDays > 0 {
+		cutoff := at.AddDate(0, 0, -r.MaxDays)
+		for start < len(records) && records[start].Time.Before(cutoff) {
+			start++
+		}
+	}
+	if r.MaxEntries > 0 && len(records)-start > r.MaxEntries {
+		start = len(records) - r.MaxEntries
+	}
+	return records[start:], records[:start]
+}
+
+// pruneHistory drops the brews outside the retention limits from the brew
+// log at path and returns them. The log is only rewritten when something is
+// dropped, and then in one go, so a failed write leaves it as it was.
+func pruneHistory(path string, key *historyKey, r HistoryLimits, at time.Time) ([]brewRecord, error) {
+	if !r.limited() {
+		return nil, nil
+	}
+	records, err := loadHistory(path, key)
+	if err != nil {
+		return nil, err
+	}
+	kept, dropped := r.prune(records, at)
+	if len(dropped) == 0 {
+		return nil, nil
+	}
+	return dropped, writeHistory(path, key, kept)
+}
+
+// historyUsage lists the "go-brew history" commands.
+const historyUsage = `usage: go-brew history prune [-dry-run] [-max-entries n] [-max-days n] [-history file] [-config file]
+       go-brew history encrypt|decrypt [-history file] [-config file]
+       go-brew history import [-format csv|steepster] [-map field=column,...] [-dry-run] export.csv`
+
+// runHistoryCommand implements "go-brew history" and returns the process
+// exit code:
+//
+//	go-brew history prune [-dry-run] [-max-entries n] [-max-days n]  drop brews beyond the retention limits
+//	go-brew history encrypt                                          encrypt the brew log now
+//	go-brew history decrypt                                          turn the brew log back into plain text
+//	go-brew history import export.csv                                add brews from another app, see runHistoryImport
+//
+// Each also takes -history and -config. The prune limits default to
+// history.max_entries and history.max_days from the config files; the flags
+// override them for one run.
+func runHistoryCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, historyUsage)
+		return 2
+	}
+	if args[0] == "import" {
+		return runHistoryImport(args[1:], stdout, stderr)
+	}
+	sub := args[0]
+	if sub != "prune" && sub != "encrypt" && sub != "decrypt" {
+		fmt.Fprintf(stderr, "unknown history command %q\n%s\n", sub, historyUsage)
+		return 2
+	}
+	fs := flag.NewFlagSet("history "+sub, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to read")
+	fs.StringVar(&config.HistoryFile, "history", config.HistoryFile, "brew history `file` to change")
+	var dryRun *bool
+	var maxEntries, maxDays *int
+	if sub == "prune" {
+		dryRun = fs.Bool("dry-run", false, "show what would be removed without changing the file")
+		maxEntries = fs.Int("max-entries", 0, "keep at most `n` of the most recent brews (default history.max_entries)")
+		maxDays = fs.Int("max-days", 0, "keep brews from the last `n` days (default history.max_days)")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\n%s\n", fs.Arg(0), historyUsage)
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	if sub != "prune" {
+		return convertHistory(config, sub == "encrypt", stdout, stderr)
+	}
+	key, err := config.historyKey(os.Getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	keep := config.HistoryLimits
+	if config.setFlags["max-entries"] {
+		keep.MaxEntries = *maxEntries
+	}
+	if config.setFlags["max-days"] {
+		keep.MaxDays = *maxDays
+	}
+	if keep.MaxEntries < 0 || keep.MaxDays < 0 {
+		fmt.Fprintln(stderr, "error: -max-entries and -max-days must not be negative")
+		return 2
+	}
+	if !keep.limited() {
+		fmt.Fprintln(stderr, "error: no limit to prune to; set history.max_entries or history.max_days, or pass -max-entries or -max-days")
+		return 2
+	}
+
+	records, err := loadHistory(config.HistoryFile, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	kept, dropped := keep.prune(records, now())
+	if len(dropped) == 0 {
+		fmt.Fprintf(stdout, "Nothing to prune: %s holds %d %s, within %s\n", config.HistoryFile, len(records), plural(len(records), "brew", "brews"), keep)
+		return 0
+	}
+	span := fmt.Sprintf("%d %s from %s to %s", len(dropped), plural(len(dropped), "brew", "brews"),
+		dropped[0].Time.Local().Format(time.DateOnly), dropped[len(dropped)-1].Time.Local().Format(time.DateOnly))
+	if *dryRun {
+		fmt.Fprintf(stdout, "Would remove %s, keeping %s (%d left)\n", span, keep, len(kept))
+		return 0
+	}
+	if err := writeHistory(config.HistoryFile, key, kept); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Removed %s, keeping %s (%d left)\n", span, keep, len(kept))
+	return 0
+}
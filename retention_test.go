@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dailyBrews returns a brew finished at noon on each of the n days up to
+// and including the day of now, oldest first.
+func dailyBrews(n int) []brewRecord {
+	var records []brewRecord
+	for i := n - 1; i >= 0; i-- {
+		records = append(records, brewRecord{Time: now().AddDate(0, 0, -i).Add(-2 * time.Hour), Tea: "Oolong", Duration: Duration{3 * time.Minute}})
+	}
+	return records
+}
+
+func TestHistoryLimitsPrune(t *testing.T) {
+	records := dailyBrews(10)
+	tests := []struct {
+		limits HistoryLimits
+		kept   int
+	}{
+		{HistoryLimits{}, 10},
+		{HistoryLimits{MaxEntries: 4}, 4},
+		{HistoryLimits{MaxEntries: 20}, 10},
+		{HistoryLimits{MaxDays: 3}, 3},
+		{HistoryLimits{MaxEntries: 2, MaxDays: 3}, 2},
+		{HistoryLimits{MaxEntries: 6, MaxDays: 3}, 3},
+	}
+	for _, tt := range tests {
+		kept, dropped := tt.limits.prune(records, now())
+		if len(kept) != tt.kept || len(kept)+len(dropped) != len(records) {
+			t.Errorf("%+v: expected %d brews kept, got %d kept and %d dropped", tt.limits, tt.kept, len(kept), len(dropped))
+			continue
+		}
+		if len(kept) > 0 && !kept[len(kept)-1].Time.Equal(records[len(records)-1].Time) {
+			t.Errorf("%+v: expected the newest brew to be kept", tt.limits)
+		}
+	}
+}
+
+func TestRecordBrewPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	records := dailyBrews(5)
+	if err := writeHistory(path, nil, records[:4]); err != nil {
+		t.Fatal(err)
+	}
+	if msg := recordBrewCmd(path, nil, records[4], HistoryLimits{MaxEntries: 3})(); msg != nil {
+		t.Fatalf("Expected the brew to be recorded, got %v", msg)
+	}
+	got, err := loadHistory(path, nil)
+	if err != nil || len(got) != 3 || !got[0].Time.Equal(records[2].Time) || !got[2].Time.Equal(records[4].Time) {
+		t.Errorf("Expected the three newest brews to remain, got %+v, %v", got, err)
+	}
+}
+
+func TestHistoryPruneCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	if err := writeHistory(path, nil, dailyBrews(10)); err != nil {
+		t.Fatal(err)
+	}
+	config := writeConfig(t, "[history]\nmax_entries = 8\n")
+	var stdout, stderr bytes.Buffer
+
+	args := []string{"prune", "-dry-run", "-config", config, "-history", path}
+	if code := runHistoryCommand(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Would remove 2 brews from 2024-02-21 to 2024-02-22") {
+		t.Errorf("Expected the dry run to list the brews to remove, got %q", stdout.String())
+	}
+	if records, _ := loadHistory(path, nil); len(records) != 10 {
+		t.Errorf("Expected the dry run to leave the history alone, got %d brews", len(records))
+	}
+
+	stdout.Reset()
+	args = []string{"prune", "-max-days", "4", "-config", config, "-history", path}
+	if code := runHistoryCommand(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if records, _ := loadHistory(path, nil); len(records) != 4 {
+		t.Errorf("Expected the 4 brews from the last 4 days to remain, got %d", len(records))
+	}
+	if !strings.Contains(stdout.String(), "Removed 6 brews") {
+		t.Errorf("Expected the removed brews to be reported, got %q", stdout.String())
+	}
+
+	empty := writeConfig(t, "")
+	if code := runHistoryCommand([]string{"prune", "-config", empty, "-history", path}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without any limit, got %d", code)
+	}
+	if code := runHistoryCommand([]string{"trim"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an unknown command, got %d", code)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bluetooth characteristics of Acaia-compatible scales. Scales from 2021 on
+// notify weights on one characteristic and take commands on another; older
+// ones use a single characteristic for both.
+const (
+	acaiaNotifyUUID = "49535343-1e4d-4bd9-ba61-23c647249616"
+	acaiaWriteUUID  = "49535343-8841-43f4-a8d4-ecbe34729bb3"
+	acaiaLegacyUUID = "00002a80-0000-1000-8000-00805f9b34fb"
+)
+
+// acaiaHeartbeat is how often the scale is told the connection is alive;
+// it stops sending weights after a few seconds without.
+const acaiaHeartbeat = 3 * time.Second
+
+// Acaia message types.
+const (
+	acaiaHeartbeatMsg = 0  // Keeps the connection alive
+	acaiaIdentMsg     = 11 // Identifies the app after connecting
+	acaiaEventMsg     = 12 // Requests events from, or reports events by, the scale
+	acaiaWeightEvent  = 5  // Event carrying the weight
+)
+
+// acaiaHeader starts every message to and from the scale.
+var acaiaHeader = []byte{0xEF, 0xDD}
+
+// acaiaMessage encodes a message: the header, the type, the payload and a
+// two-byte checksum summing the even and the odd payload bytes.
+func acaiaMessage(msgType byte, payload ...byte) []byte {
+	msg := append(append(bytes.Clone(acaiaHeader), msgType), payload...)
+	var even, odd byte
+	for i, b := range payload {
+		if i%2 == 0 {
+			even += b
+		} else {
+			odd += b
+		}
+	}
+	return append(msg, even, odd)
+}
+
+// acaiaHandshake returns the messages that make the scale start sending
+// weights: the app's identity, then which events to report.
+func acaiaHandshake() [][]byte {
+	ident := acaiaMessage(acaiaIdentMsg, []byte("012345678901234")...)
+	events := []byte{0, 1, 1, 2, 2, 5, 3, 4}
+	notify := acaiaMessage(acaiaEventMsg, append([]byte{byte(len(events) + 1)}, events...)...)
+	return [][]byte{ident, notify}
+}
+
+// acaiaPing returns the heartbeat message.
+func acaiaPing() []byte {
+	return acaiaMessage(acaiaHeartbeatMsg, 2, 0)
+}
+
+// parseAcaiaWeight finds a weight event in a notification from the scale
+// and returns the weight in grams. The value is sent as a little-endian
+// number with the count of decimals and a sign flag after it.
+func parseAcaiaWeight(data []byte) (float64, bool) {
+	i := bytes.Index(data, acaiaHeader)
+	if i < 0 {
+		return 0, false
+	}
+	msg := data[i:]
+	if len(msg) < 11 || msg[2] != acaiaEventMsg || msg[4] != acaiaWeightEvent {
+		return 0, false
+	}
+	w := msg[5:]
+	grams := float64(int(w[1])<<8 | int(w[0]))
+	for range min(w[4], 4) {
+		grams /= 10
+	}
+	if w[5]&0x02 != 0 {
+		grams = -grams
+	}
+	return grams, true
+}
+
+// scaleMsg delivers a weight read from the scale.
+type scaleMsg struct {
+	grams float64 // Weight on the scale in grams
+}
+
+// ratioPattern matches a preset's leaf-to-water ratio, such as
+// "3g per 250ml" or "5 g / 100 ml".
+var ratioPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*g\s*(?:per|/|:|to|for)\s*(\d+(?:\.\d+)?)\s*ml`)
+
+// parseRatio returns the millilitres of water per gram of leaf in a ratio,
+// or false if it names no ratio in grams and millilitres.
+func parseRatio(ratio string) (float64, bool) {
+	match := ratioPattern.FindStringSubmatch(strings.ToLower(ratio))
+	if match == nil {
+		return 0, false
+	}
+	grams, errG := strconv.ParseFloat(match[1], 64)
+	ml, errM := strconv.ParseFloat(match[2], 64)
+	if errG != nil || errM != nil || grams <= 0 {
+		return 0, false
+	}
+	return ml / grams, true
+}
+
+// scaleLabel shows the leaf on the scale while a preset is being prepared
+// and, for presets with a ratio, how much water it needs. It returns "" until
+// the scale has sent a weight, and while nothing is on it.
+func (m model) scaleLabel() string {
+	if m.leafGrams < 0.1 {
+		return ""
+	}
+	label := fmt.Sprintf("%s%.1fg of leaf", m.icon("⚖️", "Scale:"), m.leafGrams)
+	if perGram, ok := parseRatio(m.currentPreset().Info.Ratio); ok {
+		label += fmt.Sprintf(" · pour %.0fml of water", m.leafGrams*perGram)
+	}
+	return label
+}
@@ -0,0 +1,186 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// The BlueZ Bluetooth service on the system bus.
+const (
+	bluezDest      = "org.bluez"
+	bluezDevice    = "org.bluez.Device1"
+	bluezChar      = "org.bluez.GattCharacteristic1"
+	dbusProperties = "org.freedesktop.DBus.Properties"
+)
+
+// scaleConnectTimeout is how long to wait for the scale to connect and list
+// its services.
+const scaleConnectTimeout = 15 * time.Second
+
+// bluezObjects are the objects BlueZ manages: interfaces and their
+// properties by object path.
+type bluezObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+
+// watchScale connects to the Acaia-compatible scale named by device, its
+// Bluetooth address or name, and sends every change of weight until ctx is
+// cancelled. Failures are reported in the status line.
+func watchScale(ctx context.Context, device string, send func(tea.Msg)) {
+	if err := readScale(ctx, device, send); err != nil && ctx.Err() == nil {
+		send(errMsg{fmt.Errorf("scale %s: %w", device, err)})
+	}
+}
+
+// readScale does the work of watchScale through BlueZ, which needs no cgo
+// and comes with every desktop Linux. The scale must have been paired, for
+// instance with bluetoothctl.
+func readScale(ctx context.Context, device string, send func(tea.Msg)) error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return fmt.Errorf("connecting to BlueZ: %w", err)
+	}
+	objects, err := bluezManagedObjects(ctx, conn)
+	if err != nil {
+		return err
+	}
+	devPath, ok := findScaleDevice(objects, device)
+	if !ok {
+		return errors.New("not found; pair it first, e.g. with bluetoothctl")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	connectCtx, stop := context.WithTimeout(ctx, scaleConnectTimeout)
+	defer stop()
+	dev := conn.Object(bluezDest, devPath)
+	if err := dev.CallWithContext(connectCtx, bluezDevice+".Connect", 0).Err; err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	for {
+		resolved, err := dev.GetProperty(bluezDevice + ".ServicesResolved")
+		if err == nil && resolved.Value() == true {
+			break
+		}
+		select {
+		case <-connectCtx.Done():
+			return errors.New("connecting: timed out waiting for its services")
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	if objects, err = bluezManagedObjects(ctx, conn); err != nil {
+		return err
+	}
+	notifyPath, writePath, ok := findScaleChars(objects, devPath)
+	if !ok {
+		return errors.New("not an Acaia-compatible scale")
+	}
+
+	if err := conn.AddMatchSignal(dbus.WithMatchObjectPath(notifyPath), dbus.WithMatchInterface(dbusProperties), dbus.WithMatchMember("PropertiesChanged")); err != nil {
+		return err
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+
+	char := conn.Object(bluezDest, notifyPath)
+	if err := char.CallWithContext(ctx, bluezChar+".StartNotify", 0).Err; err != nil {
+		return fmt.Errorf("subscribing to weights: %w", err)
+	}
+	defer char.Call(bluezChar+".StopNotify", 0)
+	write := func(msg []byte) error {
+		return conn.Object(bluezDest, writePath).CallWithContext(ctx, bluezChar+".WriteValue", 0, msg, map[string]dbus.Variant{}).Err
+	}
+	for _, msg := range acaiaHandshake() {
+		if err := write(msg); err != nil {
+			return fmt.Errorf("starting the scale: %w", err)
+		}
+	}
+
+	heartbeat := time.NewTicker(acaiaHeartbeat)
+	defer heartbeat.Stop()
+	last := math.NaN()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := write(acaiaPing()); err != nil {
+				return fmt.Errorf("lost the connection: %w", err)
+			}
+		case sig := <-signals:
+			if sig.Path != notifyPath || len(sig.Body) < 2 {
+				continue
+			}
+			changed, _ := sig.Body[1].(map[string]dbus.Variant)
+			value, _ := changed["Value"].Value().([]byte)
+			if grams, ok := parseAcaiaWeight(value); ok && grams != last {
+				last = grams
+				send(scaleMsg{grams})
+			}
+		}
+	}
+}
+
+// bluezManagedObjects lists the Bluetooth adapters, devices and their
+// services known to BlueZ.
+func bluezManagedObjects(ctx context.Context, conn *dbus.Conn) (bluezObjects, error) {
+	var objects bluezObjects
+	err := conn.Object(bluezDest, "/").CallWithContext(ctx, "org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objects)
+	if err != nil {
+		return nil, fmt.Errorf("listing Bluetooth devices: %w", err)
+	}
+	return objects, nil
+}
+
+// findScaleDevice returns the path of the device whose address or name is
+// device, regardless of case.
+func findScaleDevice(objects bluezObjects, device string) (dbus.ObjectPath, bool) {
+	for path, ifaces := range objects {
+		props, ok := ifaces[bluezDevice]
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"Address", "Name", "Alias"} {
+			if v, ok := props[key].Value().(string); ok && strings.EqualFold(v, device) {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findScaleChars returns the characteristics of the scale at devPath that
+// notify weights and take commands, which are the same on older scales.
+func findScaleChars(objects bluezObjects, devPath dbus.ObjectPath) (notify, write dbus.ObjectPath, ok bool) {
+	var legacy dbus.ObjectPath
+	for path, ifaces := range objects {
+		props, found := ifaces[bluezChar]
+		if !found || !strings.HasPrefix(string(path), string(devPath)+"/") {
+			continue
+		}
+		uuid, _ := props["UUID"].Value().(string)
+		switch strings.ToLower(uuid) {
+		case acaiaNotifyUUID:
+			notify = path
+		case acaiaWriteUUID:
+			write = path
+		case acaiaLegacyUUID:
+			legacy = path
+		}
+	}
+	if notify != "" && write != "" {
+		return notify, write, true
+	}
+	if legacy != "" {
+		return legacy, legacy, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestFindScale(t *testing.T) {
+	char := func(uuid string) map[string]map[string]dbus.Variant {
+		return map[string]map[string]dbus.Variant{bluezChar: {"UUID": dbus.MakeVariant(uuid)}}
+	}
+	objects := bluezObjects{
+		"/org/bluez/hci0/dev_AA":                {bluezDevice: {"Address": dbus.MakeVariant("AA:BB:CC:DD:EE:01"), "Name": dbus.MakeVariant("Speaker")}},
+		"/org/bluez/hci0/dev_BB":                {bluezDevice: {"Address": dbus.MakeVariant("AA:BB:CC:DD:EE:02"), "Name": dbus.MakeVariant("PEARLS 1234")}},
+		"/org/bluez/hci0/dev_BB/service1/char1": char(acaiaNotifyUUID),
+		"/org/bluez/hci0/dev_BB/service1/char2": char(acaiaWriteUUID),
+		"/org/bluez/hci0/dev_CC":                {bluezDevice: {"Address": dbus.MakeVariant("AA:BB:CC:DD:EE:03"), "Name": dbus.MakeVariant("LUNAR")}},
+		"/org/bluez/hci0/dev_CC/service1/char1": char(acaiaLegacyUUID),
+		"/org/bluez/hci0/dev_AA/service1/char1": char(acaiaNotifyUUID),
+	}
+
+	if path, ok := findScaleDevice(objects, "pearls 1234"); !ok || path != "/org/bluez/hci0/dev_BB" {
+		t.Errorf("Expected the scale found by name, got %q %v", path, ok)
+	}
+	if path, ok := findScaleDevice(objects, "aa:bb:cc:dd:ee:03"); !ok || path != "/org/bluez/hci0/dev_CC" {
+		t.Errorf("Expected the scale found by address, got %q %v", path, ok)
+	}
+	if _, ok := findScaleDevice(objects, "Kettle"); ok {
+		t.Error("Expected an unknown device not to be found")
+	}
+
+	notify, write, ok := findScaleChars(objects, "/org/bluez/hci0/dev_BB")
+	if !ok || notify != "/org/bluez/hci0/dev_BB/service1/char1" || write != "/org/bluez/hci0/dev_BB/service1/char2" {
+		t.Errorf("Expected separate characteristics, got %q %q %v", notify, write, ok)
+	}
+	notify, write, ok = findScaleChars(objects, "/org/bluez/hci0/dev_CC")
+	if !ok || notify != write || notify != "/org/bluez/hci0/dev_CC/service1/char1" {
+		t.Errorf("Expected the legacy characteristic for both, got %q %q %v", notify, write, ok)
+	}
+	if _, _, ok := findScaleChars(objects, "/org/bluez/hci0/dev_AA"); ok {
+		t.Error("Expected a device without both characteristics to be rejected")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchScale is unavailable off Linux, where go-brew has no Bluetooth stack
+// to talk to the scale through.
+func watchScale(ctx context.Context, device string, send func(tea.Msg)) {
+	send(errMsg{errors.New("Bluetooth scales are only supported on Linux, through BlueZ")})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAcaiaMessage(t *testing.T) {
+	want := []byte{0xEF, 0xDD, 0, 2, 0, 2, 0}
+	if got := acaiaPing(); !bytes.Equal(got, want) {
+		t.Errorf("Expected heartbeat % x, got % x", want, got)
+	}
+	got := acaiaMessage(acaiaEventMsg, 1, 2, 3, 200, 100)
+	if even, odd := got[len(got)-2], got[len(got)-1]; even != 104 || odd != 202 {
+		t.Errorf("Expected checksum 104 202, got %d %d", even, odd)
+	}
+}
+
+func TestParseAcaiaWeight(t *testing.T) {
+	tests := []struct {
+		data  []byte
+		grams float64
+		ok    bool
+	}{
+		{[]byte{0xEF, 0xDD, 12, 9, 5, 0x1E, 0x00, 0, 0, 1, 0, 0, 0}, 3, true},
+		{[]byte{0xEF, 0xDD, 12, 9, 5, 0x39, 0x30, 0, 0, 2, 0, 0, 0}, 123.45, true},
+		{[]byte{0xEF, 0xDD, 12, 9, 5, 0x0F, 0x00, 0, 0, 1, 2, 0, 0}, -1.5, true},
+		{[]byte{0x00, 0xEF, 0xDD, 12, 9, 5, 0x2C, 0x01, 0, 0, 1, 0, 0, 0}, 30, true},
+		{[]byte{0xEF, 0xDD, 12, 9, 7, 0x1E, 0x00, 0, 0, 1, 0, 0, 0}, 0, false},
+		{[]byte{0xEF, 0xDD, 12, 9, 5}, 0, false},
+		{[]byte{1, 2, 3}, 0, false},
+	}
+	for _, tt := range tests {
+		grams, ok := parseAcaiaWeight(tt.data)
+		if ok != tt.ok || (ok && (grams-tt.grams > 1e-9 || tt.grams-grams > 1e-9)) {
+			t.Errorf("parseAcaiaWeight(% x): expected %v %v, got %v %v", tt.data, tt.grams, tt.ok, grams, ok)
+		}
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		ratio string
+		per   float64
+		ok    bool
+	}{
+		{"3g per 250ml", 250.0 / 3, true},
+		{"5 g / 100 ml", 20, true},
+		{"1g:15ml", 15, true},
+		{"2 tsp per cup", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		per, ok := parseRatio(tt.ratio)
+		if ok != tt.ok || per != tt.per {
+			t.Errorf("parseRatio(%q): expected %v %v, got %v %v", tt.ratio, tt.per, tt.ok, per, ok)
+		}
+	}
+}
+
+func TestScaleLabel(t *testing.T) {
+	config := NewConfig()
+	config.Presets = append([]TeaPreset(nil), DefaultTeaPresets...)
+	config.Presets[0].Info.Ratio = ""
+	m := initialModel(config)
+	m.presetIdx = 1
+	m.width, m.height = 80, 24
+
+	if m.scaleLabel() != "" {
+		t.Error("Expected no scale label before the scale reports")
+	}
+	newModel, _ := m.Update(scaleMsg{grams: 3.2})
+	m = newModel.(model)
+	if got := m.scaleLabel(); got != "⚖️ 3.2g of leaf · pour 400ml of water" {
+		t.Errorf("Unexpected label %q", got)
+	}
+	if !strings.Contains(m.View(), "pour 400ml of water") {
+		t.Error("Expected the water amount in the view")
+	}
+
+	m.presetIdx = 0
+	if got := m.scaleLabel(); got != "⚖️ 3.2g of leaf" {
+		t.Errorf("Expected only the weight for a preset without a ratio, got %q", got)
+	}
+	newModel, _ = m.Update(scaleMsg{grams: 0})
+	if got := newModel.(model).scaleLabel(); got != "" {
+		t.Errorf("Expected no label for an empty scale, got %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Screensaver drift: the clock moves to another of these positions across
+// and down the screen each minute, so no cell stays lit for long.
+const (
+	screensaverColumns = 7
+	screensaverRows    = 5
+)
+
+// asleep reports whether the screensaver is shown: the configured idle time
+// has passed since the last key or finished brew and nothing is counting or being typed.
+func (m model) asleep() bool {
+	if m.config.Screensaver <= 0 || m.lastActive.IsZero() {
+		return false
+	}
+	if m.isBrewing() || m.inputKind != inputNone || !m.coolStart.IsZero() {
+		return false
+	}
+	return m.clock.Sub(m.lastActive) >= m.config.Screensaver
+}
+
+// screensaverLabel returns the time the screensaver shows, or "" while it is
+// off. It is part of the view key, so the frame changes once a minute.
+func (m model) screensaverLabel() string {
+	if !m.asleep() {
+		return ""
+	}
+	return m.config.Formats.clock(m.clock)
+}
+
+// renderScreensaver draws the dimmed teapot and clock, placed by the minute
+// so the display doesn't burn in.
+func (m model) renderScreensaver() string {
+	faint := lipgloss.NewStyle().Faint(true).Foreground(lipgloss.Color(m.config.Colors.Idle))
+	teapot := "🫖"
+	if !m.config.Emoji {
+		teapot = "go-brew"
+	}
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		teapot,
+		faint.Bold(true).Render(m.screensaverLabel()),
+		faint.Render("press any key"),
+	)
+	minute := m.clock.Minute()
+	x := lipgloss.Position(float64(minute%screensaverColumns) / (screensaverColumns - 1))
+	y := lipgloss.Position(float64(minute%screensaverRows) / (screensaverRows - 1))
+	return m.clearImages() + lipgloss.Place(m.width, m.height, x, y, content)
+}
+
+// wake restarts the idle time at a key press or a finished brew, ending the
+// screensaver or postponing it.
+func (m model) wake(at time.Time) model {
+	m.lastActive = at
+	return m
+}
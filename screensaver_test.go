@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScreensaver(t *testing.T) {
+	config := NewConfig()
+	config.Screensaver = 10 * time.Minute
+	m := initialModel(config)
+	m.width, m.height = 60, 20
+
+	m.clock = m.clock.Add(9 * time.Minute)
+	if m.asleep() {
+		t.Fatal("Expected the screensaver to wait for the full idle time")
+	}
+	m.clock = m.clock.Add(time.Minute)
+	if !m.asleep() || !contains(m.View(), "14:10") || contains(m.View(), "Press 's' to start") {
+		t.Fatalf("Expected the screensaver clock after 10 idle minutes, got\n%s", m.View())
+	}
+
+	// The waking key is swallowed, so it doesn't start a brew
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = newModel.(model)
+	if m.asleep() || m.state != StateIdle {
+		t.Errorf("Expected the key to wake the screen without starting, got state %v", m.state)
+	}
+	if !contains(m.View(), "Press 's' to start") {
+		t.Errorf("Expected the timer after waking, got\n%s", m.View())
+	}
+}
+
+func TestScreensaverStaysOffWhileBrewing(t *testing.T) {
+	config := NewConfig()
+	config.Screensaver = time.Minute
+	m := initialModel(config)
+	m.state = StateBrewing
+	m.clock = m.clock.Add(time.Hour)
+	if m.asleep() {
+		t.Error("Expected no screensaver during a brew")
+	}
+
+	m.state = StateIdle
+	m.config.Screensaver = 0
+	if m.asleep() {
+		t.Error("Expected no screensaver when it is disabled")
+	}
+}
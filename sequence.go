@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// sequenceStep is one timer in a sequence loaded with -sequence.
+type sequenceStep struct {
+	Label    string        // Shown while the step runs and recorded in the history
+	Duration time.Duration // Length of the step
+	Preset   bool          // Whether the step names a tea preset rather than a duration
+	Hint     string        // Instruction shown while a recipe step runs
+	Tea      string        // Tea a recipe step records in the history instead of its label
+}
+
+// loadSequence reads the steps of a sequence from path, or from stdin when
+// path is "-". Preset names are resolved against presets.
+func loadSequence(path string, stdin io.Reader, presets []TeaPreset) ([]sequenceStep, error) {
+	if path == "-" {
+		steps, err := parseSequence(stdin, presets)
+		if err != nil {
+			return nil, fmt.Errorf("stdin: %w", err)
+		}
+		return steps, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	steps, err := parseSequence(f, presets)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return steps, nil
+}
+
+// parseSequence parses one step per line. A line is either a duration
+// followed by an optional label, or the name of a tea preset:
+//
+//	# Pour-over
+//	30s Bloom
+//	2m30s Pour and drain
+//	Green Tea
+//
+// Blank lines and lines starting with '#' are skipped.
+func parseSequence(r io.Reader, presets []TeaPreset) ([]sequenceStep, error) {
+	var steps []sequenceStep
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		step, err := parseSequenceStep(text, presets)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps found")
+	}
+	return steps, nil
+}
+
+// parseSequenceStep parses a single non-empty line of a sequence.
+func parseSequenceStep(text string, presets []TeaPreset) (sequenceStep, error) {
+	first, label, _ := strings.Cut(text, " ")
+	if d, err := time.ParseDuration(first); err == nil {
+		if d <= 0 || d > MaxBrewTime {
+			return sequenceStep{}, fmt.Errorf("duration %v must be between 0 and %v", d, MaxBrewTime)
+		}
+		label = strings.TrimSpace(label)
+		if label == "" {
+			label = d.String()
+		}
+		return sequenceStep{Label: label, Duration: d}, nil
+	}
+
+	for _, p := range presets {
+		if strings.EqualFold(p.Name, text) {
+			return sequenceStep{Label: p.Name, Duration: p.Duration, Preset: true}, nil
+		}
+	}
+	return sequenceStep{}, fmt.Errorf("%q is neither a duration nor a preset name", text)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSequence(t *testing.T) {
+	input := `# Pour-over
+30s Bloom
+
+2m30s  Pour and drain
+green tea
+45s
+`
+	steps, err := parseSequence(strings.NewReader(input), DefaultTeaPresets)
+	if err != nil {
+		t.Fatalf("Failed to parse sequence: %v", err)
+	}
+
+	want := []sequenceStep{
+		{"Bloom", 30 * time.Second, false, "", ""},
+		{"Pour and drain", 150 * time.Second, false, "", ""},
+		{"Green Tea", 2 * time.Minute, true, "", ""},
+		{"45s", 45 * time.Second, false, "", ""},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("Expected %d steps, got %d: %v", len(want), len(steps), steps)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("Step %d: expected %+v, got %+v", i, want[i], steps[i])
+		}
+	}
+}
+
+func TestParseSequenceErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"30s Bloom\nMatcha latte\n", `line 2: "Matcha latte" is neither a duration nor a preset name`},
+		{"-5s Rest\n", "line 1: duration -5s must be between 0 and 30m0s"},
+		{"1h Cold brew\n", "line 1: duration 1h0m0s must be between 0 and 30m0s"},
+		{"# only a comment\n\n", "no steps found"},
+	}
+	for _, tt := range tests {
+		_, err := parseSequence(strings.NewReader(tt.input), DefaultTeaPresets)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("parseSequence(%q): expected error %q, got %v", tt.input, tt.want, err)
+		}
+	}
+}
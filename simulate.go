@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hiddenFlags are left out of -help. They exist for automation and tests,
+// not for brewing tea.
+var hiddenFlags = map[string]bool{
+	"simulate-finish-after": true,
+}
+
+// printUsage prints -help for the command line flags without the hidden
+// ones.
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(out)
+	flag.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	visible.PrintDefaults()
+}
+
+// simulateFinishMsg cuts the brew of tick chain id short for
+// -simulate-finish-after. It is ignored once the brew has been paused,
+// reset or started over.
+type simulateFinishMsg struct {
+	id int
+}
+
+// simulateFinishCmd returns the command that finishes the brew starting now
+// after -simulate-finish-after, or nil unless that flag is set. Automation
+// uses it to run the whole completion path, with sound, notification and
+// history entry, without waiting out a real brew. The stopwatch never
+// finishes, so it is left alone.
+func (m model) simulateFinishCmd() tea.Cmd {
+	if m.config.SimulateFinishAfter <= 0 || m.stopwatch {
+		return nil
+	}
+	id := m.tickID
+	return tea.Tick(m.config.SimulateFinishAfter, func(time.Time) tea.Msg {
+		return simulateFinishMsg{id: id}
+	})
+}
+
+// simulateFinish finishes the running brew as its last tick would.
+func (m model) simulateFinish(msg simulateFinishMsg) (model, tea.Cmd) {
+	if !m.isBrewing() || msg.id != m.tickID {
+		return m, nil
+	}
+	m.timer, m.deadline = time.Second, now()
+	return m.update(tickMsg{id: m.tickID, time: now()})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulateFinish(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled, config.HistoryFile = false, false, ""
+	config.SimulateFinishAfter = time.Millisecond
+	m := initialModel(config)
+	m, cmd := m.start()
+	if cmd == nil {
+		t.Fatal("Expected the brew to start ticking")
+	}
+
+	// A brew paused in the meantime is left alone
+	id := m.tickID
+	paused := m.pause()
+	if paused, _ = paused.simulateFinish(simulateFinishMsg{id: id}); !paused.isPaused() {
+		t.Error("Expected a stale simulated finish to be ignored")
+	}
+
+	newModel, _ := m.Update(simulateFinishMsg{id: id})
+	if m = newModel.(model); !m.isFinished() || m.timer != 0 {
+		t.Errorf("Expected the brew to finish early, got %v with %v left", m.state, m.timer)
+	}
+
+	// Without the flag nothing is scheduled
+	if (initialModel(NewConfig())).simulateFinishCmd() != nil {
+		t.Error("Expected no simulated finish by default")
+	}
+}
+
+func TestUsageHidesFlags(t *testing.T) {
+	defer func(fs *flag.FlagSet) { flag.CommandLine = fs }(flag.CommandLine)
+	flag.CommandLine = flag.NewFlagSet("go-brew", flag.ContinueOnError)
+	var out bytes.Buffer
+	flag.CommandLine.SetOutput(&out)
+	flag.Duration("duration", 0, "brew time")
+	flag.Duration("simulate-finish-after", 0, "for testing")
+
+	printUsage()
+	if !strings.Contains(out.String(), "-duration") || strings.Contains(out.String(), "simulate") {
+		t.Errorf("Expected only the visible flags in the usage, got\n%s", out.String())
+	}
+}
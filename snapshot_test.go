@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
+)
+
+// TestSnapshots renders the timer in each of its states and at the edges of
+// the supported terminal sizes, and compares every frame against its golden
+// file in testdata/TestSnapshots. After an intentional UI change, review the
+// new frames and regenerate them with:
+//
+//	go test -run TestSnapshots -update
+func TestSnapshots(t *testing.T) {
+	idle := func() model {
+		config := NewConfig()
+		config.SoundEnabled = false
+		config.NotifyEnabled = false
+		config.HistoryFile = ""
+		m := initialModel(config)
+		m.width, m.height = 80, 30
+		return m
+	}
+	brewing := func() model {
+		m := idle()
+		m.state = StateBrewing
+		m.brewTea = m.currentPreset().Name
+		m.timer = m.brewDuration() - 75*time.Second
+		m.deadline = now().Add(m.timer)
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		model   func() model
+		profile termenv.Profile // Color profile to render with
+	}{
+		{"idle", idle, termenv.Ascii},
+		{"brewing", brewing, termenv.Ascii},
+		{"paused", func() model {
+			m := brewing()
+			m.state = StatePaused
+			return m
+		}, termenv.Ascii},
+		{"finished", func() model {
+			m := brewing()
+			m.state = StateFinished
+			m.timer = 0
+			m.soundFailed = true
+			return m
+		}, termenv.Ascii},
+		{"tiny", func() model {
+			m := brewing()
+			m.width, m.height = 30, 10
+			return m
+		}, termenv.Ascii},
+		{"wide", func() model {
+			m := idle()
+			m.width, m.height = 200, 50
+			return m
+		}, termenv.Ascii},
+		{"color", brewing, termenv.ANSI256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lipgloss.SetColorProfile(tt.profile)
+			defer lipgloss.SetColorProfile(termenv.Ascii)
+			m := tt.model()
+			golden.RequireEqual(t, []byte(m.render()))
+		})
+	}
+}
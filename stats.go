@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	statsDays     = 14 // Days covered by the brews-per-day sparkline
+	statsTopTeas  = 8  // Teas listed in the brews-per-tea chart
+	statsBarWidth = 20 // Width of the longest bar in the brews-per-tea chart
+	statsRecent   = 5  // Brews listed while a filter is applied
+)
+
+// screen selects what the UI shows.
+type screen int
+
+const (
+	// screenTimer is the timer with its presets and controls
+	screenTimer screen = iota
+	// screenStats charts the brew history
+	screenStats
+	// screenCatalog browses the built-in tea catalog
+	screenCatalog
+	// screenCalc works out the leaf for each vessel
+	screenCalc
+)
+
+// historyMsg delivers the brew log read for the stats screen.
+type historyMsg []brewRecord
+
+// historyStats are the figures the stats screen charts.
+type historyStats struct {
+	Total     int      // Brews in the log
+	PerDay    []int    // Brews on each of the last statsDays days, oldest first
+	Teas      []string // Most brewed teas, at most statsTopTeas of them
+	TeaCounts []int    // Brews of each of Teas
+	MoreTeas  int      // Teas brewed that didn't make it into Teas
+	PerHour   [24]int  // Brews finished in each hour of the day
+}
+
+// historyStatsMsg delivers the stats of the brew log worked out by its
+// store.
+type historyStatsMsg historyStats
+
+// summarizeHistory works out the stats of records as of today, in today's
+// time zone.
+func summarizeHistory(records []brewRecord, today time.Time) historyStats {
+	s := historyStats{Total: len(records), PerDay: brewsPerDay(records, statsDays, today)}
+	s.Teas, s.TeaCounts = brewsPerTea(records)
+	if len(s.Teas) > statsTopTeas {
+		s.MoreTeas = len(s.Teas) - statsTopTeas
+		s.Teas, s.TeaCounts = s.Teas[:statsTopTeas], s.TeaCounts[:statsTopTeas]
+	}
+	for _, rec := range records {
+		s.PerHour[rec.Time.In(today.Location()).Hour()]++
+	}
+	return s
+}
+
+// historyStatsCmd returns a command that has store work out the stats as of
+// today, so a large log is queried rather than read whole.
+func historyStatsCmd(store historySummarizer, today time.Time) tea.Cmd {
+	return func() tea.Msg {
+		stats, err := store.Summarize(today)
+		if err != nil {
+			return errMsg{fmt.Errorf("reading brew stats: %w", err)}
+		}
+		return historyStatsMsg(stats)
+	}
+}
+
+// loadHistoryCmd returns a command that reads the brew log at path, so the
+// file is not read while rendering.
+func loadHistoryCmd(path string, key *historyKey) tea.Cmd {
+	return func() tea.Msg {
+		records, err := loadHistory(path, key)
+		if err != nil {
+			return errMsg{fmt.Errorf("reading brew history: %w", err)}
+		}
+		return historyMsg(records)
+	}
+}
+
+// toggleStats switches between the timer and the stats screen, reloading the
+// brew log on the way in so brews finished since last time are counted.
+// A running timer keeps counting down in the background.
+func (m model) toggleStats() (model, tea.Cmd) {
+	if m.screen == screenStats {
+		m.screen = screenTimer
+		return m, nil
+	}
+	m.screen = screenStats
+	if m.config.HistoryFile == "" {
+		return m, nil
+	}
+	// A database answers the stats itself; the brews for filtering were
+	// read at startup
+	store, err := openHistory(m.config.HistoryFile, m.config.HistoryKey)
+	if s, ok := store.(historySummarizer); ok && err == nil {
+		return m, historyStatsCmd(s, m.clock)
+	}
+	return m, loadHistoryCmd(m.config.HistoryFile, m.config.HistoryKey)
+}
+
+// brewsPerDay counts the records of each of the last days days up to and
+// including the day of today, oldest first. Days follow today's time zone.
+func brewsPerDay(records []brewRecord, days int, today time.Time) []int {
+	// Index calendar dates rather than 24h spans so DST changes don't move
+	// brews into the wrong day
+	index := make(map[string]int, days)
+	for i := 0; i < days; i++ {
+		index[today.AddDate(0, 0, i+1-days).Format(time.DateOnly)] = i
+	}
+	counts := make([]int, days)
+	for _, rec := range records {
+		if i, ok := index[rec.Time.In(today.Location()).Format(time.DateOnly)]; ok {
+			counts[i]++
+		}
+	}
+	return counts
+}
+
+// brewsPerTea counts the records of each tea, most brewed first and ties in
+// name order.
+func brewsPerTea(records []brewRecord) ([]string, []int) {
+	counts := make(map[string]int)
+	for _, rec := range records {
+		counts[rec.Tea]++
+	}
+	teas := make([]string, 0, len(counts))
+	for name := range counts {
+		teas = append(teas, name)
+	}
+	sort.Slice(teas, func(i, j int) bool {
+		if counts[teas[i]] != counts[teas[j]] {
+			return counts[teas[i]] > counts[teas[j]]
+		}
+		return teas[i] < teas[j]
+	})
+	values := make([]int, len(teas))
+	for i, name := range teas {
+		values[i] = counts[name]
+	}
+	return teas, values
+}
+
+// renderStats builds the stats screen: a sparkline of brews per day over the
+// last two weeks, a heatmap of brews by hour of the day and a bar chart of
+// the most brewed teas. A filter is applied to the brews read from the log;
+// otherwise the store's own stats are shown if it has any.
+func (m model) renderStats() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Padding(1, 2).Foreground(lipgloss.Color(m.config.Colors.Ready))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Faint(true)
+	hint := "\n\n" + labelStyle.Render(fmt.Sprintf("Press '%s' to filter, '%s' or esc to return to the timer", KeyHistorySearch, m.config.Keys.Stats))
+	// The filter prompt and its problems replace the hint while it is open
+	if m.inputKind == inputHistoryFilter {
+		hint = "\n\n" + m.inputView() + "\n" + labelStyle.Render(m.inputHint())
+		if m.status != "" {
+			hint += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Colors.Paused)).Render(m.status)
+		}
+	}
+
+	title := titleStyle.Render(m.icon("🍵", "") + "Brew Stats")
+	history := m.filteredHistory()
+	if m.historyQuery != "" {
+		title += "\n" + labelStyle.Render(fmt.Sprintf("Filter: %s (%d of %d brews)", m.historyQuery, len(history), len(m.history)))
+	}
+	stats := m.summary
+	if stats == nil || m.historyQuery != "" {
+		s := summarizeHistory(history, m.clock)
+		stats = &s
+	}
+	switch {
+	case m.config.HistoryFile == "":
+		return title + "\nBrew history is disabled" + hint
+	case len(m.history) == 0 && stats.Total == 0:
+		return title + "\nNo brews recorded yet" + hint
+	case stats.Total == 0:
+		return title + "\nNo brews match the filter" + hint
+	}
+
+	perDay := stats.PerDay
+	recent := 0
+	for _, n := range perDay {
+		recent += n
+	}
+	// Label the first and last day under the ends of the sparkline
+	from, to := m.config.Formats.dayMonth(m.clock.AddDate(0, 0, 1-statsDays)), m.config.Formats.dayMonth(m.clock)
+	gap := strings.Repeat(" ", max(1, statsDays+2-lipgloss.Width(from)-lipgloss.Width(to)))
+	days := fmt.Sprintf("Brews per day (last %d days): %d\n", statsDays, recent) +
+		"│" + sparkline(perDay) + "│\n" +
+		labelStyle.Render(from+gap+to)
+
+	// Hours are marked every 6 under the heatmap
+	const byHour = "By hour of day: "
+	hours := byHour + "│" + heatmap(stats.PerHour[:]) + "│\n" +
+		labelStyle.Render(strings.Repeat(" ", len(byHour)+1)+fmt.Sprintf("%-6s%-6s%-6s%-6s", "0", "6", "12", "18"))
+
+	more := ""
+	if stats.MoreTeas > 0 {
+		more = "\n" + labelStyle.Render(fmt.Sprintf("and %d more", stats.MoreTeas))
+	}
+	perTea := fmt.Sprintf("Brews per tea: %d in total\n", stats.Total) +
+		barChart(stats.Teas, stats.TeaCounts, statsBarWidth) + more
+
+	// A filtered journal also lists the latest matching brews
+	var latest string
+	if m.historyQuery != "" {
+		latest = "\n\nLatest matches:"
+		for i := len(history) - 1; i >= max(0, len(history)-statsRecent); i-- {
+			rec := history[i]
+			line := fmt.Sprintf("%s  %s (%v)", m.config.Formats.dayMonth(rec.Time.In(m.clock.Location()))+" "+m.config.Formats.clock(rec.Time.In(m.clock.Location())), rec.Tea, rec.Duration.Duration)
+			if rec.Note != "" {
+				line += "  " + m.icon("📝", "Note:") + rec.Note
+			}
+			latest += "\n" + line
+		}
+	}
+
+	return title + "\n" + days + "\n\n" + hours + "\n\n" + perTea + latest + hint
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBrewsPerDay(t *testing.T) {
+	today := now()
+	records := []brewRecord{
+		{Time: today.Add(-time.Hour), Tea: "Sencha"},
+		{Time: today.Add(-2 * time.Hour), Tea: "Sencha"},
+		{Time: today.AddDate(0, 0, -1), Tea: "Oolong"},
+		{Time: today.AddDate(0, 0, -3), Tea: "Oolong"},
+		{Time: today.AddDate(0, 0, -30), Tea: "Too old"},
+	}
+	got := brewsPerDay(records, 4, today)
+	want := []int{1, 0, 1, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected brews per day %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBrewsPerTea(t *testing.T) {
+	records := []brewRecord{{Tea: "Sencha"}, {Tea: "Oolong"}, {Tea: "Sencha"}, {Tea: "Assam"}}
+	teas, counts := brewsPerTea(records)
+	if strings.Join(teas, ",") != "Sencha,Assam,Oolong" || counts[0] != 2 || counts[1] != 1 || counts[2] != 1 {
+		t.Errorf("Expected Sencha first and ties in name order, got %v %v", teas, counts)
+	}
+}
+
+func TestStatsScreen(t *testing.T) {
+	config := NewConfig()
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	for _, tea := range []string{"Sencha", "Sencha", "Oolong"} {
+		if err := appendHistory(config.HistoryFile, nil, brewRecord{Time: now(), Tea: tea}); err != nil {
+			t.Fatalf("Failed to append history: %v", err)
+		}
+	}
+	m := initialModel(config)
+
+	// Opening the stats screen reads the brew log
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStats)})
+	m = newModel.(model)
+	if m.screen != screenStats || cmd == nil {
+		t.Fatal("Expected stats key to open the stats screen and load the history")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(model)
+	if len(m.history) != 3 {
+		t.Fatalf("Expected 3 history records, got %d", len(m.history))
+	}
+	view := m.View()
+	for _, want := range []string{"Brews per day (last 14 days): 3", "Sencha", "Oolong"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected stats view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	// Timer keys do nothing on the stats screen; esc returns to the timer
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	if m = newModel.(model); m.isBrewing() {
+		t.Error("Expected start key to be ignored on the stats screen")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(model); m.screen != screenTimer {
+		t.Error("Expected esc to return to the timer")
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// Layout of the exported stats graphic.
+const (
+	svgWidth      = 760
+	svgHeight     = 420
+	svgPieX       = 140 // Center of the brews per tea pie
+	svgPieY       = 230
+	svgPieRadius  = 110
+	svgLegendX    = 270 // Left edge of the pie legend
+	svgBarsLeft   = 470 // Plot area of the weekly bar chart
+	svgBarsRight  = 730
+	svgBarsTop    = 110
+	svgBarsBottom = 350
+	svgPieSlices  = 6  // Teas shown in the pie before the rest become "Other"
+	svgWeeks      = 12 // Default number of weeks in the bar chart
+)
+
+// svgPalette colors the pie slices and legend, in order. The last, gray,
+// color falls to "Other".
+var svgPalette = []string{"#2E8B57", "#C88A2E", "#6B2E12", "#B8C55A", "#B5442A", "#5B7DB1", "#9A9A9A"}
+
+// runStatsCommand implements "go-brew stats" and returns the process exit
+// code:
+//
+//	go-brew stats export --svg|--ics [-o file] [-weeks n] [-history file] [-locale name]
+//
+// The graphic has a pie of brews per tea over the whole history and a bar
+// chart of brews per week, for sharing without a terminal screenshot. With
+// --ics the history is written as an iCalendar file of brews instead, for
+// calendar apps. Either is written to stdout unless -o names a file.
+func runStatsCommand(args []string, stdout, stderr io.Writer) int {
+	const usage = "usage: go-brew stats export --svg|--ics [-o file] [-weeks n] [-history file] [-locale name]"
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	fs := flag.NewFlagSet("stats export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	svg := fs.Bool("svg", false, "write the graphic as SVG")
+	ics := fs.Bool("ics", false, "write the brews as iCalendar events")
+	out := fs.String("o", "", "write to `file` instead of stdout")
+	weeks := fs.Int("weeks", svgWeeks, "number of weeks in the bar chart")
+	path := fs.String("history", defaultHistoryPath(), "brew history `file` to read")
+	locale := fs.String("locale", "", "write dates for `locale`, such as en_GB (default from display.locale or the environment)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\n%s\n", fs.Arg(0), usage)
+		return 2
+	}
+	if *svg == *ics {
+		fmt.Fprintf(stderr, "choose one format: --svg or --ics\n%s\n", usage)
+		return 2
+	}
+	if *weeks < 1 || *weeks > 52 {
+		fmt.Fprintln(stderr, "error: -weeks must be between 1 and 52")
+		return 2
+	}
+
+	key, err := commandHistoryKey()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	records, err := loadHistory(*path, key)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if *ics {
+		writeICS(bw, records, now())
+	} else {
+		writeStatsSVG(bw, records, now(), *weeks, commandLocale(*locale))
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// brewsPerWeek counts the records of each of the last weeks seven-day spans
+// up to and including the day of today, oldest first.
+func brewsPerWeek(records []brewRecord, weeks int, today time.Time) []int {
+	counts := make([]int, weeks)
+	for i, n := range brewsPerDay(records, weeks*7, today) {
+		counts[i/7] += n
+	}
+	return counts
+}
+
+// writeStatsSVG draws the stats graphic for records as of today, writing
+// dates in the given formats.
+func writeStatsSVG(w io.Writer, records []brewRecord, today time.Time, weeks int, formats localeFormats) {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", svgWidth, svgHeight, svgWidth, svgHeight)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="#FFFDF7"/>`+"\n")
+	fmt.Fprintf(w, `<text x="30" y="45" font-size="24" font-weight="bold" fill="#333">🍵 go-brew stats</text>`+"\n")
+	fmt.Fprintf(w, `<text x="30" y="72" font-size="14" fill="#666">%d %s as of %s</text>`+"\n", len(records), plural(len(records), "brew", "brews"), formats.date(today))
+	writeTeaPie(w, records)
+	writeWeekBars(w, records, today, weeks, formats)
+	fmt.Fprintln(w, "</svg>")
+}
+
+// writeTeaPie draws the share of brews per tea, with a legend. The least
+// brewed teas are combined into "Other" so the slices stay readable.
+func writeTeaPie(w io.Writer, records []brewRecord) {
+	fmt.Fprintf(w, `<text x="30" y="%d" font-size="16" fill="#333">Brews per tea</text>`+"\n", svgBarsTop-10)
+	teas, counts := brewsPerTea(records)
+	if len(teas) == 0 {
+		fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" fill="#EEE"/>`+"\n", svgPieX, svgPieY, svgPieRadius)
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="14" fill="#666" text-anchor="middle">No brews yet</text>`+"\n", svgPieX, svgPieY+5)
+		return
+	}
+	if len(teas) > svgPieSlices {
+		other := 0
+		for _, n := range counts[svgPieSlices:] {
+			other += n
+		}
+		teas = append(teas[:svgPieSlices:svgPieSlices], "Other")
+		counts = append(counts[:svgPieSlices:svgPieSlices], other)
+	}
+
+	total := len(records)
+	angle := -math.Pi / 2 // Start at twelve o'clock
+	for i, n := range counts {
+		color := svgPalette[i]
+		if n == total {
+			fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", svgPieX, svgPieY, svgPieRadius, color)
+		} else {
+			sweep := 2 * math.Pi * float64(n) / float64(total)
+			large := 0
+			if sweep > math.Pi {
+				large = 1
+			}
+			x1, y1 := svgPieX+svgPieRadius*math.Cos(angle), svgPieY+svgPieRadius*math.Sin(angle)
+			x2, y2 := svgPieX+svgPieRadius*math.Cos(angle+sweep), svgPieY+svgPieRadius*math.Sin(angle+sweep)
+			fmt.Fprintf(w, `<path d="M%d,%d L%.1f,%.1f A%d,%d 0 %d 1 %.1f,%.1f Z" fill="%s" stroke="#FFFDF7" stroke-width="2"/>`+"\n",
+				svgPieX, svgPieY, x1, y1, svgPieRadius, svgPieRadius, large, x2, y2, color)
+			angle += sweep
+		}
+
+		y := svgBarsTop + 10 + i*28
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="14" height="14" fill="%s"/>`+"\n", svgLegendX, y, color)
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-size="13" fill="#333">%s (%d)</text>`+"\n", svgLegendX+22, y+12, html.EscapeString(teas[i]), n)
+	}
+}
+
+// writeWeekBars draws brews per week over the last weeks weeks, labelling
+// the first day of every few weeks.
+func writeWeekBars(w io.Writer, records []brewRecord, today time.Time, weeks int, formats localeFormats) {
+	counts := brewsPerWeek(records, weeks, today)
+	highest := 1
+	for _, n := range counts {
+		highest = max(highest, n)
+	}
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="16" fill="#333">Brews per week</text>`+"\n", svgBarsLeft, svgBarsTop-10)
+	fmt.Fprintf(w, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#999"/>`+"\n", svgBarsLeft, svgBarsBottom, svgBarsRight, svgBarsBottom)
+
+	slot := float64(svgBarsRight-svgBarsLeft) / float64(weeks)
+	labelEvery := (weeks + 5) / 6
+	first := today.AddDate(0, 0, 1-weeks*7)
+	for i, n := range counts {
+		x := svgBarsLeft + float64(i)*slot
+		h := float64(svgBarsBottom-svgBarsTop-20) * float64(n) / float64(highest)
+		fmt.Fprintf(w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", x+slot*0.15, svgBarsBottom-h, slot*0.7, h, svgPalette[0])
+		if n > 0 {
+			fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="11" fill="#333" text-anchor="middle">%d</text>`+"\n", x+slot/2, svgBarsBottom-h-4, n)
+		}
+		if (weeks-1-i)%labelEvery == 0 {
+			fmt.Fprintf(w, `<text x="%.1f" y="%d" font-size="11" fill="#666" text-anchor="middle">%s</text>`+"\n", x+slot/2, svgBarsBottom+16, formats.dayMonth(first.AddDate(0, 0, i*7)))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBrewsPerWeek(t *testing.T) {
+	today := now()
+	records := []brewRecord{
+		{Time: today.AddDate(0, 0, -21), Tea: "Oolong"}, // Before the 3 weeks
+		{Time: today.AddDate(0, 0, -14), Tea: "Oolong"},
+		{Time: today.AddDate(0, 0, -7), Tea: "Green Tea"},
+		{Time: today.AddDate(0, 0, -6), Tea: "Green Tea"},
+		{Time: today, Tea: "Black Tea"},
+	}
+	got := brewsPerWeek(records, 3, today)
+	if len(got) != 3 || got[0] != 1 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("Expected [1 1 2] brews per week, got %v", got)
+	}
+}
+
+func TestWriteStatsSVG(t *testing.T) {
+	today := now()
+	var records []brewRecord
+	for i, tea := range []string{"Sencha", "Sencha", "Oolong", "Assam", "Chai", "Mint", "Rooibos", "Hojicha & Milk", "Yerba Mate"} {
+		records = append(records, brewRecord{Time: today.AddDate(0, 0, -i*3), Tea: tea})
+	}
+	var buf bytes.Buffer
+	writeStatsSVG(&buf, records, today, svgWeeks, defaultLocale)
+	svg := buf.String()
+
+	// The output must be well-formed XML for browsers and converters
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Expected well-formed SVG, got %v", err)
+		}
+	}
+	for _, want := range []string{"9 brews as of Mar 1, 2024", "Sencha (2)", "Hojicha &amp; Milk (1)", "Other (2)", "Brews per week", "Feb 24"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected the SVG to contain %q", want)
+		}
+	}
+	if strings.Contains(svg, "Rooibos") {
+		t.Error("Expected the least brewed teas to be combined into Other")
+	}
+	if n := strings.Count(svg, "<path"); n != 7 {
+		t.Errorf("Expected 7 pie slices, got %d", n)
+	}
+}
+
+func TestWriteStatsSVGSingleTea(t *testing.T) {
+	var buf bytes.Buffer
+	writeStatsSVG(&buf, []brewRecord{{Time: now(), Tea: "Oolong"}}, now(), 4, defaultLocale)
+	if svg := buf.String(); strings.Contains(svg, "<path") || !strings.Contains(svg, "Oolong (1)") {
+		t.Errorf("Expected a full circle for a single tea, got\n%s", svg)
+	}
+}
+
+func TestStatsExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	history := filepath.Join(dir, historyFileName)
+	if err := appendHistory(history, nil, brewRecord{Time: now(), Tea: "Oolong", Duration: Duration{3 * time.Minute}}); err != nil {
+		t.Fatalf("Failed to append history: %v", err)
+	}
+	out := filepath.Join(dir, "stats.svg")
+	var stdout, stderr bytes.Buffer
+	if code := runStatsCommand([]string{"export", "--svg", "-o", out, "-history", history}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if data, err := os.ReadFile(out); err != nil || !bytes.HasPrefix(data, []byte("<svg")) {
+		t.Errorf("Expected an SVG file, got %q (%v)", data, err)
+	}
+
+	if code := runStatsCommand([]string{"export", "-history", history}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without a format, got %d", code)
+	}
+	if code := runStatsCommand(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without a subcommand, got %d", code)
+	}
+}
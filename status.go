@@ -0,0 +1,37 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusDuration is how long a status line stays visible before it clears.
+const statusDuration = 4 * time.Second
+
+// statusMsg asks the UI to show a transient status line. Commands return it
+// to surface information that is worth a glance but not worth interrupting.
+type statusMsg string
+
+// errMsg reports a runtime problem from a command. The error is shown in the
+// transient status line so it does not vanish into the log.
+type errMsg struct {
+	err error
+}
+
+// statusClearMsg clears the status line set with the matching ID. Status
+// lines replaced by a newer one ignore it and keep their own timeout.
+type statusClearMsg struct {
+	id int
+}
+
+// showStatus displays text in the status line and returns the command that
+// clears it again after statusDuration.
+func (m model) showStatus(text string) (model, tea.Cmd) {
+	m.statusID++
+	m.status = text
+	id := m.statusID
+	return m, tea.Tick(statusDuration, func(time.Time) tea.Msg {
+		return statusClearMsg{id: id}
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxLapsShown is the number of most recent laps listed in the view.
+const maxLapsShown = 5
+
+// toggleStopwatch switches between the countdown timer and the stopwatch.
+// The stopwatch counts up from zero using the same start, pause and reset
+// keys, and records laps with the lap key.
+func (m model) toggleStopwatch() (model, tea.Cmd) {
+	m = m.silence()
+	m.stopwatch = !m.stopwatch
+	m.laps = nil
+	m.undo = nil
+	m.state = StateIdle
+	m.timer = m.brewDuration()
+	if m.stopwatch {
+		m.timer = 0
+	}
+	m = m.stopTicking()
+	if m.stopwatch {
+		return m.showStatus("Stopwatch mode")
+	}
+	return m.showStatus("Timer mode")
+}
+
+// startStopwatch starts counting up from zero, discarding earlier laps.
+func (m model) startStopwatch() (model, tea.Cmd) {
+	m.timer = 0
+	m.laps = nil
+	m.state = StateBrewing
+	m.undo = nil
+	return m.startTicking()
+}
+
+// lap records the elapsed time of the running stopwatch as a lap.
+func (m model) lap() model {
+	if m.stopwatch && (m.isBrewing() || m.isPaused()) {
+		m.laps = append(m.laps[:len(m.laps):len(m.laps)], m.timer.Truncate(time.Second))
+	}
+	return m
+}
+
+// renderLaps lists the most recent laps with their split from the previous
+// lap, newest last.
+func (m model) renderLaps(style lipgloss.Style) string {
+	first := max(0, len(m.laps)-maxLapsShown)
+	lines := make([]string, 0, len(m.laps)-first)
+	for i := first; i < len(m.laps); i++ {
+		split := m.laps[i]
+		if i > 0 {
+			split -= m.laps[i-1]
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("Lap %-3d %s  (+%s)", i+1, m.config.TimeFormat.Format(m.laps[i]), m.config.TimeFormat.Format(split))))
+	}
+	return strings.Join(lines, "\n")
+}
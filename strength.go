@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	strengthCells  = 10  // Width of the strength indicator
+	strengthLight  = 0.4 // Strength below which a cup counts as light
+	strengthStrong = 0.7 // Strength from which a cup counts as strong
+)
+
+// extractionShapes says how far along its extraction each kind of tea is at
+// its recommended brew time, by a word in the preset name. Delicate teas
+// give up most of their flavor early, so pulling them sooner makes little
+// difference, while tisanes keep getting stronger. The first match wins.
+var extractionShapes = []struct {
+	word  string
+	share float64
+}{
+	{"green", 0.85},
+	{"matcha", 0.9},
+	{"white", 0.8},
+	{"black", 0.8},
+	{"oolong", 0.75},
+	{"pu-erh", 0.75},
+	{"herbal", 0.7},
+	{"rooibos", 0.65},
+}
+
+// defaultExtractionShare applies to teas extractionShapes doesn't know.
+const defaultExtractionShare = 0.8
+
+// extractionShare returns how far along its extraction the named tea is at
+// its recommended brew time.
+func extractionShare(name string) float64 {
+	name = strings.ToLower(name)
+	for _, s := range extractionShapes {
+		if strings.Contains(name, s.word) {
+			return s.share
+		}
+	}
+	return defaultExtractionShare
+}
+
+// brewStrength estimates the strength of a cup of the named tea after
+// elapsed of a brew lasting total, from 0 to 1. Extraction slows as the
+// leaves give up their flavor, so strength rises as 1-e^(-t/τ), with τ
+// chosen so the recommended brew time reaches the tea's extraction share.
+func brewStrength(name string, elapsed, total time.Duration) float64 {
+	if total <= 0 || elapsed <= 0 {
+		return 0
+	}
+	tau := total.Seconds() / -math.Log(1-extractionShare(name))
+	return 1 - math.Exp(-elapsed.Seconds()/tau)
+}
+
+// strengthLabel names the strength of a cup.
+func strengthLabel(strength float64) string {
+	switch {
+	case strength < strengthLight:
+		return "light"
+	case strength < strengthStrong:
+		return "medium"
+	}
+	return "strong"
+}
+
+// strengthView renders the strength indicator for the running brew, or ""
+// when there is no tea brewing to estimate it for.
+func (m model) strengthView() string {
+	if !m.config.ShowStrength || m.stopwatch || m.prepStep() || !(m.isBrewing() || m.isPaused()) {
+		return ""
+	}
+	total := m.brewDuration()
+	strength := brewStrength(m.brewTea, total-m.timer, total)
+	filled := int(math.Round(strength * strengthCells))
+	return "Strength " + strings.Repeat("▰", filled) + strings.Repeat("▱", strengthCells-filled) + " " + strengthLabel(strength)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBrewStrength(t *testing.T) {
+	total := 3 * time.Minute
+	if got := brewStrength("Black Tea", 0, total); got != 0 {
+		t.Errorf("Expected no strength before brewing, got %v", got)
+	}
+	if got := brewStrength("Black Tea", total, total); math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("Expected black tea at its extraction share after its brew time, got %v", got)
+	}
+	// Extraction slows down: the first minute adds more than the last
+	first := brewStrength("Black Tea", time.Minute, total)
+	last := brewStrength("Black Tea", total, total) - brewStrength("Black Tea", 2*time.Minute, total)
+	if first <= last {
+		t.Errorf("Expected strength to rise non-linearly, got %v then %v", first, last)
+	}
+	// Green tea gets going faster than a tisane with the same brew time
+	if brewStrength("Green Tea", time.Minute, total) <= brewStrength("Herbal", time.Minute, total) {
+		t.Error("Expected green tea to extract faster than herbal tea")
+	}
+}
+
+func TestStrengthView(t *testing.T) {
+	m := initialModel(NewConfig())
+	m, _ = m.start()
+	if got := m.strengthView(); got != "Strength ▱▱▱▱▱▱▱▱▱▱ light" {
+		t.Errorf("Unexpected indicator %q", got)
+	}
+	m.timer = 0
+	if got := m.strengthView(); got != "Strength ▰▰▰▰▰▰▰▱▱▱ medium" {
+		t.Errorf("Unexpected indicator %q", got)
+	}
+	m.config.ShowStrength = false
+	if got := m.strengthView(); got != "" {
+		t.Errorf("Expected no indicator when disabled, got %q", got)
+	}
+}
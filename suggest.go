@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxRating is the best rating a brew can be given; the worst is 1.
+const maxRating = 5
+
+// minSuggestRatings is how many rated brews a duration needs before it is
+// suggested, so one lucky cup doesn't move the timer.
+const minSuggestRatings = 2
+
+// suggestMargin is how much higher on average a duration has to be rated
+// than the one about to be brewed before it is suggested instead.
+const suggestMargin = 0.5
+
+// suggestDuration finds the duration a tea is rated highest at in the brew
+// log, to suggest over current. Only brews of the whole tea count, not its
+// infusions, and a duration needs minSuggestRatings ratings. It reports
+// false when current is already the best, or not clearly worse.
+func suggestDuration(records []brewRecord, teaName string, current time.Duration) (time.Duration, bool) {
+	type score struct{ sum, n int }
+	scores := make(map[time.Duration]*score)
+	for _, rec := range records {
+		if rec.Rating == 0 || rec.Tea != teaName || rec.Infusion > 0 {
+			continue
+		}
+		s := scores[rec.Duration.Duration]
+		if s == nil {
+			s = &score{}
+			scores[rec.Duration.Duration] = s
+		}
+		s.sum += rec.Rating
+		s.n++
+	}
+	avg := func(s *score) float64 { return float64(s.sum) / float64(s.n) }
+
+	// Ties go to the duration rated more often, then to the shorter one,
+	// so the same suggestion comes up every time
+	var best time.Duration
+	var bestScore *score
+	for d, s := range scores {
+		if s.n < minSuggestRatings {
+			continue
+		}
+		if bestScore == nil || avg(s) > avg(bestScore) ||
+			avg(s) == avg(bestScore) && (s.n > bestScore.n || s.n == bestScore.n && d < best) {
+			best, bestScore = d, s
+		}
+	}
+	if bestScore == nil || best == current {
+		return 0, false
+	}
+	if s := scores[current]; s != nil && avg(bestScore) < avg(s)+suggestMargin {
+		return 0, false
+	}
+	return best, true
+}
+
+// suggestion returns the duration the ratings suggest for the selected
+// preset. It is only offered while idle on the preset, as the preset's own
+// duration is what it would change, and not for presets with steeps.
+func (m model) suggestion() (time.Duration, bool) {
+	if !m.showsPresetLine() || m.customBrew() {
+		return 0, false
+	}
+	if _, ok := m.steepDuration(); ok {
+		return 0, false
+	}
+	return suggestDuration(m.history, m.currentPreset().Name, m.brewDuration())
+}
+
+// suggestionLabel describes the suggested duration and how to use it, or
+// returns "" when there is none.
+func (m model) suggestionLabel() string {
+	d, ok := m.suggestion()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%sYou rate %s higher at %s · press '%s' to use it",
+		m.icon("💡", "Tip:"), m.currentPreset().Name, m.config.TimeFormat.Format(d), m.config.Keys.Suggest)
+}
+
+// acceptSuggestion sets the selected preset to the suggested duration for
+// the rest of the session, as editing the preset would.
+func (m model) acceptSuggestion() (model, tea.Cmd) {
+	d, ok := m.suggestion()
+	if !ok {
+		return m, nil
+	}
+	return m.setPresetDuration(d)
+}
+
+// openRating asks how the finished brew turned out.
+func (m model) openRating() (model, tea.Cmd) {
+	m, cmd := m.openInput(inputRating, fmt.Sprintf("Rate %s (1-%d): ", m.brewTea, maxRating), strconv.Itoa(maxRating-1))
+	m.input.CharLimit = 1
+	return m, cmd
+}
+
+// ratingLabel shows the rating of the finished brew, or how to rate it,
+// while it is on screen. Brews not saved to the history have none.
+func (m model) ratingLabel() string {
+	if !m.isFinished() {
+		return ""
+	}
+	if m.blindTea != "" {
+		return fmt.Sprintf("Press '%s' to rate it and find out which tea it was", m.config.Keys.Rate)
+	}
+	if m.finishedAt.IsZero() {
+		return ""
+	}
+	for _, rec := range slices.Backward(m.history) {
+		if !rec.Time.Equal(m.finishedAt) || rec.Rating == 0 {
+			continue
+		}
+		label := fmt.Sprintf("Rated %d/%d", rec.Rating, maxRating)
+		if rec.Blind {
+			label += " · it was " + rec.Tea
+		}
+		return label + fmt.Sprintf(" · press '%s' to change", m.config.Keys.Rate)
+	}
+	return fmt.Sprintf("Press '%s' to rate it", m.config.Keys.Rate)
+}
+
+// submitRating saves the rating typed for the finished brew to the brew
+// log. The log already read is updated as well, so suggestions take the
+// rating into account right away.
+func (m model) submitRating(s string) (model, tea.Cmd) {
+	rating, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || rating < 1 || rating > maxRating {
+		return m.showStatus(fmt.Sprintf("rate the brew from 1 to %d", maxRating))
+	}
+	m = m.closeInput()
+	m.history = slices.Clone(m.history)
+	for i := range m.history {
+		if m.history[i].Time.Equal(m.finishedAt) {
+			m.history[i].Rating = rating
+		}
+	}
+	text := fmt.Sprintf("Rated %s %d/%d", m.brewTea, rating, maxRating)
+	if m.blindTea != "" {
+		m = m.revealBlind()
+		text = fmt.Sprintf("It was %s! Rated %d/%d", m.brewTea, rating, maxRating)
+	}
+	m, status := m.showStatus(text)
+	return m, tea.Batch(status, rateBrewCmd(m.config.HistoryFile, m.config.HistoryKey, m.finishedAt, rating))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSuggestDuration(t *testing.T) {
+	rated := func(d time.Duration, ratings ...int) []brewRecord {
+		var records []brewRecord
+		for _, r := range ratings {
+			records = append(records, brewRecord{Tea: "Green Tea", Duration: Duration{d}, Rating: r})
+		}
+		return records
+	}
+	join := func(lists ...[]brewRecord) []brewRecord {
+		var records []brewRecord
+		for _, l := range lists {
+			records = append(records, l...)
+		}
+		return records
+	}
+	other := []brewRecord{
+		{Tea: "Sencha", Duration: Duration{time.Minute}, Rating: 5},
+		{Tea: "Sencha", Duration: Duration{time.Minute}, Rating: 5},
+		{Tea: "Green Tea", Duration: Duration{time.Minute}, Rating: 5, Infusion: 2},
+		{Tea: "Green Tea", Duration: Duration{time.Minute}, Rating: 5, Infusion: 3},
+		{Tea: "Green Tea", Duration: Duration{time.Minute}},
+		{Tea: "Green Tea", Duration: Duration{time.Minute}},
+	}
+
+	tests := []struct {
+		name    string
+		records []brewRecord
+		want    time.Duration // 0 for no suggestion
+	}{
+		{"no ratings", nil, 0},
+		{"better rated", join(rated(2*time.Minute, 2, 3), rated(105*time.Second, 5, 4)), 105 * time.Second},
+		{"unrated current", rated(105*time.Second, 4, 4), 105 * time.Second},
+		{"one rating is not enough", rated(105*time.Second, 5), 0},
+		{"current is best", join(rated(2*time.Minute, 5, 5), rated(105*time.Second, 4, 4)), 0},
+		{"not clearly better", join(rated(2*time.Minute, 4, 4), rated(105*time.Second, 4, 4, 5)), 0},
+		{"ties go to the shorter", join(rated(150*time.Second, 5, 5), rated(105*time.Second, 5, 5)), 105 * time.Second},
+		{"other teas and infusions ignored", other, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := suggestDuration(tt.records, "Green Tea", 2*time.Minute)
+			if ok != (tt.want != 0) || got != tt.want {
+				t.Errorf("Expected suggestion %v, got %v, %v", tt.want, got, ok)
+			}
+		})
+	}
+}
+
+func TestRateBrew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFileName)
+	finished := time.Date(2024, 3, 1, 8, 30, 0, 123, time.UTC)
+	for i := range 3 {
+		rec := brewRecord{Time: finished.Add(time.Duration(i) * time.Hour), Tea: "Green Tea", Duration: Duration{2 * time.Minute}}
+		if err := appendHistory(path, nil, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := rateBrew(path, nil, finished.Add(time.Hour).In(time.Local), 4); err != nil {
+		t.Fatalf("Failed to rate brew: %v", err)
+	}
+	records, err := loadHistory(path, nil)
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected three records, got %v, %v", records, err)
+	}
+	for i, want := range []int{0, 4, 0} {
+		if records[i].Rating != want {
+			t.Errorf("Record %d: expected rating %d, got %d", i, want, records[i].Rating)
+		}
+	}
+
+	if err := rateBrew(path, nil, finished.Add(-time.Hour), 4); err == nil {
+		t.Error("Expected an error rating a brew not in the history")
+	}
+}
+
+func TestRateAndAcceptSuggestion(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = filepath.Join(t.TempDir(), historyFileName)
+	m := initialModel(config)
+	usual := config.Presets[0].Duration
+	teaName := config.Presets[0].Name
+	shorter := usual - 30*time.Second
+	m.history = []brewRecord{{Time: now().Add(-time.Hour), Tea: teaName, Duration: Duration{shorter}, Rating: 5}}
+
+	// Brew the shorter time and rate it
+	m.config.Presets = slices.Clone(config.Presets)
+	m.config.Presets[0].Duration = shorter
+	m.timer = m.brewDuration()
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyStart)})
+	m = newModel.(model)
+	m.timer = time.Second
+	newModel, cmd := m.Update(tickLeft(m, 0))
+	cmdMsgs(cmd)
+	m = newModel.(model)
+	if !contains(m.View(), "Press 'v' to rate it") {
+		t.Error("Expected the finished view to offer a rating")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyRate)})
+	m = newModel.(model)
+	if m.inputKind != inputRating {
+		t.Fatal("Expected the rate key to open the rating prompt")
+	}
+	m.input.SetValue("9")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = newModel.(model); m.inputKind != inputRating {
+		t.Error("Expected an out of range rating to keep the prompt open")
+	}
+	m.input.SetValue("5")
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, msg := range cmdMsgs(cmd) {
+		if err, ok := msg.(errMsg); ok {
+			t.Fatalf("Expected the rating to be saved, got %v", err.err)
+		}
+	}
+	m = newModel.(model)
+	records, err := loadHistory(config.HistoryFile, nil)
+	if err != nil || len(records) != 1 || records[0].Rating != 5 {
+		t.Fatalf("Expected the rating in the history, got %+v, %v", records, err)
+	}
+	if !contains(m.View(), "Rated 5/5") {
+		t.Error("Expected the finished view to show the rating")
+	}
+
+	// Back at the preset's usual time, the rated one is suggested
+	m.config.Presets[0].Duration = usual
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyReset)})
+	m = newModel.(model)
+	want := "You rate " + teaName + " higher at " + m.config.TimeFormat.Format(shorter)
+	if !contains(m.View(), want) {
+		t.Fatalf("Expected the idle view to suggest %q", want)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeySuggest)})
+	m = newModel.(model)
+	if m.brewDuration() != shorter || m.customBrew() {
+		t.Errorf("Expected the preset to brew for %v, got %v", shorter, m.brewDuration())
+	}
+	if contains(m.View(), "You rate") {
+		t.Error("Expected the suggestion to go once accepted")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// continuedMsg reports that the process was continued after being stopped
+// from outside, e.g. with kill -STOP, when no ctrl+z came first.
+type continuedMsg struct{}
+
+// suspend hands the terminal back to the shell on ctrl+z. A running brew
+// keeps counting down in wall-clock time unless pause_on_suspend is set, in
+// which case it pauses until go-brew is brought back with fg.
+func (m model) suspend(at time.Time) (model, tea.Cmd) {
+	m.suspendedAt = at
+	if m.config.PauseOnSuspend && m.isBrewing() {
+		m = m.pause()
+		m.suspendPaused = true
+	}
+	return m, tea.Suspend
+}
+
+// resumed brings the timer up to date once the process runs again. The
+// tick chain stalled while it was stopped, so a running countdown or
+// stopwatch is read from its deadline, and a brew paused for the suspension
+// resumes. The screen is cleared and the
+// size asked again, since the terminal may have changed in the meantime.
+func (m model) resumed(at time.Time) (model, tea.Cmd) {
+	redraw := tea.Batch(tea.ClearScreen, tea.WindowSize())
+	if m.suspendPaused {
+		m.suspendPaused = false
+		m, cmd := m.resume()
+		return m, tea.Batch(cmd, redraw)
+	}
+	if !m.isBrewing() {
+		return m, redraw
+	}
+	// The deadline already covers the time spent suspended
+	if m.stopwatch {
+		m.timer = max(0, at.Sub(m.deadline))
+	} else {
+		m.timer = max(0, m.deadline.Sub(at))
+	}
+	m.suspendedAt = time.Time{}
+	// Retire the stalled chain so it cannot count the same tick twice
+	m.tickID++
+	return m, tea.Batch(tick(m.tickID, m.tickStep()), redraw)
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// canSuspend reports whether ctrl+z can suspend go-brew to the shell. There
+// is no job control to return to here.
+const canSuspend = false
+
+// watchContinue does nothing where processes are not stopped and continued.
+func watchContinue(p *tea.Program) {}
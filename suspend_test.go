@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSuspendKeepsWallClock(t *testing.T) {
+	m := initialModel(NewConfig())
+	m, _ = m.start()
+	start := now()
+
+	m, cmd := m.suspend(start.Add(10 * time.Second))
+	if cmd == nil || cmd() != tea.Suspend() {
+		t.Fatal("Expected ctrl+z to suspend the program")
+	}
+	if !m.isBrewing() {
+		t.Error("Expected the brew to keep running while suspended")
+	}
+
+	// Brought back 90.5 seconds into the 4 minute brew
+	id := m.tickID
+	m, _ = m.resumed(start.Add(90*time.Second + 500*time.Millisecond))
+	if m.timer != 149*time.Second+500*time.Millisecond {
+		t.Errorf("Expected 2m29.5s left after catching up, got %v", m.timer)
+	}
+	if m.tickID == id {
+		t.Error("Expected the stalled tick chain to be retired")
+	}
+
+	// A brew that ran out while suspended finishes on the next tick
+	m, _ = m.resumed(start.Add(10 * time.Minute))
+	if m.timer != 0 {
+		t.Fatalf("Expected no time left, got %v", m.timer)
+	}
+	newModel, _ := m.Update(tickMsg{id: m.tickID, time: start.Add(10 * time.Minute)})
+	if !newModel.(model).isFinished() {
+		t.Error("Expected the brew to finish right after resuming")
+	}
+}
+
+func TestPauseOnSuspend(t *testing.T) {
+	config := NewConfig()
+	config.PauseOnSuspend = true
+	m := initialModel(config)
+	m, _ = m.start()
+	left := m.timer
+
+	m, _ = m.suspend(now())
+	if !m.isPaused() {
+		t.Fatal("Expected the brew to pause while suspended")
+	}
+	m, _ = m.resumed(now().Add(time.Hour))
+	if !m.isBrewing() || m.timer != left {
+		t.Errorf("Expected the brew to resume with %v left, got %v (%v)", left, m.timer, m.state)
+	}
+
+	// A brew paused by hand stays paused
+	m = m.pause()
+	m, _ = m.suspend(now())
+	if m, _ = m.resumed(now()); !m.isPaused() {
+		t.Error("Expected a brew paused before suspending to stay paused")
+	}
+}
+
+func TestSuspendStopwatch(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.stopwatch = true
+	m, _ = m.startStopwatch()
+	m.timer, m.deadline = 5*time.Second, now().Add(-5*time.Second)
+	m, _ = m.suspend(now())
+	m, _ = m.resumed(now().Add(42 * time.Second))
+	if m.timer != 47*time.Second {
+		t.Errorf("Expected the stopwatch to count the suspended time, got %v", m.timer)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// canSuspend reports whether ctrl+z can suspend go-brew to the shell.
+const canSuspend = true
+
+// watchContinue tells p whenever the process is continued after a stop, so
+// the countdown catches up and the screen is redrawn even after a stop that
+// did not come from ctrl+z.
+func watchContinue(p *tea.Program) {
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	go func() {
+		for range cont {
+			p.Send(continuedMsg{})
+		}
+	}()
+}
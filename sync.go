@@ -0,0 +1,514 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sync backends supported by SyncConfig.
+const (
+	SyncGit    = "git"    // A Git repository go-brew clones, commits to and pushes
+	SyncWebDAV = "webdav" // A WebDAV folder, such as Nextcloud's
+)
+
+// syncPasswordEnv holds the WebDAV password. It is never read from the
+// config file, which is itself synced.
+const syncPasswordEnv = "GOBREW_SYNC_PASSWORD"
+
+// Names of the synced files on the remote.
+const (
+	syncConfigName  = configFileName
+	syncHistoryName = historyFileName
+)
+
+// syncTimeout bounds a whole sync, including Git's network operations.
+var syncTimeout = 2 * time.Minute
+
+// syncClient makes the WebDAV requests.
+var syncClient = &http.Client{Timeout: 30 * time.Second}
+
+// SyncConfig describes where "go-brew sync" keeps the shared copies of the
+// config file and brew history.
+type SyncConfig struct {
+	Kind   string // SyncGit or SyncWebDAV, empty when sync is not set up
+	Remote string // Repository URL or WebDAV folder URL
+	User   string // WebDAV user name
+}
+
+// syncFiles holds the synced files by name. Missing files are absent.
+type syncFiles map[string][]byte
+
+// syncBackend reads and writes the shared copies of the synced files.
+type syncBackend interface {
+	fetch(ctx context.Context) (syncFiles, error)
+	store(ctx context.Context, files syncFiles, message string) error
+}
+
+// syncState remembers the content of each file as of the last sync, to tell
+// which side changed it since.
+type syncState struct {
+	Hashes map[string]string `json:"hashes"` // SHA-256 of each file by name
+}
+
+// runSyncCommand implements "go-brew sync" and returns the process exit
+// code:
+//
+//	go-brew sync [-config file] [-history file]
+//
+// The brew history is merged, so brews logged on any machine are kept. The
+// config file goes to whichever side changed it; if both did, the local one
+// is kept and the remote one saved next to it for a manual merge.
+func runSyncCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to sync")
+	fs.StringVar(&config.HistoryFile, "history", config.HistoryFile, "brew history `file` to sync")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\nusage: go-brew sync [-config file] [-history file]\n", fs.Arg(0))
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	backend, err := config.Sync.backend(os.Getenv(syncPasswordEnv))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	key, err := config.historyKey(os.Getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+	statePath := filepath.Join(dirs().State, "sync.json")
+	if err := syncWith(ctx, backend, config.ConfigPath, config.HistoryFile, key, statePath, stdout); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// backend returns the backend for the configured remote.
+func (s SyncConfig) backend(password string) (syncBackend, error) {
+	switch s.Kind {
+	case "":
+		return nil, errors.New("sync is not set up: set sync.type and sync.remote in the config file")
+	case SyncGit:
+		return &gitSync{remote: s.Remote, dir: filepath.Join(dirs().Cache, "sync")}, nil
+	case SyncWebDAV:
+		return &webdavSync{url: strings.TrimSuffix(s.Remote, "/"), user: s.User, password: password}, nil
+	}
+	return nil, fmt.Errorf("unknown sync type %q", s.Kind)
+}
+
+// validate checks that an enabled sync has a remote.
+func (s SyncConfig) validate() error {
+	if s.Kind != "" && s.Remote == "" {
+		return fmt.Errorf("sync.remote is required for %s sync", s.Kind)
+	}
+	return nil
+}
+
+// syncWith brings the local config and history files and the backend's
+// copies in line, reporting what it did to out.
+func syncWith(ctx context.Context, backend syncBackend, configPath, historyPath string, key *historyKey, statePath string, out io.Writer) error {
+	remote, err := backend.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching: %w", err)
+	}
+	state := loadSyncState(statePath)
+	push := syncFiles{}
+
+	// Brews are only ever added, so both logs merge without conflicts
+	if historyPath != "" {
+		local, err := readHistoryLog(historyPath)
+		if err != nil {
+			return err
+		}
+		merged, err := mergeHistory(local, remote[syncHistoryName], key)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(merged, local) {
+			if err := writeHistoryLog(historyPath, merged); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Pulled brews into %s\n", historyPath)
+		}
+		if !bytes.Equal(merged, remote[syncHistoryName]) {
+			push[syncHistoryName] = merged
+		}
+		state.Hashes[syncHistoryName] = hashOf(merged)
+	}
+
+	// The config file goes to the side that did not change it since the
+	// last sync
+	local, err := readOptional(configPath)
+	if err != nil {
+		return err
+	}
+	theirs, remoteHas := remote[syncConfigName]
+	base := state.Hashes[syncConfigName]
+	switch {
+	case remoteHas && bytes.Equal(local, theirs):
+	case !remoteHas || hashOf(theirs) == base:
+		if local != nil {
+			push[syncConfigName] = local
+		}
+	case local == nil || hashOf(local) == base:
+		if _, err := decodeConfig(theirs); err != nil {
+			return fmt.Errorf("remote %s: %w", syncConfigName, err)
+		}
+		if err := writeFileAtomic(configPath, theirs); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Pulled %s\n", configPath)
+		local = theirs
+	default:
+		// Changed on both sides: keep ours and let the user merge. The
+		// remote copy now counts as seen, so the next sync pushes the result
+		conflict := configPath + ".remote"
+		if err := writeFileAtomic(conflict, theirs); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Conflict: %s changed here and on the remote; kept the local file and saved the remote one as %s\n", configPath, conflict)
+		state.Hashes[syncConfigName] = hashOf(theirs)
+		return finishSync(ctx, backend, push, state, statePath, out)
+	}
+	if local != nil {
+		state.Hashes[syncConfigName] = hashOf(local)
+	}
+	return finishSync(ctx, backend, push, state, statePath, out)
+}
+
+// finishSync stores the files to push and saves the sync state.
+func finishSync(ctx context.Context, backend syncBackend, push syncFiles, state syncState, statePath string, out io.Writer) error {
+	if len(push) > 0 {
+		host, _ := os.Hostname()
+		if err := backend.store(ctx, push, "go-brew sync from "+host); err != nil {
+			return fmt.Errorf("pushing: %w", err)
+		}
+		names := make([]string, 0, len(push))
+		for name := range push {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(out, "Pushed %s\n", strings.Join(names, " and "))
+	} else {
+		fmt.Fprintln(out, "Already in sync")
+	}
+	return saveSyncState(statePath, state)
+}
+
+// mergeHistory combines two brew logs into one without duplicates, oldest
+// first. Records are told apart by their finish time and tea. With a key
+// the merged log is encrypted; as sealing the same brews twice never gives
+// the same bytes, an encrypted log that already holds every brew is
+// returned as it is, so an unchanged log isn't pushed again.
+func mergeHistory(a, b []byte, key *historyKey) ([]byte, error) {
+	seen := make(map[string]bool)
+	var records []brewRecord
+	var logs [2][]brewRecord
+	for i, data := range [][]byte{a, b} {
+		var err error
+		if logs[i], err = parseHistory(data, "brew history", key); err != nil {
+			return nil, fmt.Errorf("reading %w", err)
+		}
+		for _, rec := range logs[i] {
+			if id := brewID(rec); !seen[id] {
+				seen[id] = true
+				records = append(records, rec)
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	if key == nil {
+		return encodeHistory(records, nil, historyHeader{})
+	}
+	for i, data := range [][]byte{a, b} {
+		first, _, _ := bytes.Cut(data, []byte("\n"))
+		if _, encrypted := parseHistoryHeader(bytes.TrimSpace(first)); encrypted && sameRecords(logs[i], records) {
+			return data, nil
+		}
+	}
+	h, err := key.header(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return encodeHistory(records, key, h)
+}
+
+// brewID tells brews apart when logs are combined by the ID they were
+// logged with. Brews logged before IDs are told apart by tea and time: two
+// records of the same tea finished at the same moment are the same brew.
+func brewID(rec brewRecord) string {
+	if rec.ID != "" {
+		return rec.ID
+	}
+	return rec.Time.UTC().Format(time.RFC3339Nano) + "\x00" + rec.Tea
+}
+
+// sameRecords reports whether two lists hold the same brews in the same
+// order.
+func sameRecords(a, b []brewRecord) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+// decodeConfig checks that data is a config file go-brew can read.
+func decodeConfig(data []byte) (*fileConfig, error) {
+	dir, err := os.MkdirTemp("", "go-brew-sync")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, configFileName)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	fc, _, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if errs := fc.validate(); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return fc, nil
+}
+
+// readOptional reads the file at path, returning nil if it doesn't exist.
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// writeFileAtomic replaces the file at path with data, so a failed write
+// never leaves half a config file or brew log behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// hashOf returns the hex SHA-256 of data.
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadSyncState reads the state of the last sync. A missing or unreadable
+// state means no sync has happened yet.
+func loadSyncState(path string) syncState {
+	state := syncState{Hashes: map[string]string{}}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	if state.Hashes == nil {
+		state.Hashes = map[string]string{}
+	}
+	return state
+}
+
+// saveSyncState records the state after a sync.
+func saveSyncState(path string, state syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// gitSync keeps the synced files in a Git repository, through a clone in
+// the cache directory.
+type gitSync struct {
+	remote string // Repository URL
+	dir    string // Local clone
+}
+
+// git runs a git command in the clone.
+func (g *gitSync) git(ctx context.Context, args ...string) error {
+	_, err := g.output(ctx, args...)
+	return err
+}
+
+// output runs a git command in the clone and returns what it printed.
+func (g *gitSync) output(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, bytes.TrimSpace(out))
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
+
+// clone makes the local clone of the remote, replacing one of another
+// remote left by an earlier sync.remote.
+func (g *gitSync) clone(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); err == nil {
+		if origin, err := g.output(ctx, "remote", "get-url", "origin"); err == nil && origin == g.remote {
+			return nil
+		}
+		if err := os.RemoveAll(g.dir); err != nil {
+			return err
+		}
+	}
+	if err := ensureDir(filepath.Dir(g.dir)); err != nil {
+		return err
+	}
+	// The remote comes from the config file, so it must not be taken for an option
+	if out, err := exec.CommandContext(ctx, "git", "clone", "-q", "--", g.remote, g.dir).CombinedOutput(); err != nil {
+		return fmt.Errorf("git clone: %v: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// fetch clones the remote, or pulls into the clone, and reads the synced
+// files from it.
+func (g *gitSync) fetch(ctx context.Context) (syncFiles, error) {
+	if err := g.clone(ctx); err != nil {
+		return nil, err
+	}
+	// A freshly created repository has nothing to pull yet
+	if g.git(ctx, "rev-parse", "-q", "--verify", "HEAD") == nil {
+		if err := g.git(ctx, "pull", "-q", "--ff-only"); err != nil {
+			return nil, err
+		}
+	}
+
+	files := syncFiles{}
+	for _, name := range []string{syncConfigName, syncHistoryName} {
+		data, err := readOptional(filepath.Join(g.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			files[name] = data
+		}
+	}
+	return files, nil
+}
+
+// store writes files into the clone, commits them with message and pushes
+// the commit to the remote.
+func (g *gitSync) store(ctx context.Context, files syncFiles, message string) error {
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(g.dir, name), data, 0o600); err != nil {
+			return err
+		}
+		if err := g.git(ctx, "add", name); err != nil {
+			return err
+		}
+	}
+	if err := g.git(ctx, "commit", "-q", "-m", message); err != nil {
+		return err
+	}
+	return g.git(ctx, "push", "-q", "origin", "HEAD")
+}
+
+// webdavSync keeps the synced files in a WebDAV folder.
+type webdavSync struct {
+	url            string // Folder URL without a trailing slash
+	user, password string // Basic authentication, if the user is set
+}
+
+// request sends a request for the named file in the folder.
+func (w *webdavSync) request(ctx context.Context, method, name string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, w.url+"/"+name, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+	return syncClient.Do(req)
+}
+
+// fetch downloads the synced files from the folder, leaving out any it
+// doesn't hold yet.
+func (w *webdavSync) fetch(ctx context.Context) (syncFiles, error) {
+	files := syncFiles{}
+	for _, name := range []string{syncConfigName, syncHistoryName} {
+		resp, err := w.request(ctx, http.MethodGet, name, nil)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		switch {
+		case err != nil:
+			return nil, err
+		case resp.StatusCode == http.StatusNotFound:
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("GET %s: %s", name, resp.Status)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// store uploads files to the folder. WebDAV keeps no history, so message
+// is unused.
+func (w *webdavSync) store(ctx context.Context, files syncFiles, message string) error {
+	for name, data := range files {
+		resp, err := w.request(ctx, http.MethodPut, name, data)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("PUT %s: %s", name, resp.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeWebDAV stores the files PUT to it and serves them back.
+type fakeWebDAV struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (f *fakeWebDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "me" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := f.files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.files[r.URL.Path] = data
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// syncMachine is one computer's config, history and sync state.
+type syncMachine struct {
+	config, history, state string
+}
+
+func newSyncMachine(t *testing.T) syncMachine {
+	dir := t.TempDir()
+	return syncMachine{
+		config:  filepath.Join(dir, "config.toml"),
+		history: filepath.Join(dir, "history.jsonl"),
+		state:   filepath.Join(dir, "sync.json"),
+	}
+}
+
+func (s syncMachine) sync(t *testing.T, backend syncBackend) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := syncWith(context.Background(), backend, s.config, s.history, nil, s.state, &out); err != nil {
+		t.Fatalf("Expected sync to succeed, got %v", err)
+	}
+	return out.String()
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+const (
+	brewAt10 = `{"time":"2024-03-01T10:00:00Z","tea":"Green Tea","duration":"3m0s"}` + "\n"
+	brewAt11 = `{"time":"2024-03-01T11:00:00Z","tea":"Oolong","duration":"4m0s"}` + "\n"
+	brewAt12 = `{"time":"2024-03-01T12:00:00Z","tea":"Black Tea","duration":"4m0s"}` + "\n"
+)
+
+func TestMergeHistory(t *testing.T) {
+	merged, err := mergeHistory([]byte(brewAt12+brewAt10), []byte(brewAt11+brewAt10), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(merged); got != brewAt10+brewAt11+brewAt12 {
+		t.Errorf("Expected the union of both logs in time order, got\n%s", got)
+	}
+
+	if _, err := mergeHistory([]byte("not json"), nil, nil); err == nil {
+		t.Error("Expected a corrupt log to be rejected")
+	}
+}
+
+func TestSyncWebDAV(t *testing.T) {
+	dav := &fakeWebDAV{files: map[string][]byte{}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	backend, err := SyncConfig{Kind: SyncWebDAV, Remote: srv.URL + "/brew/", User: "me"}.backend("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	laptop, desktop := newSyncMachine(t), newSyncMachine(t)
+	writeTestFile(t, laptop.config, "[cues]\nhalfway = 40\n")
+	writeTestFile(t, laptop.history, brewAt10)
+	writeTestFile(t, desktop.history, brewAt11)
+
+	if out := laptop.sync(t, backend); !strings.Contains(out, "Pushed config.toml and history.jsonl") {
+		t.Errorf("Expected the laptop's files to be pushed, got %q", out)
+	}
+	if out := desktop.sync(t, backend); !strings.Contains(out, "Pulled") || !strings.Contains(out, "Pushed history.jsonl") {
+		t.Errorf("Expected the desktop to pull the config and push the merged history, got %q", out)
+	}
+	if got := readTestFile(t, desktop.config); got != "[cues]\nhalfway = 40\n" {
+		t.Errorf("Expected the laptop's config on the desktop, got %q", got)
+	}
+	if got := readTestFile(t, desktop.history); got != brewAt10+brewAt11 {
+		t.Errorf("Expected both brews on the desktop, got %q", got)
+	}
+
+	laptop.sync(t, backend)
+	if got := readTestFile(t, laptop.history); got != brewAt10+brewAt11 {
+		t.Errorf("Expected the desktop's brew on the laptop, got %q", got)
+	}
+	if out := laptop.sync(t, backend); !strings.Contains(out, "Already in sync") {
+		t.Errorf("Expected nothing left to sync, got %q", out)
+	}
+}
+
+func TestSyncConfigConflict(t *testing.T) {
+	dav := &fakeWebDAV{files: map[string][]byte{}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	backend := &webdavSync{url: srv.URL, user: "me", password: "secret"}
+
+	laptop, desktop := newSyncMachine(t), newSyncMachine(t)
+	writeTestFile(t, laptop.config, "[cues]\nhalfway = 40\n")
+	laptop.sync(t, backend)
+	desktop.sync(t, backend)
+
+	// Only the laptop changes its config: the desktop takes it
+	writeTestFile(t, laptop.config, "[cues]\nhalfway = 60\n")
+	laptop.sync(t, backend)
+	desktop.sync(t, backend)
+	if got := readTestFile(t, desktop.config); got != "[cues]\nhalfway = 60\n" {
+		t.Errorf("Expected the one-sided change to be pulled, got %q", got)
+	}
+
+	// Both change it: the desktop keeps its own and saves the laptop's
+	writeTestFile(t, laptop.config, "[cues]\nhalfway = 70\n")
+	writeTestFile(t, desktop.config, "[cues]\nhalfway = 30\n")
+	laptop.sync(t, backend)
+	if out := desktop.sync(t, backend); !strings.Contains(out, "Conflict") {
+		t.Errorf("Expected a conflict to be reported, got %q", out)
+	}
+	if got := readTestFile(t, desktop.config); got != "[cues]\nhalfway = 30\n" {
+		t.Errorf("Expected the local config to be kept, got %q", got)
+	}
+	if got := readTestFile(t, desktop.config+".remote"); got != "[cues]\nhalfway = 70\n" {
+		t.Errorf("Expected the remote config to be saved alongside, got %q", got)
+	}
+
+	// Once resolved, the desktop's version wins
+	desktop.sync(t, backend)
+	laptop.sync(t, backend)
+	if got := readTestFile(t, laptop.config); got != "[cues]\nhalfway = 30\n" {
+		t.Errorf("Expected the resolved config on the laptop, got %q", got)
+	}
+}
+
+func TestSyncRejectsInvalidRemoteConfig(t *testing.T) {
+	dav := &fakeWebDAV{files: map[string][]byte{"/config.toml": []byte("[cues]\nhalfway = 150\n")}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	backend := &webdavSync{url: srv.URL, user: "me", password: "secret"}
+
+	m := newSyncMachine(t)
+	err := syncWith(context.Background(), backend, m.config, m.history, nil, m.state, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "cues.halfway") {
+		t.Errorf("Expected the invalid remote config to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(m.config); err == nil {
+		t.Error("Expected the invalid config not to be written")
+	}
+}
+
+func TestSyncGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "Test")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "test@example.com")
+	}
+	remote := filepath.Join(t.TempDir(), "brew.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	laptop, desktop := newSyncMachine(t), newSyncMachine(t)
+	laptopGit := &gitSync{remote: remote, dir: filepath.Join(t.TempDir(), "clone")}
+	desktopGit := &gitSync{remote: remote, dir: filepath.Join(t.TempDir(), "clone")}
+	writeTestFile(t, laptop.config, "[cues]\nhalfway = 40\n")
+	writeTestFile(t, laptop.history, brewAt10)
+	writeTestFile(t, desktop.history, brewAt11)
+
+	laptop.sync(t, laptopGit)
+	desktop.sync(t, desktopGit)
+	laptop.sync(t, laptopGit)
+	if got := readTestFile(t, desktop.config); got != "[cues]\nhalfway = 40\n" {
+		t.Errorf("Expected the laptop's config on the desktop, got %q", got)
+	}
+	if got := readTestFile(t, laptop.history); got != brewAt10+brewAt11 {
+		t.Errorf("Expected both brews on the laptop, got %q", got)
+	}
+
+	// A changed sync.remote is cloned afresh rather than ignored
+	moved := filepath.Join(t.TempDir(), "moved.git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", moved).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	laptopGit.remote = moved
+	laptop.sync(t, laptopGit)
+	if origin, err := laptopGit.output(context.Background(), "remote", "get-url", "origin"); err != nil || origin != moved {
+		t.Errorf("Expected the clone to follow the new remote, got %q, %v", origin, err)
+	}
+	if out, err := exec.Command("git", "-C", moved, "log", "--oneline").CombinedOutput(); err != nil || len(out) == 0 {
+		t.Errorf("Expected the sync pushed to the new remote, got %q, %v", out, err)
+	}
+}
+
+func TestSyncNotSetUp(t *testing.T) {
+	if _, err := (SyncConfig{}).backend(""); err == nil {
+		t.Error("Expected an error when sync is not set up")
+	}
+	if err := (SyncConfig{Kind: SyncGit}).validate(); err == nil {
+		t.Error("Expected an error for a git sync without a remote")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+
+// Taskbar progress states of the OSC 9;4 sequence.
+const (
+	taskbarClear  = 0 // No progress shown
+	taskbarNormal = 1 // Progress in the normal color
+	taskbarPaused = 4 // Progress in the paused (warning) color
+)
+
+// taskbarSequence returns the OSC 9;4 escape sequence that sets the taskbar
+// progress of the terminal's window to state and percent.
+func taskbarSequence(state, percent int) string {
+	return fmt.Sprintf("\x1b]9;4;%d;%d\x1b\\", state, percent)
+}
+
+// detectTaskbarProgress reports whether the terminal shows OSC 9;4 progress
+// on its taskbar icon. Only Windows Terminal and ConEmu are trusted with
+// it, as other terminals take OSC 9 for a desktop notification.
+func detectTaskbarProgress(getenv func(string) string) bool {
+	return getenv("WT_SESSION") != "" || getenv("ConEmuPID") != ""
+}
+
+// taskbarProgress returns the sequence showing the brew's progress on the
+// taskbar: filling while brewing, in the paused color while paused and
+// cleared otherwise, so a finished or reset brew leaves no bar behind. It
+// returns "" unless display.taskbar_progress is on in a terminal that
+// supports it.
+func (m model) taskbarProgress() string {
+	if !m.config.TaskbarProgress || !m.config.TaskbarTerminal {
+		return ""
+	}
+	total := m.brewDuration()
+	if m.stopwatch || total <= 0 || !(m.isBrewing() || m.isPaused()) {
+		return taskbarSequence(taskbarClear, 0)
+	}
+	percent := int(100 * (total - m.timer) / total)
+	if m.isPaused() {
+		return taskbarSequence(taskbarPaused, percent)
+	}
+	return taskbarSequence(taskbarNormal, percent)
+}
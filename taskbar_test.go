@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectTaskbarProgress(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{"WT_SESSION": "0f6c"}, true},
+		{map[string]string{"ConEmuPID": "1234"}, true},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := detectTaskbarProgress(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("detectTaskbarProgress(%v): expected %v, got %v", tt.env, tt.want, got)
+		}
+	}
+}
+
+func TestTaskbarProgress(t *testing.T) {
+	config := NewConfig()
+	config.TaskbarTerminal = true
+	m := initialModel(config)
+
+	if got, want := m.taskbarProgress(), "\x1b]9;4;0;0\x1b\\"; got != want {
+		t.Errorf("Expected idle to clear the progress, got %q", got)
+	}
+
+	m.state = StateBrewing
+	m.timer = m.brewDuration() / 4
+	if got, want := m.taskbarProgress(), "\x1b]9;4;1;75\x1b\\"; got != want {
+		t.Errorf("Expected %q while brewing, got %q", want, got)
+	}
+	if !strings.HasPrefix(m.View(), "\x1b]9;4;1;75") {
+		t.Error("Expected the frame to start with the progress sequence")
+	}
+
+	m.state = StatePaused
+	if got, want := m.taskbarProgress(), "\x1b]9;4;4;75\x1b\\"; got != want {
+		t.Errorf("Expected %q while paused, got %q", want, got)
+	}
+
+	m.state, m.timer = StateFinished, 0
+	if got, want := m.taskbarProgress(), "\x1b]9;4;0;0\x1b\\"; got != want {
+		t.Errorf("Expected the finished brew to clear the progress, got %q", got)
+	}
+
+	// Switched off, or in other terminals, nothing is sent
+	m.state, m.timer = StateBrewing, time.Minute
+	config.TaskbarProgress = false
+	if got := m.taskbarProgress(); got != "" {
+		t.Errorf("Expected no sequence with display.taskbar_progress off, got %q", got)
+	}
+	config.TaskbarProgress, config.TaskbarTerminal = true, false
+	if got := m.taskbarProgress(); got != "" {
+		t.Errorf("Expected no sequence in an unsupported terminal, got %q", got)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// telemetryFileName is the name of the usage metrics state inside the state
+// directory. It holds the user's answer and the counts not yet reported.
+const telemetryFileName = "telemetry.json"
+
+// telemetryInterval is how often collected counts are reported.
+const telemetryInterval = 7 * 24 * time.Hour
+
+// telemetryClient sends the usage reports.
+var telemetryClient = &http.Client{Timeout: 10 * time.Second}
+
+// telemetryState is the stored telemetry consent and the usage counted
+// since the last report. Nothing but these counters is ever collected.
+type telemetryState struct {
+	Asked   bool           `json:"asked"`           // Whether the first-run question was answered
+	Enabled bool           `json:"enabled"`         // Whether the user opted in
+	Since   time.Time      `json:"since,omitzero"`  // Start of the counting period
+	Counts  map[string]int `json:"counts,omitzero"` // Uses of each feature in the period
+}
+
+// telemetryReport is the body of a usage report.
+type telemetryReport struct {
+	Version string         `json:"version"` // go-brew version
+	OS      string         `json:"os"`      // Operating system, e.g. "linux"
+	Since   time.Time      `json:"since"`   // Start of the counting period
+	Counts  map[string]int `json:"counts"`  // Uses of each feature in the period
+}
+
+// defaultTelemetryPath returns the location of the telemetry state.
+func defaultTelemetryPath() string {
+	return filepath.Join(dirs().State, telemetryFileName)
+}
+
+// loadTelemetry reads the telemetry state at path. A missing file means the
+// question has not been asked yet.
+func loadTelemetry(path string) (telemetryState, error) {
+	var s telemetryState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// save writes the telemetry state to path.
+func (s telemetryState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// setTelemetry records the user's answer. Turning telemetry off also drops
+// the counts that were not reported yet.
+func setTelemetry(path string, enabled bool) error {
+	s, err := loadTelemetry(path)
+	if err != nil {
+		return err
+	}
+	s.Asked, s.Enabled = true, enabled
+	if !enabled {
+		s.Since, s.Counts = time.Time{}, nil
+	}
+	return s.save(path)
+}
+
+// countUsage adds one use of each feature to the telemetry state at path if
+// the user opted in, and reports the counts to endpoint once they cover
+// telemetryInterval. The counts are kept for a later try if reporting fails.
+func countUsage(ctx context.Context, path, endpoint string, features []string, at time.Time) error {
+	s, err := loadTelemetry(path)
+	if err != nil || !s.Enabled {
+		return err
+	}
+	if s.Counts == nil {
+		s.Counts, s.Since = make(map[string]int), at
+	}
+	for _, f := range features {
+		s.Counts[f]++
+	}
+	if endpoint != "" && at.Sub(s.Since) >= telemetryInterval {
+		if err := sendTelemetry(ctx, endpoint, s); err != nil {
+			s.save(path)
+			return err
+		}
+		s.Since, s.Counts = at, make(map[string]int)
+	}
+	return s.save(path)
+}
+
+// sendTelemetry posts the counts of s to endpoint.
+func sendTelemetry(ctx context.Context, endpoint string, s telemetryState) error {
+	body, err := json.Marshal(telemetryReport{Version: version, OS: runtime.GOOS, Since: s.Since, Counts: s.Counts})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := telemetryClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("usage report: %s", resp.Status)
+	}
+	return nil
+}
+
+// usageFeatures names what the finished brew used, for the telemetry counts.
+func (m model) usageFeatures() []string {
+	features := []string{"brew"}
+	switch {
+	case m.config.Recipe != "":
+		features = append(features, "recipe")
+	case len(m.config.Sequence) > 0:
+		features = append(features, "sequence")
+	case !m.alarmAt.IsZero():
+		features = append(features, "alarm")
+	case m.customBrew():
+		features = append(features, "custom")
+	default:
+		features = append(features, "preset")
+	}
+	if _, ok := m.steepDuration(); ok {
+		features = append(features, "steeps")
+	}
+	if m.note != "" {
+		features = append(features, "note")
+	}
+	return features
+}
+
+// usageCmd returns a command that counts the features a finished brew used,
+// or nil unless the user opted in. Failures are only logged: usage metrics
+// are no reason to bother the user.
+func (m model) usageCmd(at time.Time) tea.Cmd {
+	if !m.config.Telemetry || m.config.TelemetryPath == "" {
+		return nil
+	}
+	path, endpoint, features := m.config.TelemetryPath, m.config.TelemetryEndpoint, m.usageFeatures()
+	return func() tea.Msg {
+		if err := countUsage(context.Background(), path, endpoint, features, at); err != nil {
+			log.Printf("Counting usage failed: %v", err)
+		}
+		return nil
+	}
+}
+
+// askTelemetry opens the first-run question about usage metrics.
+func (m model) askTelemetry() model {
+	m, _ = m.openInput(inputTelemetry, "Share anonymous usage counts to help improve go-brew? (y/N) ", "")
+	m.input.CharLimit = 3
+	m.input.Width = 3
+	return m
+}
+
+// answerTelemetry applies the answer to the first-run question. Anything
+// but yes declines.
+func (m model) answerTelemetry(answer string) (model, tea.Cmd) {
+	m = m.closeInput()
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	enabled := answer == "y" || answer == "yes"
+	m.config.Telemetry = enabled
+	path := m.config.TelemetryPath
+	save := func() tea.Msg {
+		if err := setTelemetry(path, enabled); err != nil {
+			return errMsg{fmt.Errorf("saving telemetry choice: %w", err)}
+		}
+		return nil
+	}
+	status := "Usage counts stay off"
+	if enabled {
+		status = "Thanks! 'go-brew telemetry off' stops the usage counts"
+	}
+	m, cmd := m.showStatus(status)
+	return m, tea.Batch(save, cmd)
+}
+
+// runTelemetryCommand implements "go-brew telemetry" and returns the process
+// exit code:
+//
+//	go-brew telemetry status [-config file]  show what is collected and the choice made
+//	go-brew telemetry on                     opt in to usage counts
+//	go-brew telemetry off                    opt out and drop unreported counts
+func runTelemetryCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: go-brew telemetry status|on|off [-config file]")
+		return 2
+	}
+	sub := args[0]
+	fs := flag.NewFlagSet("telemetry "+sub, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	config := NewConfig()
+	fs.StringVar(&config.ConfigPath, "config", config.ConfigPath, "user config `file` to read")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	config.setFlags = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		config.setFlags[f.Name] = true
+	})
+
+	path := defaultTelemetryPath()
+	switch sub {
+	case "on", "off":
+		if err := setTelemetry(path, sub == "on"); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Usage counts are %s\n", sub)
+		return 0
+	case "status":
+		if err := config.Load(); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		s, err := loadTelemetry(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		writeTelemetryStatus(stdout, s, config.TelemetryEndpoint)
+		return 0
+	}
+	fmt.Fprintf(stderr, "unknown telemetry command %q\nusage: go-brew telemetry status|on|off [-config file]\n", sub)
+	return 2
+}
+
+// writeTelemetryStatus explains the telemetry choice and shows the counts
+// waiting to be reported.
+func writeTelemetryStatus(w io.Writer, s telemetryState, endpoint string) {
+	switch {
+	case !s.Asked:
+		fmt.Fprintln(w, "Usage counts: off (not asked yet)")
+	case s.Enabled:
+		fmt.Fprintln(w, "Usage counts: on")
+	default:
+		fmt.Fprintln(w, "Usage counts: off")
+	}
+	fmt.Fprintln(w, "Collected: the number of brews and of the features they used, the go-brew")
+	fmt.Fprintln(w, "version and the operating system. Never tea names, notes or anything typed.")
+	if endpoint == "" {
+		fmt.Fprintln(w, "Reported to: nowhere (telemetry.endpoint is not set), counts stay on this machine")
+	} else {
+		fmt.Fprintf(w, "Reported to: %s, once a week\n", endpoint)
+	}
+	if len(s.Counts) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nCounted since %s:\n", s.Since.Format(time.DateOnly))
+	names := make([]string, 0, len(s.Counts))
+	for name := range s.Counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %d\n", name, s.Counts[name])
+	}
+}
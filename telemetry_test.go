@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCountUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), telemetryFileName)
+	start := now()
+
+	// Nothing is counted before the user opts in
+	if err := countUsage(context.Background(), path, "", []string{"brew"}, start); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := loadTelemetry(path); s.Asked || len(s.Counts) != 0 {
+		t.Fatalf("Expected nothing counted without consent, got %+v", s)
+	}
+
+	if err := setTelemetry(path, true); err != nil {
+		t.Fatal(err)
+	}
+	countUsage(context.Background(), path, "", []string{"brew", "preset"}, start)
+	countUsage(context.Background(), path, "", []string{"brew", "note"}, start.Add(time.Hour))
+	s, err := loadTelemetry(path)
+	if err != nil || s.Counts["brew"] != 2 || s.Counts["preset"] != 1 || s.Counts["note"] != 1 || !s.Since.Equal(start) {
+		t.Errorf("Expected 2 brews with a preset and a note since the first, got %+v, %v", s, err)
+	}
+
+	// Opting out drops what was not reported
+	setTelemetry(path, false)
+	if s, _ := loadTelemetry(path); !s.Asked || s.Enabled || len(s.Counts) != 0 {
+		t.Errorf("Expected counts dropped on opt-out, got %+v", s)
+	}
+}
+
+func TestUsageReport(t *testing.T) {
+	var reports []telemetryReport
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report telemetryReport
+		json.NewDecoder(r.Body).Decode(&report)
+		reports = append(reports, report)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), telemetryFileName)
+	setTelemetry(path, true)
+	start := now()
+	countUsage(context.Background(), path, srv.URL, []string{"brew"}, start)
+	if len(reports) != 0 {
+		t.Fatal("Expected no report before a week of counts")
+	}
+
+	// A failed report keeps the counts for the next try
+	status = http.StatusServiceUnavailable
+	if err := countUsage(context.Background(), path, srv.URL, []string{"brew"}, start.Add(telemetryInterval)); err == nil {
+		t.Error("Expected the failed report to be returned")
+	}
+	if s, _ := loadTelemetry(path); s.Counts["brew"] != 2 {
+		t.Errorf("Expected the counts kept after a failed report, got %+v", s)
+	}
+
+	status = http.StatusNoContent
+	countUsage(context.Background(), path, srv.URL, []string{"brew"}, start.Add(telemetryInterval+time.Hour))
+	if len(reports) != 2 || reports[1].Counts["brew"] != 3 || reports[1].Version != version {
+		t.Fatalf("Expected a report of 3 brews, got %+v", reports)
+	}
+	if s, _ := loadTelemetry(path); len(s.Counts) != 0 {
+		t.Errorf("Expected the counts reset after reporting, got %+v", s)
+	}
+}
+
+func TestTelemetryFirstRunPrompt(t *testing.T) {
+	config := NewConfig()
+	config.TelemetryPath = filepath.Join(t.TempDir(), telemetryFileName)
+	config.AskTelemetry = true
+	m := initialModel(config)
+	if m.inputKind != inputTelemetry || !contains(m.View(), "Share anonymous usage counts") {
+		t.Fatalf("Expected the first-run question, got\n%s", m.View())
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	newModel, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(model)
+	// Save the choice without waiting for the status line to clear
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		batch[0]()
+	}
+	if m.inputKind != inputNone || !config.Telemetry {
+		t.Errorf("Expected yes to opt in, got telemetry %v", config.Telemetry)
+	}
+	if s, err := loadTelemetry(config.TelemetryPath); err != nil || !s.Asked || !s.Enabled {
+		t.Errorf("Expected the choice to be saved, got %+v, %v", s, err)
+	}
+	if features := m.usageFeatures(); strings.Join(features, " ") != "brew preset" {
+		t.Errorf("Expected a preset brew to count brew and preset, got %v", features)
+	}
+}
+
+func TestTelemetryStatus(t *testing.T) {
+	var buf bytes.Buffer
+	writeTelemetryStatus(&buf, telemetryState{Asked: true, Enabled: true, Since: now(), Counts: map[string]int{"brew": 4}}, "")
+	out := buf.String()
+	for _, want := range []string{"Usage counts: on", "Never tea names", "counts stay on this machine", "brew      4"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected status to contain %q, got\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// padRight pads s with spaces to width terminal cells. Text is measured in
+// cells, not bytes or runes: CJK characters and most emoji take two cells,
+// and combining marks and ANSI styles none, so padding by rune count would
+// leave columns of Japanese tea names ragged. s is returned unchanged when
+// it is already that wide.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
+}
+
+// maxWidth returns the width in terminal cells of the widest of texts.
+func maxWidth(texts ...string) int {
+	widest := 0
+	for _, s := range texts {
+		widest = max(widest, lipgloss.Width(s))
+	}
+	return widest
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPadRight(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want string
+	}{
+		{"Sencha", "Sencha  "},
+		{"玉露", "玉露    "},
+		{"🍵 Matcha", "🍵 Matcha"},
+		{"Hojicha tea", "Hojicha tea"},
+	} {
+		if got := padRight(tc.s, 8); got != tc.want {
+			t.Errorf("Expected padRight(%q, 8) = %q, got %q", tc.s, tc.want, got)
+		}
+	}
+}
+
+// TestWideNamesAlign checks that the columns after tea names line up when
+// some names are written in wide characters.
+func TestWideNamesAlign(t *testing.T) {
+	sameWidth := func(what, text string) {
+		t.Helper()
+		lines := strings.Split(text, "\n")
+		for _, line := range lines[1:] {
+			if lipgloss.Width(line) != lipgloss.Width(lines[0]) {
+				t.Errorf("Expected the %s lines to be equally wide, got:\n%s", what, text)
+				return
+			}
+		}
+	}
+
+	sameWidth("bar chart", barChart([]string{"玉露", "Sencha", "🌿 Mint"}, []int{3, 2, 1}, 10))
+
+	config := NewConfig()
+	config.Presets = []TeaPreset{
+		{Name: "玉露", Duration: 2 * time.Minute},
+		{Name: "Sencha", Duration: time.Minute},
+		{Name: "🌿 Mint", Duration: 5 * time.Minute},
+	}
+	sameWidth("preset list", initialModel(config).renderPresetList(lipgloss.NewStyle()))
+}
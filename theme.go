@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Theme names a built-in set of state colors. The colorblind-safe themes
+// tell the states apart by hue and brightness along the color axis each
+// kind of colorblindness leaves intact.
+type Theme string
+
+const (
+	// ThemeDefault is the original green, yellow and orange palette
+	ThemeDefault Theme = "default"
+	// ThemeDeuteranopia avoids red-green contrasts, the most common kind
+	ThemeDeuteranopia Theme = "deuteranopia"
+	// ThemeProtanopia avoids red-green contrasts and relies less on reds,
+	// which look dark to protanopes
+	ThemeProtanopia Theme = "protanopia"
+	// ThemeTritanopia avoids blue-yellow contrasts
+	ThemeTritanopia Theme = "tritanopia"
+)
+
+// themes lists the valid themes in the order they are documented.
+var themes = []Theme{ThemeDefault, ThemeDeuteranopia, ThemeProtanopia, ThemeTritanopia}
+
+// themePalettes holds the colors of each theme. The colorblind-safe ones
+// are drawn from the Okabe-Ito palette.
+var themePalettes = map[Theme]Palette{
+	ThemeDefault:      {Ready: ColorReady, Brewing: ColorBrewing, Paused: ColorPaused, Idle: ColorIdle, Halfway: ColorHalfway, Final: ColorFinal},
+	ThemeDeuteranopia: {Ready: "#56B4E9", Brewing: "#F0E442", Paused: "#CC79A7", Idle: ColorIdle, Halfway: "#E69F00", Final: "#D55E00"},
+	ThemeProtanopia:   {Ready: "#3DA5FF", Brewing: "#F5E94B", Paused: "#8C7A00", Idle: ColorIdle, Halfway: "#FFC20A", Final: "#FFFFFF"},
+	ThemeTritanopia:   {Ready: "#00CED1", Brewing: "#FF8FA3", Paused: "#D55E00", Idle: ColorIdle, Halfway: "#FF5C7A", Final: "#E8002E"},
+}
+
+// UnmarshalText validates and stores a theme name.
+func (t *Theme) UnmarshalText(text []byte) error {
+	for _, theme := range themes {
+		if strings.EqualFold(string(text), string(theme)) {
+			*t = theme
+			return nil
+		}
+	}
+	names := make([]string, len(themes))
+	for i, theme := range themes {
+		names[i] = fmt.Sprintf("%q", theme)
+	}
+	return fmt.Errorf("invalid theme %q (use %s)", text, strings.Join(names, ", "))
+}
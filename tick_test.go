@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTickStep(t *testing.T) {
+	config := NewConfig()
+	m := initialModel(config)
+	if got := m.tickStep(); got != time.Second {
+		t.Errorf("Expected a one second tick by default, got %v", got)
+	}
+
+	config.TickInterval = 250 * time.Millisecond
+	if got := m.tickStep(); got != 250*time.Millisecond {
+		t.Errorf("Expected the configured tick, got %v", got)
+	}
+
+	// The last tick of a countdown is only as long as what is left
+	config.TickInterval = 5 * time.Second
+	m.timer = 3 * time.Second
+	if got := m.tickStep(); got != 3*time.Second {
+		t.Errorf("Expected the final tick to end the brew on time, got %v", got)
+	}
+	m.stopwatch = true
+	if got := m.tickStep(); got != 2*time.Second {
+		t.Errorf("Expected the stopwatch to tick at its next whole interval, got %v", got)
+	}
+
+	// A late tick is followed by a shorter one, back in step with the deadline
+	config.TickInterval = time.Second
+	m.stopwatch = false
+	m.timer = 2*time.Minute + 700*time.Millisecond
+	if got := m.tickStep(); got != 700*time.Millisecond {
+		t.Errorf("Expected the next tick at a whole second left, got %v", got)
+	}
+}
+
+func TestLongTicksFinishOnTime(t *testing.T) {
+	config := NewConfig()
+	config.SoundEnabled = false
+	config.NotifyEnabled = false
+	config.HistoryFile = ""
+	config.TickInterval = 2 * time.Second
+	m := initialModel(config)
+	m, _ = m.start()
+	m.timer, m.deadline = 5*time.Second, now().Add(5*time.Second)
+
+	var ticks int
+	for m.isBrewing() && ticks < 10 {
+		newModel, _ := m.Update(tickLeft(m, m.timer-m.tickStep()))
+		m = newModel.(model)
+		ticks++
+	}
+	if !m.isFinished() || ticks != 3 {
+		t.Errorf("Expected 5s to finish after ticks at 4s, 2s and 0s left, got %d ticks in state %v", ticks, m.state)
+	}
+
+	// A late tick still counts the time that really passed
+	m, _ = m.start()
+	newModel, _ := m.Update(tickLeft(m, m.timer-2500*time.Millisecond))
+	if m = newModel.(model); m.timer != m.brewDuration()-2500*time.Millisecond {
+		t.Errorf("Expected the timer read from the deadline, got %v", m.timer)
+	}
+}
+
+func TestPauseBetweenTicks(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	start := now()
+	config := NewConfig()
+	config.SoundEnabled, config.NotifyEnabled, config.HistoryFile = false, false, ""
+	config.TickInterval = 5 * time.Second
+	m := initialModel(config)
+	m, _ = m.start()
+
+	// Paused 4.9s in, before the first 5s tick arrives
+	now = func() time.Time { return start.Add(4900 * time.Millisecond) }
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(model)
+	want := 4*time.Minute - 4900*time.Millisecond
+	if !m.isPaused() || m.timer != want {
+		t.Fatalf("Expected %v left when paused, got %v", want, m.timer)
+	}
+	m, _ = m.resume()
+	if got := m.deadline.Sub(now()); got != want {
+		t.Errorf("Expected the resumed brew to end %v from now, got %v", want, got)
+	}
+
+	// The stopwatch keeps the time since its last tick too
+	m = initialModel(config)
+	m.stopwatch = true
+	now = func() time.Time { return start }
+	m, _ = m.startStopwatch()
+	now = func() time.Time { return start.Add(4900 * time.Millisecond) }
+	if m = m.pause(); m.timer != 4900*time.Millisecond {
+		t.Errorf("Expected the stopwatch paused at 4.9s, got %v", m.timer)
+	}
+}
+
+func TestSubSecondStopwatch(t *testing.T) {
+	config := NewConfig()
+	config.TickInterval = 250 * time.Millisecond
+	config.Stopwatch = true
+	m := initialModel(config)
+	m, _ = m.startStopwatch()
+	for range 5 {
+		newModel, _ := m.Update(tickLeft(m, -m.timer-m.tickStep()))
+		m = newModel.(model)
+	}
+	if m.timer != 1250*time.Millisecond || m.shownTimer() != time.Second {
+		t.Errorf("Expected 1.25s counted and 1s shown, got %v and %v", m.timer, m.shownTimer())
+	}
+}
+
+func TestTickIntervalBounds(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		valid bool
+	}{
+		{"250ms", true},
+		{"5s", true},
+		{"50ms", false},
+		{"10s", false},
+	} {
+		fc, _, err := loadConfigFile(writeConfig(t, "[display]\ntick_interval = \""+tt.value+"\"\n"))
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if errs := fc.validate(); (len(errs) == 0) != tt.valid {
+			t.Errorf("tick_interval = %q: expected valid %v, got %v", tt.value, tt.valid, errs)
+		}
+	}
+}
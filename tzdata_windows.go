@@ -0,0 +1,5 @@
+package main
+
+// Windows has no zoneinfo database for time.LoadLocation to read, so the
+// time zones an -at alarm may name are built in.
+import _ "time/tzdata"
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is the number of actions that can be undone; older ones are dropped.
+const maxUndo = 10
+
+// undoEntry is the timer state saved before an undoable action such as a
+// reset or a preset change, with a short description of the action.
+type undoEntry struct {
+	action         string          // What the action did, e.g. "reset"
+	timer          time.Duration   // Remaining time before the action
+	state          TimerState      // Timer state before the action
+	presetIdx      int             // Selected preset before the action
+	customDuration time.Duration   // Typed duration before the action
+	brewTea        string          // Tea of the brew that was running, if any
+	step           int             // Step of the -sequence before the action
+	laps           []time.Duration // Stopwatch laps before the action
+	alarmAt        time.Time       // Alarm time before the action
+	infusion       int             // Infusion of the selected preset before the action
+}
+
+// saveUndo records the current timer state so the action about to be taken
+// can be undone. The history is copied rather than appended in place because
+// model copies may share the backing array.
+func (m model) saveUndo(action string) model {
+	entry := undoEntry{
+		action:         action,
+		timer:          m.timer,
+		state:          m.state,
+		presetIdx:      m.presetIdx,
+		customDuration: m.customDuration,
+		brewTea:        m.brewTea,
+		step:           m.step,
+		laps:           m.laps,
+		alarmAt:        m.alarmAt,
+		infusion:       m.infusion,
+	}
+	history := m.undo
+	if len(history) >= maxUndo {
+		history = history[len(history)-maxUndo+1:]
+	}
+	m.undo = append(history[:len(history):len(history)], entry)
+	return m
+}
+
+// undoLast restores the timer state saved before the most recent undoable
+// action. A brew that was running continues counting down from where it was.
+func (m model) undoLast() (model, tea.Cmd) {
+	if len(m.undo) == 0 {
+		return m.showStatus("Nothing to undo")
+	}
+	entry := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+
+	m = m.silence()
+	m.timer = entry.timer
+	m.state = entry.state
+	m.presetIdx = entry.presetIdx
+	m.customDuration = entry.customDuration
+	m.brewTea = entry.brewTea
+	m.step = entry.step
+	m.laps = entry.laps
+	m.alarmAt = entry.alarmAt
+	m.infusion = entry.infusion
+
+	var tickCmd tea.Cmd
+	if m.state == StateBrewing {
+		m, tickCmd = m.startTicking()
+	} else {
+		m = m.stopTicking()
+	}
+	m, statusCmd := m.showStatus("Undid " + entry.action)
+	return m, tea.Batch(tickCmd, statusCmd)
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/gen2brain/beeep"
 )
 
 // Update implements the Bubbletea update function for the Go Brew application.
@@ -13,117 +15,456 @@ import (
 // This function follows the MVU pattern by returning the updated model and
 // any commands that should be executed as side effects.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m, cmd = m.syncTitle(cmd)
+	return m.syncOverlay(cmd)
+}
+
+// update handles a single message for Update.
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
-		// Handle spacebar for pause/resume functionality
-		// We check both KeyType and string representation for maximum compatibility
-		if msg.Type == tea.KeySpace {
-			if m.state == StateBrewing {
-				// Pause the timer but keep the current time
-				m.state = StatePaused
-				return m, nil
-			} else if m.state == StatePaused {
-				// Resume brewing from the paused state
-				m.state = StateBrewing
-				return m, tick()
-			}
+		// The key that ends the screensaver does nothing else
+		asleep := m.asleep()
+		// Keep the "ready at" preview exact for whatever the key changes
+		m.clock = now()
+		m = m.wake(m.clock)
+		if asleep {
+			return m, nil
+		}
+
+		// Suspending works everywhere, as in any terminal program
+		if msg.Type == tea.KeyCtrlZ && canSuspend && !m.config.kioskLocked(KeySuspend) {
+			return m.suspend(now())
 		}
 
-		keyStr := msg.String()
+		// An open text input takes every key until it is submitted or cancelled
+		if m.inputKind != inputNone {
+			return m.updateInput(msg)
+		}
+		// The catalog screen's search line takes all typing
+		if m.screen == screenCatalog {
+			return m.updateCatalog(msg)
+		}
+
+		keyStr := keyName(msg)
 		// Debug: uncomment to see what keys are being pressed
 		// log.Printf("Key pressed: %s (Type: %d)", keyStr, msg.Type)
 
+		// A kiosk ignores the keys guests mustn't use
+		if m.config.kioskLocked(keyStr) {
+			return m, nil
+		}
+		// The calculator screen takes digits for the cup size
+		if m.screen == screenCalc {
+			if m, cmd, used := m.updateCalc(keyStr); used {
+				return m, cmd
+			}
+		}
+
+		// Any key other than quit cancels a pending quit confirmation
+		quitArmed := m.quitArmed
+		m.quitArmed = false
+		startArmed := m.startArmed
+		m.startArmed = false
+
+		keys := m.config.Keys
+
+		// Digits typed while idle compose a duration when digit entry is on
+		if m.digitEntry() {
+			var cmd tea.Cmd
+			var used bool
+			if m, cmd, used = m.updateDigits(keyStr); used {
+				return m, cmd
+			}
+		}
+
+		// The stats screen only answers to the keys that leave it
+		if m.screen == screenStats {
+			switch keyStr {
+			case keys.Stats, "esc":
+				return m.toggleStats()
+			case KeyHistorySearch:
+				return m.openHistorySearch()
+			case keys.Quit, KeyQuitAlt:
+			default:
+				return m, nil
+			}
+		}
+
 		switch keyStr {
-		case KeyQuit, KeyQuitAlt:
-			return m, tea.Quit
-		case KeyStart:
-			// Start timer if not already brewing
+		case keys.Quit, KeyQuitAlt:
+			// Leaving a brew in progress takes a second press, so a stray
+			// keystroke cannot abandon it
+			if m.config.ConfirmQuit && (m.isBrewing() || m.isPaused()) && !quitArmed {
+				m.quitArmed = true
+				return m.showStatus(fmt.Sprintf("Brew in progress: press %s again to quit", keyStr))
+			}
+			return m.silence(), tea.Quit
+		case keys.Start:
+			// Start timer if not already brewing; custom brews are named
+			// first so the history does not file them under a preset
 			if m.state != StateBrewing {
-				// If previously finished, reset to idle before starting fresh
-				if m.isFinished() {
-					if m.config.CustomDuration {
-						m.timer = m.config.BrewTime  // Use custom duration
-					} else {
-						m.timer = m.currentPreset().Duration  // Use preset duration
-					}
-					m.state = StateIdle
+				m = m.clearFinishedNote()
+				if m.stopwatch {
+					return m.startStopwatch()
+				}
+				if !m.alarmAt.IsZero() {
+					return m.startAlarm()
 				}
-				// Set timer to custom duration or preset duration and start brewing
-				if m.config.CustomDuration {
-					m.timer = m.config.BrewTime  // Use custom duration
-				} else {
-					m.timer = m.currentPreset().Duration  // Use preset duration
+				if _, ok := m.currentStep(); !ok && m.customBrew() {
+					if m.config.ConfirmDuration && !startArmed {
+						if status, odd := m.oddDuration(); odd {
+							m.startArmed = true
+							return m.showStatus(fmt.Sprintf("%s: press %s again to brew anyway", status, keyStr))
+						}
+					}
+					m, cmd := m.openInput(inputBrewName, "Tea: ", customTeaName)
+					m.input.SetValue(m.brewName)
+					m.input.CursorEnd()
+					return m, cmd
 				}
-				m.state = StateBrewing
-				return m, tick() // Start the timer tick mechanism
+				return m.start()
 			}
-		case KeyPause:
-			// Dedicated pause key (in addition to spacebar)
+		case "enter":
+			// One key from launch to brewing, when behavior.enter_starts
+			// names the tea
+			if m.config.EnterStarts != "" && m.presetsSelectable() && (m.state == StateIdle || m.state == StateFinished) {
+				return m.startEnterPreset()
+			}
+		case keys.Pause:
+			// Pause a running brew or resume a paused one
 			if m.state == StateBrewing {
-				m.state = StatePaused
-				return m, nil
+				m = m.pause()
+				return m, tea.Batch(m.pauseReminderCmd(), m.brewEventCmd(now(), BrewPaused))
 			} else if m.state == StatePaused {
-				m.state = StateBrewing
-				return m, tick()
+				m, cmd := m.resume()
+				return m, tea.Batch(cmd, m.brewEventCmd(now(), BrewResumed))
 			}
-		case KeyReset:
+		case keys.Reset:
 			// Reset timer to initial state with custom duration or preset duration
-			if m.config.CustomDuration {
-				m.timer = m.config.BrewTime  // Use custom duration
-			} else {
-				m.timer = m.currentPreset().Duration  // Use preset duration
+			if m.state != StateIdle {
+				m = m.saveUndo("reset")
 			}
-			m.state = StateIdle
-			return m, nil
-		case KeyUp:
-			// Navigate to previous preset (only allowed when idle)
+			// Resetting a brew in progress gives up on it
+			var abandoned tea.Cmd
+			if m.isBrewing() || m.isPaused() {
+				abandoned = m.brewEventCmd(now(), BrewAbandoned)
+			}
+			// A reset while idle starts the infusions over; after a brew it
+			// readies the next infusion
 			if m.state == StateIdle {
+				m.infusion = 0
+			}
+			m = m.silence()
+			m = m.clearFinishedNote()
+			m.notifyFailed, m.soundFailed = false, false
+			m.step = 0 // A sequence starts over from its first step
+			m.timer = m.brewDuration()
+			if m.stopwatch {
+				m.timer = 0
+				m.laps = nil
+			}
+			m.state = StateIdle
+			m = m.stopCooling()
+			return m.stopTicking(), abandoned
+		case keys.Up:
+			// Navigate to previous preset (only allowed when idle and not
+			// running a sequence or the stopwatch)
+			if m.state == StateIdle && m.presetsSelectable() {
+				m = m.saveUndo("preset change")
 				// Use modulo arithmetic to wrap around the preset list
 				m.presetIdx = (m.presetIdx - 1 + len(m.config.Presets)) % len(m.config.Presets)
-				// Only update timer if NOT using custom duration
-				if !m.config.CustomDuration {
-					m.timer = m.currentPreset().Duration
-				}
+				m.infusion = 0
+				m = m.clearCustom()
+				// Only changes the timer if NOT using custom duration
+				m.timer = m.brewDuration()
 			}
 			return m, nil
-		case KeyDown:
-			// Navigate to next preset (only allowed when idle)
-			if m.state == StateIdle {
+		case keys.Down:
+			// Navigate to next preset (only allowed when idle and not
+			// running a sequence or the stopwatch)
+			if m.state == StateIdle && m.presetsSelectable() {
+				m = m.saveUndo("preset change")
 				m.presetIdx = (m.presetIdx + 1) % len(m.config.Presets)
-				// Only update timer if NOT using custom duration
-				if !m.config.CustomDuration {
-					m.timer = m.currentPreset().Duration
+				m.infusion = 0
+				m = m.clearCustom()
+				// Only changes the timer if NOT using custom duration
+				m.timer = m.brewDuration()
+			}
+			return m, nil
+		case keys.Duration:
+			// Type in a duration for the next brew; a blind taste test
+			// brews each tea for its own time. A running brew keeps the
+			// length it started with.
+			if (m.state == StateIdle || m.isFinished()) && !m.blindTest() {
+				return m.openInput(inputDuration, "Duration: ", "2m45s or 14:45")
+			}
+		case keys.Copy:
+			if m.state == StateIdle && m.presetsSelectable() {
+				return m.duplicatePreset()
+			}
+		case keys.Edit:
+			if m.state == StateIdle && m.presetsSelectable() {
+				return m.editPresetDuration()
+			}
+		case keys.Longer, keys.Shorter:
+			// Adjust the next brew like a typed duration (only allowed when
+			// idle and not running a sequence or the stopwatch)
+			if m.state == StateIdle && m.presetsSelectable() {
+				dir := 1
+				if keyStr == keys.Shorter {
+					dir = -1
 				}
+				return m.adjustDuration(keyStr, dir), nil
+			}
+		case keys.Stopwatch:
+			// Switch modes only when no timer is running
+			if !m.isBrewing() && !m.isPaused() {
+				return m.toggleStopwatch()
+			}
+		case keys.Lap:
+			return m.lap(), nil
+		case keys.Note:
+			// Notes describe a tea, which the stopwatch doesn't time
+			if !m.stopwatch {
+				m, cmd := m.openInput(inputNote, "Note: ", "second flush from the new tin")
+				m.input.CharLimit = maxNoteLength
+				m.input.Width = 40
+				m.input.SetValue(m.note)
+				m.input.CursorEnd()
+				return m, cmd
 			}
+		case keys.Stats:
+			return m.toggleStats()
+		case keys.Catalog:
+			return m.openCatalog()
+		case keys.Calc:
+			return m.toggleCalc()
+		case keys.Collect:
+			return m.collect()
+		case keys.Vessel:
+			return m.nextVessel()
+		case keys.Cool:
+			// Time the water cooling off the boil before a brew
+			if m.state != StateBrewing && m.state != StatePaused && !m.stopwatch {
+				return m.toggleCooling()
+			}
+			return m, nil
+		case keys.Info:
+			m.showInfo = !m.showInfo
 			return m, nil
+		case keys.Rate:
+			// Only brews saved to the history can be rated, apart from
+			// blind ones, which are revealed by rating them
+			if m.isFinished() && (!m.finishedAt.IsZero() || m.blindTea != "") {
+				return m.openRating()
+			}
+		case keys.Suggest:
+			return m.acceptSuggestion()
+		case keys.Undo:
+			// Undo the last reset or preset change; a running brew is
+			// never abandoned by undo
+			if m.state == StateIdle || m.state == StateFinished {
+				return m.undoLast()
+			}
+		default:
+			// Number keys pick a preset directly (when not brewing)
+			if idx, ok := presetIndex(keyStr); ok && idx < len(m.config.Presets) &&
+				m.presetsSelectable() && (m.state == StateIdle || m.state == StateFinished) {
+				return m.quickSelect(idx)
+			}
 		}
 
 	case tickMsg:
-		// Handle timer tick events - only process if actively brewing
-		if m.state == StateBrewing {
-			m.timer -= time.Second
+		// Handle timer tick events - only process ticks from the active chain
+		// while brewing; anything else is a leftover from a paused or reset
+		// brew and is dropped without scheduling another tick
+		if m.state == StateBrewing && msg.id == m.tickID {
+			// The time is read from the deadline rather than counted tick
+			// by tick, so late ticks never make the brew run long. The
+			// stopwatch counts up and never finishes on its own.
+			if m.stopwatch {
+				m.timer = max(0, msg.time.Sub(m.deadline))
+				return m, tick(m.tickID, m.tickStep())
+			}
+			before := m.brewCue()
+			m.timer = max(0, m.deadline.Sub(msg.time))
 			if m.timer <= 0 {
 				// Timer completed - transition to finished state
+				rec := brewRecord{
+					ID:       m.brewUUID,
+					Time:     msg.time,
+					Tea:      m.brewTea,
+					Duration: Duration{m.brewDuration()},
+					Custom:   m.customBrew(),
+					Note:     m.note,
+				}
+				finished := m.brewEventCmd(msg.time, BrewFinished)
+				// Presets with steeps move on to their next infusion
+				if _, ok := m.steepDuration(); ok {
+					rec.Infusion = m.infusion + 1
+					m.infusion++
+				}
+				body := fmt.Sprintf("Your %s is ready after %s", rec.Tea, m.config.TimeFormat.Format(rec.Duration.Duration))
+				// A blind brew is logged as the tea it really was
+				if m.blindTea != "" {
+					rec.Tea, rec.Blind = m.blindTea, true
+				}
+				record := tea.Batch(recordBrewCmd(m.config.HistoryFile, m.config.HistoryKey, rec, m.config.HistoryLimits), m.usageCmd(msg.time))
+				m.finishedAt = time.Time{}
+				// Preparing a recipe's cup is not a brew of its own
+				if m.prepStep() {
+					body = rec.Tea + " done"
+					record = nil
+				} else if m.config.HistoryFile != "" {
+					// The brew can be rated while it is on screen
+					m.finishedAt = rec.Time
+					m.history = append(slices.Clip(m.history), rec)
+				}
 				m.timer = 0
 				m.state = StateFinished
-				// Launch asynchronous notifications and sounds
-				return m, tea.Cmd(func() tea.Msg {
-					go func() {
-						// Send desktop notification if enabled
-						if m.config.NotifyEnabled {
-							if err := beeep.Notify("Go Brew Timer", "Your tea is ready!", ""); err != nil {
-								log.Printf("Failed to send notification: %v", err)
-							}
-						}
-						// Play alert sound (includes fallback mechanisms)
-						playSound()
-					}()
-					return nil
-				})
+				m = m.stopTicking()
+				// The finished tea stays on screen for the full idle time
+				m = m.wake(msg.time)
+				// A sequence moves straight on to its next step
+				var next tea.Cmd
+				if m.step+1 < len(m.config.Sequence) {
+					m.step++
+					m, next = m.start()
+				}
+				// The alert can be cut short by reset, a new brew or quitting
+				ctx, cancel := context.WithCancel(context.Background())
+				m.stopAlert = cancel
+				// Launch asynchronous notifications and sounds and log the brew
+				var exit, wait tea.Cmd
+				if m.isFinished() {
+					exit = m.exitAfterFinish()
+					m, wait = m.waitForCollection(msg.time)
+				}
+				return m, tea.Batch(alertCmd(ctx, m.config, body), record, finished, next, exit, wait)
 			}
-			// Continue ticking if not finished
-			return m, tick()
+			// Continue ticking if not finished, marking a cue just reached
+			m, cue := m.cueAlertCmd(before)
+			return m, tea.Batch(tick(m.tickID, m.tickStep()), cue)
+		}
+
+	case coolMsg:
+		// Only the running cool-down keeps refreshing its panel
+		if msg.id == m.coolID && !m.coolStart.IsZero() {
+			m.coolNow = msg.time
+			return m, coolTick(m.coolID)
+		}
+
+	case historyMsg:
+		m.history = msg
+
+	case historyStatsMsg:
+		stats := historyStats(msg)
+		m.summary = &stats
+
+	case clockMsg:
+		m.clock = time.Time(msg)
+		return m, clockTick()
+
+	case startMsg:
+		if m.state == StateIdle {
+			if !m.alarmAt.IsZero() {
+				return m.startAlarm()
+			}
+			return m.start()
+		}
+
+	case tea.FocusMsg:
+		m.unfocused = false
+
+	case tea.BlurMsg:
+		m.unfocused = true
+
+	case tea.ResumeMsg, continuedMsg:
+		return m.resumed(now())
+
+	case simulateFinishMsg:
+		return m.simulateFinish(msg)
+
+	case pauseReminderMsg:
+		// Only a brew still in the pause this reminder was set for
+		if m.isPaused() && msg.id == m.tickID {
+			return m, m.stateAlertCmd(EventPause, fmt.Sprintf("Your %s has been paused for %v", m.brewTea, pauseReminderAfter))
+		}
+
+	case remindMsg:
+		return m.remind(msg.id)
+
+	case coldMsg:
+		return m.goneCold(msg.id)
+
+	case collectMsg:
+		return m.collect()
+
+	case autoExitMsg:
+		// Quit unless a new brew was started since this one finished
+		if m.isFinished() && msg.id == m.tickID {
+			return m.silence(), tea.Quit
+		}
+
+	case notifyResultMsg:
+		// Keep the failure visible on the finished screen and explain it
+		// briefly in the status line
+		if msg.err != nil {
+			log.Printf("Failed to send notification: %v", msg.err)
+			m.notifyFailed = true
+			return m.showStatus("Notification failed: " + msg.err.Error())
+		}
+
+	case soundResultMsg:
+		if msg.err != nil {
+			log.Printf("Failed to play alert sound: %v", msg.err)
+			m.soundFailed = true
+			return m.showStatus("Sound failed: " + msg.err.Error())
+		}
+
+	case statusMsg:
+		return m.showStatus(string(msg))
+
+	case scaleMsg:
+		m.leafGrams = msg.grams
+		return m, nil
+
+	case hookFlushMsg:
+		// Undelivered brew events stay queued and are tried again later
+		if msg.err == nil {
+			return m, nil
+		}
+		log.Printf("Delivering brew events failed, %d queued: %v", msg.left, msg.err)
+		m, retry := m.hookRetryCmd()
+		if msg.background {
+			return m, retry
+		}
+		m, status := m.showStatus(m.icon("⚠", "Error:") + fmt.Sprintf("Brew event queued, retrying in %v: %v", hookRetryInterval, msg.err))
+		return m, tea.Batch(status, retry)
+
+	case overlayErrMsg:
+		// One failure is enough to hear about, not one a second
+		m.overlayFailed = true
+		log.Printf("Error: %v", msg.err)
+		return m.showStatus(m.icon("⚠", "Error:") + "writing the overlay: " + msg.err.Error())
+
+	case hookRetryMsg:
+		m.hookRetrying = false
+		return m, m.flushHooksCmd()
+
+	case errMsg:
+		log.Printf("Error: %v", msg.err)
+		return m.showStatus(m.icon("⚠", "Error:") + msg.err.Error())
+
+	case statusClearMsg:
+		// Only clear the line this timeout was scheduled for
+		if msg.id == m.statusID {
+			m.status = ""
+			m.quitArmed = false
+			m.startArmed = false
 		}
 
 	case tea.WindowSizeMsg:
@@ -135,11 +476,184 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// tick creates a Bubbletea command that generates timer tick messages at one-second intervals.
-// This is the core timing mechanism for the application, driving the countdown timer.
-// The command continues running until explicitly cancelled by stopping timer operations.
-func tick() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// keyName returns the name of a key press as used in key bindings. It matches
+// msg.String() except for the spacebar, which Bubbletea reports as " " but
+// is bound by the more readable name "space".
+func keyName(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		return KeyPause
+	}
+	return msg.String()
+}
+
+// tickInterval is how long a tick waits for each second until the next
+// redraw. Integration tests shorten it so ticks arrive within milliseconds;
+// as their clock is fixed, a brew then finishes on its first tick.
+var tickInterval = time.Second
+
+// tickStep returns how long until the next tick redraws the timer: when it
+// next reaches a whole display.tick_interval, or the end of a countdown, so
+// ticks stay in step with the deadline and a brew never runs over its time
+// waiting for a long tick.
+func (m model) tickStep() time.Duration {
+	step := m.config.TickInterval
+	if step <= 0 {
+		step = DefaultTickInterval
+	}
+	if m.stopwatch {
+		return step - m.timer%step
+	}
+	if left := m.timer % step; left > 0 {
+		return left
+	}
+	return step
+}
+
+// start begins a fresh brew with the custom duration or the selected
+// preset's duration, stopping any alert still playing from the last one.
+func (m model) start() (model, tea.Cmd) {
+	m = m.silence()
+	m.notifyFailed, m.soundFailed = false, false
+	if m.blindTest() && !m.stopwatch {
+		m = m.pickBlind()
+	}
+	m.timer = m.brewDuration()
+	m.state = StateBrewing
+	m.undo = nil // Earlier selections belong to the previous brew
+	// The water has been poured, so the cool-down is over
+	m = m.stopCooling()
+	m.brewTea = m.currentPreset().Name
+	if step, ok := m.currentStep(); ok {
+		m.brewTea = step.Label
+		if step.Tea != "" {
+			m.brewTea = step.Tea
+		}
+	} else if !m.alarmAt.IsZero() {
+		m.brewTea = "Alarm " + m.alarmClock()
+	} else if m.customBrew() {
+		m.brewTea = m.brewName
+		if m.brewTea == "" {
+			m.brewTea = customTeaName
+		}
+	} else if m.blindTea != "" {
+		m.brewTea = blindTeaName
+	}
+	m.brewUUID = newUUID()
+	m, cmd := m.startTicking() // Start the timer tick mechanism
+	cmd = tea.Batch(cmd, m.brewEventCmd(now(), BrewCreated, BrewStarted))
+	if m.config.routedAny(EventStart) {
+		cmd = tea.Batch(cmd, m.stateAlertCmd(EventStart, fmt.Sprintf("Brewing %s, ready at %s", m.brewTea, m.config.Formats.clock(m.deadline))))
+	}
+	return m, cmd
+}
+
+// quickSelect selects the preset at idx, as picked with a number key, and
+// starts brewing it right away when quick start is enabled.
+func (m model) quickSelect(idx int) (model, tea.Cmd) {
+	m = m.saveUndo("preset change")
+	m = m.silence()
+	m = m.clearFinishedNote()
+	m.presetIdx = idx
+	m.infusion = 0
+	m = m.clearCustom()
+	m.timer = m.brewDuration()
+	m.state = StateIdle
+	if m.config.QuickStart {
+		return m.start()
+	}
+	return m, nil
+}
+
+// clearFinishedNote drops the note of a finished brew, which was saved with
+// it, when the user moves on to the next one. A note typed before or during
+// a brew stays until that brew has finished.
+func (m model) clearFinishedNote() model {
+	if m.isFinished() {
+		m.note = ""
+	}
+	return m
+}
+
+// autoExitMsg quits the program after a finished brew when -exit-on-finish
+// is set. It carries the tick chain ID at the time the brew finished, so it
+// is ignored if another brew has been started since.
+type autoExitMsg struct {
+	id int
+}
+
+// exitAfterFinish returns the command that quits after the configured
+// -exit-on-finish delay, or nil when the timer should stay open.
+func (m model) exitAfterFinish() tea.Cmd {
+	if m.config.ExitOnFinish <= 0 {
+		return nil
+	}
+	id := m.tickID
+	return tea.Tick(m.config.ExitOnFinish, func(time.Time) tea.Msg {
+		return autoExitMsg{id: id}
+	})
+}
+
+// presetIndex converts a number key "1" to "9" into a preset index.
+func presetIndex(key string) (int, bool) {
+	if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		return int(key[0] - '1'), true
+	}
+	return 0, false
+}
+
+// pause stops the countdown while keeping the remaining time. The active tick
+// chain is retired so no further ticks are scheduled until the brew resumes.
+func (m model) pause() model {
+	// The time passed since the last tick counts too
+	if m.stopwatch {
+		m.timer = max(0, now().Sub(m.deadline))
+	} else {
+		m.timer = max(0, m.deadline.Sub(now()))
+	}
+	m.state = StatePaused
+	return m.stopTicking()
+}
+
+// resume continues a paused brew from its remaining time.
+func (m model) resume() (model, tea.Cmd) {
+	m.state = StateBrewing
+	m, cmd := m.startTicking()
+	if m.config.routedAny(EventResume) {
+		cmd = tea.Batch(cmd, m.stateAlertCmd(EventResume, fmt.Sprintf("Resumed %s, ready at %s", m.brewTea, m.config.Formats.clock(m.deadline))))
+	}
+	return m, cmd
+}
+
+// startTicking begins a new tick chain, sets the deadline the brew should
+// finish at, and returns the command for its first tick. Any tick still in
+// flight from an earlier chain becomes stale, so a quick pause/resume can
+// never leave two chains counting down at once. The stopwatch's deadline is
+// the time it would have read zero, which it counts up from.
+func (m model) startTicking() (model, tea.Cmd) {
+	m.deadline = now().Add(m.timer)
+	if m.stopwatch {
+		m.deadline = now().Add(-m.timer)
+	}
+	m.tickID++
+	return m, tea.Batch(tick(m.tickID, m.tickStep()), m.simulateFinishCmd())
+}
+
+// stopTicking retires the active tick chain. The tick already in flight is
+// ignored when it arrives, so idle, paused and finished timers schedule no
+// further Tick commands and cost no CPU while waiting.
+func (m model) stopTicking() model {
+	m.tickID++
+	return m
+}
+
+// tick creates a Bubbletea command that generates a timer tick message for the
+// given tick chain once step of brew time has passed. This is the core timing
+// mechanism for the application, driving the countdown timer; each handled
+// tick reads the time left from the deadline and schedules the next one for
+// as long as its chain stays active.
+func tick(id int, step time.Duration) tea.Cmd {
+	delay := time.Duration(int64(tickInterval) * int64(step) / int64(time.Second))
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
+		return tickMsg{id: id, time: t}
 	})
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// vesselRounding is what durations nudged for a vessel are rounded to, so
+// the timer doesn't suggest a precision no tea has.
+const vesselRounding = 5 * time.Second
+
+// Vessel is a cup or pot to brew in.
+type Vessel struct {
+	Name      string  // Name shown in the calculator, e.g. "Mug"
+	Ml        int     // Water it holds in millilitres
+	Retention float64 // How well it keeps the water hot, relative to a ceramic mug's 1
+}
+
+// DefaultVessels are the vessels listed when the config file names none.
+// A thin porcelain gaiwan loses heat quickly; a big pot with its lid on
+// keeps the water hotter for longer.
+var DefaultVessels = []Vessel{
+	{"Gaiwan", 120, 0.9},
+	{"Cup", 250, 1},
+	{"Mug", 350, 1},
+	{"Teapot", 750, 1.15},
+}
+
+// vesselByName returns the index of the vessel called name, regardless of
+// case, or -1 if there is none.
+func vesselByName(vessels []Vessel, name string) int {
+	for i, v := range vessels {
+		if strings.EqualFold(v.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkVessel reports a behavior.vessel value that names no vessel.
+func (c *Config) checkVessel() error {
+	if c.Vessel != "" && vesselByName(c.Vessels, c.Vessel) < 0 {
+		return fmt.Errorf("behavior.vessel: there is no vessel called %q", c.Vessel)
+	}
+	return nil
+}
+
+// selectedVessel returns the vessel picked for the next brew, or false when
+// none is.
+func (m model) selectedVessel() (Vessel, bool) {
+	if i := vesselByName(m.config.Vessels, m.vessel); i >= 0 {
+		return m.config.Vessels[i], true
+	}
+	return Vessel{}, false
+}
+
+// vesselTime returns a preset's time d adjusted for the selected vessel
+// when behavior.vessel_timing is on: water that stays hotter extracts
+// faster, so a vessel that retains more heat than a mug brews for less
+// time, and one that retains less brews for longer.
+func (m model) vesselTime(d time.Duration) time.Duration {
+	v, ok := m.selectedVessel()
+	if !ok || !m.config.VesselTiming || v.Retention <= 0 || v.Retention == 1 {
+		return d
+	}
+	return max(vesselRounding, time.Duration(float64(d)/v.Retention).Round(vesselRounding))
+}
+
+// nextVessel selects the next vessel, going back to none after the last
+// one. The brew time follows the vessel when behavior.vessel_timing is on.
+func (m model) nextVessel() (model, tea.Cmd) {
+	if m.state != StateIdle || m.stopwatch || len(m.config.Vessels) == 0 {
+		return m, nil
+	}
+	next := vesselByName(m.config.Vessels, m.vessel) + 1
+	m.vessel = ""
+	if next < len(m.config.Vessels) {
+		m.vessel = m.config.Vessels[next].Name
+	}
+	m.timer = m.brewDuration()
+	if m.vessel == "" {
+		return m.showStatus("No vessel")
+	}
+	return m.showStatus("Brewing in the " + m.vessel)
+}
+
+// vesselLabel describes the selected vessel under the preset line: its
+// size, the leaf it takes at the preset's ratio and whether the brew time
+// was adjusted for it. It returns "" when no vessel is selected.
+func (m model) vesselLabel() string {
+	v, ok := m.selectedVessel()
+	if !ok {
+		return ""
+	}
+	label := fmt.Sprintf("%s%s, %dml", m.icon("🫖", "Vessel:"), v.Name, v.Ml)
+	if grams, ok := leafFor(m.currentPreset().Info.Ratio, v.Ml); ok {
+		label += fmt.Sprintf(" · %.1fg of leaf", grams)
+	}
+	if !m.customBrew() {
+		if d := m.presetTime(); m.vesselTime(d) != d {
+			label += fmt.Sprintf(" · %s instead of %s for its heat", m.config.TimeFormat.Format(m.vesselTime(d)), m.config.TimeFormat.Format(d))
+		}
+	}
+	return label
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVesselTime(t *testing.T) {
+	config := NewConfig()
+	config.VesselTiming = true
+	m := initialModel(config)
+	m.presetIdx = 1 // Green Tea, 2m
+
+	if d := m.brewDuration(); d != 2*time.Minute {
+		t.Errorf("Expected no change without a vessel, got %v", d)
+	}
+	for _, tt := range []struct {
+		vessel string
+		want   time.Duration
+	}{
+		{"Teapot", 105 * time.Second},
+		{"Gaiwan", 135 * time.Second},
+		{"Mug", 2 * time.Minute},
+	} {
+		m.vessel = tt.vessel
+		if d := m.brewDuration(); d != tt.want {
+			t.Errorf("Expected %v in the %s, got %v", tt.want, tt.vessel, d)
+		}
+	}
+
+	m.vessel = "Teapot"
+	m.config.VesselTiming = false
+	if d := m.brewDuration(); d != 2*time.Minute {
+		t.Errorf("Expected the vessel to leave the time alone without vessel_timing, got %v", d)
+	}
+}
+
+func TestSelectVessel(t *testing.T) {
+	config := NewConfig()
+	config.VesselTiming = true
+	m := initialModel(config)
+	m.presetIdx = 1 // Green Tea, 2g per 250ml
+	m.timer = m.brewDuration()
+	m.width, m.height = 80, 24
+	press := func() {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyVessel)})
+		m = newModel.(model)
+	}
+
+	press()
+	if m.vessel != "Gaiwan" || m.timer != 135*time.Second {
+		t.Errorf("Expected the gaiwan with a longer brew, got %q and %v", m.vessel, m.timer)
+	}
+	press()
+	press()
+	press()
+	if m.vessel != "Teapot" || m.timer != 105*time.Second {
+		t.Errorf("Expected the teapot with a shorter brew, got %q and %v", m.vessel, m.timer)
+	}
+	if got := m.vesselLabel(); !strings.HasPrefix(got, "🫖 Teapot, 750ml · 6.0g of leaf · ") {
+		t.Errorf("Unexpected label %q", got)
+	}
+	if !strings.Contains(m.View(), "6.0g of leaf") {
+		t.Error("Expected the vessel's leaf amount in the view")
+	}
+	press()
+	if m.vessel != "" || m.timer != 2*time.Minute || m.vesselLabel() != "" {
+		t.Errorf("Expected to go back to no vessel, got %q and %v", m.vessel, m.timer)
+	}
+
+	m.state = StateBrewing
+	press()
+	if m.vessel != "" {
+		t.Error("Expected the vessel to stay put during a brew")
+	}
+}
+
+func TestVesselConfig(t *testing.T) {
+	config := NewConfig()
+	config.ConfigPath = writeConfig(t, "[behavior]\nvessel = \"kyusu\"\nvessel_timing = true\n\n[[presets]]\nname = \"Sencha\"\nduration = \"1m\"\n\n[[vessels]]\nname = \"Kyusu\"\nml = 180\nretention = 0.8\n")
+	if err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if m := initialModel(config); m.vessel != "kyusu" || m.timer != 75*time.Second {
+		t.Errorf("Expected to start with the kyusu and a longer brew, got %q and %v", m.vessel, m.timer)
+	}
+
+	config.Vessel = "Cauldron"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), `no vessel called "Cauldron"`) {
+		t.Errorf("Expected an unknown vessel to be rejected, got %v", err)
+	}
+}
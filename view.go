@@ -2,83 +2,490 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// viewKey captures every piece of model state that affects the rendered UI.
+// Two models with equal keys render identical output.
+type viewKey struct {
+	seconds   int           // Displayed whole seconds of the countdown
+	barTime   time.Duration // Exact time shown with sub-second ticks, which move the progress bar
+	total     time.Duration // Brew length used for the progress bar
+	state     TimerState    // Current timer state
+	presetIdx int           // Selected tea preset
+	width     int           // Terminal width
+	height    int           // Terminal height
+	notifyErr bool          // Whether a notification failure is shown
+	soundErr  bool          // Whether a sound failure is shown
+	status    string        // Transient status line
+	input     string        // Rendered text input, empty when closed
+	step      int           // Current step of a -sequence
+	stopwatch bool          // Whether the stopwatch is shown
+	laps      int           // Number of stopwatch laps recorded
+	alarmAt   time.Time     // Alarm time shown, zero if none
+	readyAt   string        // Expected finish time shown next to the countdown
+	note      string        // Note on the current brew
+	digits    string        // Digits typed for a direct duration entry
+	infusion  string        // Current infusion of a preset with steeps
+	screen    screen        // Screen shown
+	history   int           // Number of brew log records charted
+	summary   *historyStats // Stats of the brew log from its store
+	filter    string        // Filter applied to the charted records
+	cue       cue           // Countdown cue reached, for the accent color
+	cooling   string        // Cool-down estimate shown
+	search    string        // Rendered catalog search line
+	catalog   int           // Highlighted catalog match
+	calc      string        // Tea and cup size on the calculator screen
+	showInfo  bool          // Whether the preset info panel is shown
+	asleep    string        // Clock shown by the screensaver, empty when awake
+	unfocused bool          // Whether the terminal window is unfocused, dimming the view
+	quiet     bool          // Whether a finished brew's sound was held back for quiet hours
+	suggest   string        // Duration suggested by the ratings
+	rating    string        // Rating of the finished brew, or how to give one
+	scale     string        // Leaf weight on the scale and the water it needs
+	vessel    string        // Selected vessel and the leaf it takes
+	collect   string        // How long the finished brew has waited to be collected
+}
+
+// viewCache remembers the last rendered frame and the state it was rendered
+// from. It is shared by pointer between model copies so Bubbletea's repeated
+// View calls can reuse the frame until something visible changes.
+type viewCache struct {
+	key    viewKey
+	output string
+	valid  bool
+}
+
+// viewKey returns the render key for the current model state.
+func (m model) viewKey() viewKey {
+	return viewKey{
+		seconds:   displaySeconds(m.shownTimer()),
+		barTime:   m.barTime(),
+		total:     m.brewDuration(),
+		state:     m.state,
+		presetIdx: m.presetIdx,
+		width:     m.width,
+		height:    m.height,
+		notifyErr: m.notifyFailed,
+		soundErr:  m.soundFailed,
+		status:    m.status,
+		input:     m.inputView(),
+		step:      m.step,
+		stopwatch: m.stopwatch,
+		laps:      len(m.laps),
+		alarmAt:   m.alarmAt,
+		readyAt:   m.readyAtLabel(),
+		note:      m.note,
+		digits:    m.digits,
+		infusion:  m.infusionLabel(),
+		screen:    m.screen,
+		history:   len(m.history),
+		summary:   m.summary,
+		filter:    m.historyQuery,
+		cue:       m.brewCue(),
+		cooling:   m.coolingLabel(),
+		search:    m.search.View(),
+		catalog:   m.catalogIdx,
+		calc:      fmt.Sprint(m.calcPreset, " ", m.calcMl),
+		showInfo:  m.showInfo,
+		asleep:    m.screensaverLabel(),
+		unfocused: m.unfocused,
+		quiet:     m.quietFinish(),
+		suggest:   m.suggestionLabel(),
+		rating:    m.ratingLabel(),
+		scale:     m.scaleLabel(),
+		vessel:    m.vesselLabel(),
+		collect:   m.collectLabel(),
+	}
+}
+
+// barTime returns the time shown when ticks are shorter than a second, as
+// the progress bar then moves between the whole seconds shown, and 0
+// otherwise, so frames are only redrawn when the countdown changes.
+func (m model) barTime() time.Duration {
+	if m.config.TickInterval >= time.Second || m.stopwatch {
+		return 0
+	}
+	return m.shownTimer()
+}
+
 // View renders the complete terminal UI for the Go Brew application.
+// Bubbletea calls View after every message, so the frame is only rebuilt when
+// the displayed second, state, selection or terminal size actually changed;
+// otherwise the cached frame is returned without any string building.
+func (m model) View() string {
+	if m.cache == nil {
+		return m.taskbarProgress() + m.render()
+	}
+
+	key := m.viewKey()
+	if m.cache.valid && m.cache.key == key {
+		return m.cache.output
+	}
+
+	m.cache.key = key
+	m.cache.output = m.taskbarProgress() + m.render()
+	m.cache.valid = true
+	return m.cache.output
+}
+
+// render builds the terminal UI for the current model state.
 // It follows the MVU pattern by being a pure function that converts
 // the current model state into a string representation for display.
 // The view includes the timer display, progress bar, preset information,
 // and control hints, all centered in the terminal.
-func (m model) View() string {
-	// Get current tea preset for display information
-	preset := m.currentPreset()
+func (m model) render() string {
+	// Rather than wrap the layout into a garbled mess, ask for more room
+	if m.tooSmall() {
+		return m.renderTooSmall()
+	}
+	// An idle kitchen display dims to a drifting clock
+	if m.asleep() {
+		return m.renderScreensaver()
+	}
+	// The stats screen replaces the timer while it is open
+	if m.screen == screenStats {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderStats())
+	}
+	if m.screen == screenCatalog {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderCatalog())
+	}
+	if m.screen == screenCalc {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.renderCalc())
+	}
+
+	// Each section starts with its own line breaks, so an empty one leaves
+	// no gap; a picture drawn earlier is removed once the preset line is gone
+	var ui strings.Builder
+	if !m.showsPicture() {
+		ui.WriteString(m.clearImages())
+	}
+	m.writeStatus(&ui)
+	m.writeProgress(&ui)
+	m.writePresetList(&ui)
+	m.writePrompt(&ui)
+	m.writeStatusLine(&ui)
+	m.writeControls(&ui)
+
+	// Center the entire UI in the terminal window
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		ui.String(),
+	)
+}
+
+// minWidth and minHeight are the smallest terminal, in cells, the timer
+// view is drawn in.
+const (
+	minWidth  = 40
+	minHeight = 10
+)
+
+// tooSmall reports whether the terminal is smaller than the view needs. A
+// size of zero means none has been reported yet, and is not too small.
+func (m model) tooSmall() bool {
+	return m.width > 0 && m.height > 0 && (m.width < minWidth || m.height < minHeight)
+}
 
-	// Format timer display as MM:SS with leading zeros
-	timeStr := fmt.Sprintf("%02d:%02d", int(m.timer.Minutes()), int(m.timer.Seconds())%60)
+// renderTooSmall asks for a larger terminal. The time stays visible, so a
+// brew can still be followed from a cramped split.
+func (m model) renderTooSmall() string {
+	text := fmt.Sprintf("%s\nPlease enlarge the terminal\n(need %dx%d, have %dx%d)",
+		m.config.TimeFormat.Format(m.shownTimer()), minWidth, minHeight, m.width, m.height)
+	content := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(text)
+	return m.clearImages() + lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+// Styles shared by the sections of the timer view.
+var (
+	// headlineStyle frames the state and countdown line
+	headlineStyle = lipgloss.NewStyle().Bold(true).Padding(1, 2)
+	// detailStyle is for the dimmed lines describing the brew
+	detailStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Faint(true)
+)
+
+// showsPresetLine reports whether the status section describes the
+// selected preset, which it does while idle with nothing else to label.
+func (m model) showsPresetLine() bool {
+	return m.presetsSelectable() && m.state == StateIdle && m.alarmAt.IsZero()
+}
+
+// showsPicture reports whether the preset line starts with a picture of the
+// tea, where the terminal can draw one.
+func (m model) showsPicture() bool {
+	return m.showsPresetLine() && m.presetImage(m.currentPreset().Name) != ""
+}
 
-	// Define reusable styles for consistent UI appearance
-	baseStyle := lipgloss.NewStyle().Bold(true).Padding(1, 2)
-	presetStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Faint(true)
+// headline follows the state label with the time in the configured format
+// and when the tea will be ready. A kiosk shows the time in large digits
+// under the label, to be read from across the room.
+func (m model) headline(label string) string {
+	shown := m.config.TimeFormat.Format(m.shownTimer())
+	var ready string
+	if r := m.readyAtLabel(); r != "" {
+		ready = "   " + r
+	}
+	if m.config.Kiosk {
+		if big, ok := bigText(shown); ok {
+			return lipgloss.NewStyle().Align(lipgloss.Center).Render(label + ready + "\n\n" + big)
+		}
+	}
+	return label + "   " + shown + ready
+}
 
-	// Build comprehensive preset information string
-	presetInfo := fmt.Sprintf("%s (%s)", preset.Name, preset.Temp)
-	if preset.Notes != "" {
-		presetInfo += " - " + preset.Notes
+// writeStatus writes the state and countdown, followed by what describes
+// the brew: the sequence step, alarm or preset, and the cool-down,
+// infusion, note and alert problems that apply.
+func (m model) writeStatus(b *strings.Builder) {
+	// An unfocused window is dimmed, as it only needs a glance
+	headlineStyle := headlineStyle
+	if m.unfocused {
+		headlineStyle = headlineStyle.Faint(true)
 	}
 
-	// Generate status message based on current timer state
-	var status string
+	var color, label string
 	switch {
 	case m.isFinished():
 		// Tea is ready - show completion message with time
-		status = baseStyle.Foreground(lipgloss.Color(ColorReady)).Render("🫖 Tea Ready!   " + timeStr)
+		color, label = m.config.Colors.Ready, m.icon("🫖", "")+"Tea Ready!"
+	case m.stopwatch && m.isBrewing():
+		// Stopwatch running - show elapsed time
+		color, label = m.config.Colors.Brewing, m.icon("⏱", "")+"Stopwatch"
+	case m.stopwatch && m.state == StateIdle:
+		// Stopwatch waiting to start
+		color, label = m.config.Colors.Idle, "Press '"+m.config.Keys.Start+"' to start the stopwatch"
 	case m.isBrewing():
-		// Currently brewing - show active status with time
-		status = baseStyle.Foreground(lipgloss.Color(ColorBrewing)).Render("⏰ Brewing...   " + timeStr)
+		// Currently brewing - the accent changes at the halfway and final
+		// stretch cues
+		color, label = m.brewingColor(), m.icon("⏰", "")+"Brewing..."
 	case m.isPaused():
 		// Timer paused - show paused status with time
-		status = baseStyle.Foreground(lipgloss.Color(ColorPaused)).Render("⏸️ Paused   " + timeStr)
+		color, label = m.config.Colors.Paused, m.icon("⏸️", "")+"Paused"
 	default:
 		// Idle state - show start prompt with time
-		status = baseStyle.Foreground(lipgloss.Color(ColorIdle)).Render("Press 's' to start   " + timeStr)
+		color, label = m.config.Colors.Idle, "Press '"+m.config.Keys.Start+"' to start"
+	}
+	b.WriteString(headlineStyle.Foreground(lipgloss.Color(color)).Render(m.headline(label)))
+
+	// Label the running step of a sequence; otherwise add preset
+	// information when idle to help users choose tea type. The stopwatch
+	// has no tea to describe.
+	if step, ok := m.currentStep(); ok && !m.stopwatch {
+		b.WriteString("\n" + detailStyle.Render(fmt.Sprintf("Step %d/%d: %s", m.step+1, len(m.config.Sequence), step.Label)))
+		if step.Hint != "" {
+			b.WriteString("\n" + detailStyle.Render(m.icon("👉", "Tip:")+step.Hint))
+		}
+	} else if m.blindTest() && !m.stopwatch {
+		b.WriteString("\n" + detailStyle.Render(m.blindLabel()))
+	} else if !m.alarmAt.IsZero() && !m.stopwatch {
+		b.WriteString("\n" + detailStyle.Render(m.icon("⏰", "")+"Alarm at "+m.alarmClock()))
+	} else if m.showsPresetLine() {
+		preset := m.currentPreset()
+		presetInfo := fmt.Sprintf("%s (%s)", preset.Name, m.config.Formats.presetTemp(preset.Temp))
+		if preset.Notes != "" {
+			presetInfo += " - " + preset.Notes
+		}
+		// A picture of the tea replaces the cup emoji where the terminal
+		// can draw one; text labels need neither
+		if picture := m.presetImage(preset.Name); picture != "" {
+			b.WriteString("\n" + picture + detailStyle.Render(presetInfo))
+		} else {
+			b.WriteString("\n" + detailStyle.Render(m.icon("🍵", "")+presetInfo))
+		}
+		if m.showInfo {
+			b.WriteString("\n" + renderPresetInfo(preset.Info))
+		}
+		if label := m.suggestionLabel(); label != "" {
+			b.WriteString("\n" + detailStyle.Render(label))
+		}
+		if label := m.vesselLabel(); label != "" {
+			b.WriteString("\n" + detailStyle.Render(label))
+		}
+		if label := m.scaleLabel(); label != "" {
+			b.WriteString("\n" + detailStyle.Render(label))
+		}
 	}
 
-	// Add preset information when idle to help users choose tea type
-	if m.state == StateIdle {
-		status += "\n" + presetStyle.Render("🍵 "+presetInfo)
+	// Guide the pour while the water cools off the boil
+	if !m.coolStart.IsZero() {
+		b.WriteString("\n" + m.renderCooling(detailStyle))
 	}
 
-	// Generate progress bar for active states (brewing, paused, finished)
-	var progress string
-	if m.isBrewing() || m.isPaused() || m.isFinished() {
-		total := preset.Duration
-		elapsed := total - m.timer
-		progress = "\n" + renderProgressBar(total, elapsed, DefaultProgressBarWidth, m.state)
+	// Count the infusions of a preset with a steep schedule
+	if label := m.infusionLabel(); label != "" && !m.isFinished() {
+		b.WriteString("\n" + detailStyle.Render(label))
 	}
 
-	// Build control help section
-	controls := "\n\nControls:\n"
+	// Show the note on the current brew
+	if m.note != "" && !m.stopwatch {
+		b.WriteString("\n" + detailStyle.Render(m.icon("📝", "Note:")+m.note))
+	}
+
+	// Surface alert failures so a silent finish is not mistaken for a slow brew
+	if m.isFinished() {
+		var problems []string
+		if m.soundFailed {
+			problems = append(problems, m.icon("🔇", "")+"sound failed")
+		}
+		if m.notifyFailed {
+			problems = append(problems, m.icon("🔕", "")+"notification failed")
+		}
+		if len(problems) > 0 {
+			b.WriteString("\n" + detailStyle.Render(strings.Join(problems, "   ")))
+		}
+		if m.quietFinish() {
+			b.WriteString("\n" + detailStyle.Render(m.icon("🌙", "")+"Quiet hours: no sound"))
+		}
+		if label := m.ratingLabel(); label != "" && m.inputKind == inputNone {
+			b.WriteString("\n" + detailStyle.Render(label))
+		}
+		if label := m.collectLabel(); label != "" {
+			b.WriteString("\n" + detailStyle.Render(label))
+		}
+	}
+}
+
+// writeProgress writes the progress bar and strength estimate for active
+// states (brewing, paused, finished). The stopwatch has no end to measure
+// progress against and lists its laps instead.
+func (m model) writeProgress(b *strings.Builder) {
+	if m.stopwatch {
+		if len(m.laps) > 0 {
+			b.WriteString("\n" + m.renderLaps(detailStyle))
+		}
+		return
+	}
+	if !(m.isBrewing() || m.isPaused() || m.isFinished()) {
+		return
+	}
+	total := m.brewDuration()
+	b.WriteString("\n" + renderProgressBar(total, total-m.shownTimer(), progressBarWidth(m.width), m.state))
+	// Estimate how strong the cup is so far, to pull the leaves early for
+	// a lighter one
+	if strength := m.strengthView(); strength != "" {
+		b.WriteString("\n" + detailStyle.Render(strength))
+	}
+}
+
+// writePresetList writes the presets with their number keys when idle.
+func (m model) writePresetList(b *strings.Builder) {
+	if m.presetsSelectable() && m.state == StateIdle {
+		b.WriteString("\n\n" + m.renderPresetList(detailStyle))
+	}
+}
+
+// writePrompt writes the open text input, or the digits typed for a
+// duration, with the keys that submit or cancel it.
+func (m model) writePrompt(b *strings.Builder) {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Colors.Idle))
+	if m.inputKind != inputNone {
+		b.WriteString("\n\n" + m.inputView() + "\n" + hintStyle.Render(m.inputHint()))
+	} else if m.digits != "" {
+		b.WriteString("\n\n" + digitsView(m.digits) + "\n" + hintStyle.Render("enter to apply · esc to cancel"))
+	}
+}
+
+// writeStatusLine writes the transient status line for recent runtime
+// problems and confirmations.
+func (m model) writeStatusLine(b *strings.Builder) {
+	if m.status != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.Colors.Paused))
+		b.WriteString("\n\n" + statusStyle.Render(m.status))
+	}
+}
+
+// writeControls writes the key help and, when idle, what the next brew will
+// be.
+func (m model) writeControls(b *strings.Builder) {
+	b.WriteString("\n\nControls:\n")
 	for _, binding := range m.config.KeyBindings {
-		controls += fmt.Sprintf("%s: %s\n", binding.Key, binding.Desc)
+		fmt.Fprintf(b, "%s: %s\n", binding.Key, binding.Desc)
 	}
 
 	// Show current selection details when idle for better UX
-	if m.state == StateIdle {
-		controls += fmt.Sprintf("\nCurrent: %s (%v)\n", preset.Name, preset.Duration)
+	if !m.presetsSelectable() || m.state != StateIdle {
+		return
+	}
+	if !m.alarmAt.IsZero() {
+		fmt.Fprintf(b, "\nCurrent: alarm at %s\n", m.alarmClock())
+	} else if m.customDuration > 0 {
+		fmt.Fprintf(b, "\nCurrent: custom (%v)\n", m.customDuration)
+	} else {
+		preset := m.currentPreset()
+		d := preset.Duration
+		if steep, ok := m.steepDuration(); ok {
+			d = steep
+		}
+		fmt.Fprintf(b, "\nCurrent: %s (%v)\n", preset.Name, d)
 	}
+}
 
-	// Combine all UI elements into final display
-	ui := status + progress + controls
+// renderPresetList renders the selectable presets, one per line, with the
+// number key that picks each one and a marker on the current selection.
+// Lines are padded to a common width so they stay aligned when centered.
+func (m model) renderPresetList(style lipgloss.Style) string {
+	nameWidth := 0
+	for _, p := range m.config.Presets {
+		nameWidth = max(nameWidth, lipgloss.Width(p.Name))
+	}
 
-	// Center the entire UI in the terminal window
-	return lipgloss.Place(
-		m.width, m.height,
-		lipgloss.Center, lipgloss.Center,
-		ui,
-	)
+	lines := make([]string, len(m.config.Presets))
+	for i, p := range m.config.Presets {
+		marker, number := "  ", "  "
+		if i == m.presetIdx {
+			marker = "› "
+		}
+		if i < 9 {
+			number = fmt.Sprintf("%d ", i+1)
+		}
+		line := fmt.Sprintf("%s%s%s  %6s", marker, number, padRight(p.Name, nameWidth), p.Duration)
+		if i == m.presetIdx {
+			lines[i] = lipgloss.NewStyle().Bold(true).Render(line)
+		} else {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPresetInfo renders the info panel listing a preset's details.
+func renderPresetInfo(info PresetInfo) string {
+	var lines []string
+	for _, field := range []struct{ label, value string }{
+		{"Origin", info.Origin},
+		{"Caffeine", info.Caffeine},
+		{"Flavor", info.Flavor},
+		{"Ratio", info.Ratio},
+	} {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%-9s %s", field.label, field.value))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No details for this tea")
+	}
+	panelStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).Foreground(lipgloss.Color("#888888"))
+	return panelStyle.Render(strings.Join(lines, "\n"))
+}
+
+// progressBarShare is the part of the terminal width the progress bar takes.
+const progressBarShare = 0.4
+
+// progressBarWidth returns the number of characters in the progress bar for
+// a terminal termWidth columns wide: a share of it, within
+// MinProgressBarWidth and MaxProgressBarWidth so it stays readable on a
+// narrow split and doesn't stretch across a wide monitor. The width is part
+// of the view key, so the bar is redrawn on every resize.
+func progressBarWidth(termWidth int) int {
+	if termWidth <= 0 {
+		return DefaultProgressBarWidth
+	}
+	return min(MaxProgressBarWidth, max(MinProgressBarWidth, int(float64(termWidth)*progressBarShare)))
 }
 
 // renderProgressBar renders a visual progress bar with dynamic styling based on timer state.
@@ -93,9 +500,7 @@ func renderProgressBar(total, elapsed time.Duration, width int, state TimerState
 
 	// Calculate progress percentage (clamp between 0 and 1)
 	percent := float64(elapsed) / float64(total)
-	if percent > 1 {
-		percent = 1
-	}
+	percent = min(max(percent, 0), 1)
 
 	// Determine how many characters should be filled in the progress bar
 	filled := int(percent * float64(width))
@@ -129,3 +534,31 @@ func renderProgressBar(total, elapsed time.Duration, width int, state TimerState
 	// Return formatted progress bar with percentage display
 	return fmt.Sprintf("[%s] %.0f%%", bar, percent*100)
 }
+
+// windowTitle returns the terminal window title for the current state: the
+// remaining time while a timer runs, so it can be followed from another tab.
+func (m model) windowTitle() string {
+	remaining := m.config.TimeFormat.Format(m.timer)
+	switch {
+	case m.isFinished():
+		return m.icon("🫖", "") + "Tea ready · go-brew"
+	case m.isBrewing() && m.stopwatch:
+		return m.icon("⏱", "Stopwatch") + remaining + " · go-brew"
+	case m.isBrewing():
+		return m.icon("⏰", "Brewing") + remaining + " · go-brew"
+	case m.isPaused():
+		return m.icon("⏸", "Paused") + remaining + " · go-brew"
+	}
+	return "go-brew"
+}
+
+// syncTitle adds a command updating the terminal window title to cmd when
+// the title has changed since it was last set.
+func (m model) syncTitle(cmd tea.Cmd) (model, tea.Cmd) {
+	title := m.windowTitle()
+	if title == m.title {
+		return m, cmd
+	}
+	m.title = title
+	return m, tea.Batch(cmd, tea.SetWindowTitle(title))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// section renders one section of the timer view on its own.
+func section(m model, write func(model, *strings.Builder)) string {
+	var b strings.Builder
+	write(m, &b)
+	return b.String()
+}
+
+func TestWriteStatus(t *testing.T) {
+	m := initialModel(NewConfig())
+	if out := section(m, model.writeStatus); !strings.Contains(out, "Press 's' to start") || !strings.Contains(out, "🍵 Rooibos") {
+		t.Errorf("Expected the start prompt and preset line when idle, got\n%s", out)
+	}
+
+	m.state = StateFinished
+	m.soundFailed = true
+	m.notifyFailed = true
+	out := section(m, model.writeStatus)
+	if !strings.Contains(out, "Tea Ready!") || !strings.Contains(out, "🔇 sound failed   🔕 notification failed") {
+		t.Errorf("Expected the finished headline and both alert problems, got\n%s", out)
+	}
+	if strings.Contains(out, "🍵") {
+		t.Error("Expected no preset line once the tea is ready")
+	}
+}
+
+func TestWriteProgress(t *testing.T) {
+	m := initialModel(NewConfig())
+	if out := section(m, model.writeProgress); out != "" {
+		t.Errorf("Expected no progress section when idle, got %q", out)
+	}
+
+	m.state = StateBrewing
+	m.brewTea = "Green Tea"
+	m.timer = m.brewDuration() / 2
+	if out := section(m, model.writeProgress); !strings.Contains(out, "50%") || !strings.Contains(out, "Strength") {
+		t.Errorf("Expected the half-way bar and strength, got\n%s", out)
+	}
+
+	m.stopwatch = true
+	m.laps = []time.Duration{time.Minute}
+	if out := section(m, model.writeProgress); strings.Contains(out, "%") || !strings.Contains(out, "Lap 1") {
+		t.Errorf("Expected laps instead of a bar for the stopwatch, got\n%s", out)
+	}
+}
+
+func TestWritePromptAndStatusLine(t *testing.T) {
+	m := initialModel(NewConfig())
+	if out := section(m, model.writePrompt) + section(m, model.writeStatusLine); out != "" {
+		t.Errorf("Expected no prompt or status line by default, got %q", out)
+	}
+	m.digits = "45"
+	if out := section(m, model.writePrompt); !strings.Contains(out, "enter to apply") {
+		t.Errorf("Expected the digit entry hint, got %q", out)
+	}
+	m.status = "Saved"
+	if out := section(m, model.writeStatusLine); !strings.HasPrefix(out, "\n\n") || !strings.Contains(out, "Saved") {
+		t.Errorf("Expected the status line after a blank line, got %q", out)
+	}
+}
+
+func TestWriteControls(t *testing.T) {
+	m := initialModel(NewConfig())
+	out := section(m, model.writeControls)
+	if !strings.HasPrefix(out, "\n\nControls:\n") || !strings.Contains(out, "Current: Rooibos (4m0s)") {
+		t.Errorf("Expected the key help and current preset, got\n%s", out)
+	}
+
+	m.customDuration = 90 * time.Second
+	if out := section(m, model.writeControls); !strings.Contains(out, "Current: custom (1m30s)") {
+		t.Errorf("Expected the custom duration, got\n%s", out)
+	}
+
+	m.state = StateBrewing
+	if out := section(m, model.writeControls); strings.Contains(out, "Current:") {
+		t.Errorf("Expected no selection details while brewing, got\n%s", out)
+	}
+}
+
+func TestProgressBarWidth(t *testing.T) {
+	for _, tc := range []struct{ term, want int }{
+		{0, DefaultProgressBarWidth},
+		{20, MinProgressBarWidth},
+		{80, 32},
+		{100, 40},
+		{300, MaxProgressBarWidth},
+	} {
+		if got := progressBarWidth(tc.term); got != tc.want {
+			t.Errorf("Expected a %d-character bar in %d columns, got %d", tc.want, tc.term, got)
+		}
+	}
+
+	// A resize redraws the bar at the new width
+	m := initialModel(NewConfig())
+	m.state = StateBrewing
+	m.width = 100
+	if out := section(m, model.writeProgress); !strings.Contains(out, "["+strings.Repeat("░", 40)+"]") {
+		t.Errorf("Expected a 40-character bar in 100 columns, got\n%s", out)
+	}
+
+	// More time left than the brew's length draws an empty bar, not a wider one
+	if bar := renderProgressBar(30*time.Second, -210*time.Second, 10, StateBrewing); !strings.Contains(bar, "["+strings.Repeat("░", 10)+"]") || !strings.Contains(bar, " 0%") {
+		t.Errorf("Expected an empty 10-character bar, got %q", bar)
+	}
+}
+
+func TestTooSmall(t *testing.T) {
+	m := initialModel(NewConfig())
+	m.width, m.height = 60, 8
+	if out := m.render(); !strings.Contains(out, "need 40x10, have 60x8") || strings.Contains(out, "Controls") {
+		t.Errorf("Expected a request to enlarge the terminal, got\n%s", out)
+	}
+
+	m.width, m.height = minWidth, minHeight
+	if out := m.render(); strings.Contains(out, "enlarge") {
+		t.Errorf("Expected the timer at exactly the minimum size, got\n%s", out)
+	}
+}